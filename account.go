@@ -2,9 +2,15 @@ package cloudns
 
 import (
 	"context"
+	"errors"
 	"net"
+	"time"
 )
 
+const accountLoginURL = "/dns/login.json"
+const accountCurrentIPURL = "/ip/get-my-ip.json"
+const accountBalanceURL = "/account/get-balance.json"
+
 // AccountService is a service object which groups all operations related to ClouDNS account management
 type AccountService struct {
 	api *Client
@@ -13,7 +19,7 @@ type AccountService struct {
 // Login attempts authentication against the ClouDNS backend with the configured set of credentials.
 // Official Docs: https://www.cloudns.net/wiki/article/45/
 func (svc *AccountService) Login(ctx context.Context) (result StatusResult, err error) {
-	err = svc.api.request(ctx, "POST", "/dns/login.json", nil, nil, &result)
+	err = svc.api.request(ctx, "POST", accountLoginURL, nil, nil, &result)
 	return
 }
 
@@ -24,10 +30,45 @@ func (svc *AccountService) GetCurrentIP(ctx context.Context) (net.IP, error) {
 		IP net.IP `json:"ip"`
 	}
 
-	err := svc.api.request(ctx, "POST", "/ip/get-my-ip.json", nil, nil, &result)
+	err := svc.api.request(ctx, "POST", accountCurrentIPURL, nil, nil, &result)
 	return result.IP, err
 }
 
+// CurrentIPs contains the IPv4 and IPv6 addresses which the ClouDNS API backend sees while connecting to it. Either of
+// them is nil if the API could not be reached using the respective address family, in which case the error of the
+// failed request is stored in IPv4Error or IPv6Error.
+type CurrentIPs struct {
+	IPv4      net.IP
+	IPv6      net.IP
+	IPv4Error error
+	IPv6Error error
+}
+
+// GetCurrentIPs returns both the IPv4 and IPv6 address which the ClouDNS API backend sees while connecting to it, by
+// querying the API once over each address family. The errors of failed requests are returned per address family within
+// the result, and joined as error if neither of both requests succeeded.
+// This requires the HTTP client to use either the default or a *http.Transport, as other transports can not be
+// restricted to a specific address family.
+// Official Docs: https://www.cloudns.net/wiki/article/307/
+func (svc *AccountService) GetCurrentIPs(ctx context.Context) (result CurrentIPs, err error) {
+	api4, err := svc.api.withNetwork("tcp4")
+	if err != nil {
+		return
+	}
+	api6, err := svc.api.withNetwork("tcp6")
+	if err != nil {
+		return
+	}
+
+	result.IPv4, result.IPv4Error = api4.Account.GetCurrentIP(ctx)
+	result.IPv6, result.IPv6Error = api6.Account.GetCurrentIP(ctx)
+	if result.IPv4Error != nil && result.IPv6Error != nil {
+		return result, errors.Join(result.IPv4Error, result.IPv6Error)
+	}
+
+	return result, nil
+}
+
 // GetBalance returns the current account balance / funds for the configured credentials
 // Official Docs: https://www.cloudns.net/wiki/article/354/
 func (svc *AccountService) GetBalance(ctx context.Context) (float64, error) {
@@ -35,6 +76,56 @@ func (svc *AccountService) GetBalance(ctx context.Context) (float64, error) {
 		Funds float64 `json:"funds,string"`
 	}
 
-	err := svc.api.request(ctx, "POST", "/account/get-balance.json", nil, nil, &result)
+	err := svc.api.request(ctx, "POST", accountBalanceURL, nil, nil, &result)
 	return result.Funds, err
 }
+
+// BalanceMonitorOptions specifies how AccountService.MonitorBalance polls the account balance
+type BalanceMonitorOptions struct {
+	// Interval is the time waited between two polls, defaulting to one hour
+	Interval time.Duration
+	// Threshold is the balance below which OnLowBalance gets called
+	Threshold float64
+	// OnLowBalance is called with the current balance once it drops below the threshold. It is only called again after
+	// the balance has recovered to at least the threshold in the meantime.
+	OnLowBalance func(balance float64)
+	// OnError is called for every failed poll, after which monitoring continues with the next poll
+	OnError func(err error)
+}
+
+// MonitorBalance polls the balance of the account until the context is done and reports when the funds drop below the
+// threshold, e.g. for topping up prepaid reseller accounts before they stop renewing domains. It blocks until the
+// context is done and returns the error of the context.
+func (svc *AccountService) MonitorBalance(ctx context.Context, options BalanceMonitorOptions) error {
+	if options.OnLowBalance == nil {
+		return ErrIllegalArgument.wrap(errors.New("low balance callback must not be nil"))
+	}
+	if options.Interval <= 0 {
+		options.Interval = time.Hour
+	}
+
+	isLow := false
+	for {
+		balance, err := svc.GetBalance(ctx)
+		switch {
+		case err != nil:
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if options.OnError != nil {
+				options.OnError(err)
+			}
+		case balance < options.Threshold:
+			if !isLow {
+				options.OnLowBalance(balance)
+			}
+			isLow = true
+		default:
+			isLow = false
+		}
+
+		if err := svc.api.clock.Sleep(ctx, options.Interval); err != nil {
+			return err
+		}
+	}
+}
@@ -1,7 +1,14 @@
 package cloudns
 
 import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/dnaeon/go-vcr.v3/recorder"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestAccountService_Login(t *testing.T) {
@@ -33,3 +40,83 @@ func TestAccountService_GetCurrentIP(t *testing.T) {
 		t.Fatalf("Account.GetCurrentIP() returned error: %v", err)
 	}
 }
+
+func TestAccountService_GetCurrentIPs_CustomTransport(t *testing.T) {
+	client, err := New(HTTPClient(&http.Client{Transport: &recorder.Recorder{}}))
+	assert.NoError(t, err)
+
+	_, err = client.Account.GetCurrentIPs(context.Background())
+	assert.True(t, errors.Is(err, ErrIllegalArgument), "custom transports should not be supported")
+}
+
+func TestAccountService_GetCurrentIPs_PartialFailure(t *testing.T) {
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(`{"ip":"192.0.2.1"}`))
+	}))
+	defer server.Close()
+	client, err := New(BaseURL(server.URL))
+	assert.NoError(t, err)
+
+	// when
+	result, err := client.Account.GetCurrentIPs(context.Background())
+
+	// then
+	assert.NoError(t, err, "single address family should be sufficient")
+	assert.Equal(t, "192.0.2.1", result.IPv4.String())
+	assert.NoError(t, result.IPv4Error)
+	assert.Nil(t, result.IPv6)
+	assert.Error(t, result.IPv6Error, "failed address family should be reported")
+}
+
+func TestClient_WithNetwork(t *testing.T) {
+	client, err := New()
+	assert.NoError(t, err)
+
+	clone, err := client.withNetwork("tcp6")
+	assert.NoError(t, err)
+	assert.NotSame(t, client.httpClient, clone.httpClient, "clone should use separate http client")
+	assert.Same(t, clone, clone.Account.api, "services of clone should use clone")
+	assert.IsType(t, &http.Transport{}, clone.httpClient.Transport)
+	assert.Nil(t, client.httpClient.Transport, "original http client should remain untouched")
+}
+
+func TestAccountService_MonitorBalance(t *testing.T) {
+	// given
+	ctx, cancel := context.WithCancel(context.Background())
+	responses := []string{
+		`{"funds":"50.00"}`,
+		`{"funds":"9.50"}`,
+		`{"funds":"8.00"}`,
+		`{"status":"Failed","statusDescription":"Internal error"}`,
+		`{"funds":"25.00"}`,
+		`{"funds":"5.00"}`,
+	}
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		response := responses[0]
+		if responses = responses[1:]; len(responses) == 0 {
+			cancel()
+		}
+		return response
+	}, CustomClock(NewManualClock(time.Now())))
+
+	// when
+	var lowBalances []float64
+	var pollErrors int
+	err := stubClient.Account.MonitorBalance(ctx, BalanceMonitorOptions{
+		Threshold:    10,
+		OnLowBalance: func(balance float64) { lowBalances = append(lowBalances, balance) },
+		OnError:      func(err error) { pollErrors++ },
+	})
+
+	// then
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []float64{9.5, 5}, lowBalances, "callback should only be invoked when crossing the threshold")
+	assert.Equal(t, 1, pollErrors)
+}
+
+func TestAccountService_MonitorBalance_MissingCallback(t *testing.T) {
+	stubClient := newStubClient(t, func(req *http.Request) string { return `{"funds":"0"}` })
+	err := stubClient.Account.MonitorBalance(context.Background(), BalanceMonitorOptions{Threshold: 10})
+	assert.ErrorIs(t, err, ErrIllegalArgument)
+}
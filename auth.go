@@ -1,5 +1,12 @@
 package cloudns
 
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
 // AuthType is an enumeration of the various ways of authenticating against the ClouDNS API
 type AuthType int
 
@@ -49,8 +56,60 @@ func (auth *Auth) GetParams() HTTPParams {
 	return params
 }
 
+// sensitiveParamKeys contains all parameters which are redacted from a RequestError
+var sensitiveParamKeys = append((&Auth{}).getAllParamKeys(), "transfer-code")
+
 // getAllParamKeys returns all keys involved in authentication, which is being used to filter credentials out of
 // automatically generated test fixtures
 func (auth *Auth) getAllParamKeys() []string {
 	return []string{"auth-id", "sub-auth-id", "sub-auth-user", "auth-password"}
 }
+
+// Environment variables used by AuthFromEnv for reading credentials
+const (
+	EnvAuthID       = "CLOUDNS_AUTH_ID"
+	EnvSubAuthID    = "CLOUDNS_SUB_AUTH_ID"
+	EnvSubAuthUser  = "CLOUDNS_SUB_AUTH_USER"
+	EnvAuthPassword = "CLOUDNS_AUTH_PASSWORD"
+)
+
+// authFromEnv reads the credentials from the environment, ensuring that exactly one kind of credentials is specified
+func authFromEnv() (*Auth, error) {
+	auth := NewAuth()
+	auth.Password = os.Getenv(EnvAuthPassword)
+
+	var names []string
+	for _, name := range []string{EnvAuthID, EnvSubAuthID, EnvSubAuthUser} {
+		if os.Getenv(name) != "" {
+			names = append(names, name)
+		}
+	}
+
+	switch {
+	case len(names) == 0:
+		return nil, ErrMissingCredentials.wrap(fmt.Errorf("none of %s, %s or %s is set", EnvAuthID, EnvSubAuthID,
+			EnvSubAuthUser))
+	case len(names) > 1:
+		return nil, ErrMultipleCredentials.wrap(fmt.Errorf("%s are mutually exclusive", strings.Join(names, ", ")))
+	case auth.Password == "":
+		return nil, ErrMissingCredentials.wrap(fmt.Errorf("%s is not set", EnvAuthPassword))
+	}
+
+	var err error
+	switch value := os.Getenv(names[0]); names[0] {
+	case EnvAuthID:
+		auth.Type = AuthTypeUserID
+		auth.UserID, err = strconv.Atoi(value)
+	case EnvSubAuthID:
+		auth.Type = AuthTypeSubUserID
+		auth.SubUserID, err = strconv.Atoi(value)
+	case EnvSubAuthUser:
+		auth.Type = AuthTypeSubUserName
+		auth.SubUserName = value
+	}
+	if err != nil {
+		return nil, ErrIllegalArgument.wrap(fmt.Errorf("%s must be an integer: %w", names[0], err))
+	}
+
+	return auth, nil
+}
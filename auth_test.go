@@ -80,3 +80,70 @@ func TestAuth_GetParams_Invalid(t *testing.T) {
 		auth.GetParams()
 	}, "should panic with invalid auth type")
 }
+
+func TestAuth_RequireAuth(t *testing.T) {
+	_, err := New(RequireAuth())
+	assert.ErrorIs(t, err, ErrMissingCredentials, "should fail without credentials")
+	assert.ErrorIs(t, err, ErrInvalidOptions, "should fail with invalid options")
+
+	_, err = New(RequireAuth(), AuthUserID(13, "test"))
+	assert.NoError(t, err, "should not fail with credentials")
+
+	_, err = New(AuthSubUserName("hello", "world"), RequireAuth())
+	assert.NoError(t, err, "should not depend on order of options")
+}
+
+func setAuthEnv(t *testing.T, authID, subAuthID, subAuthUser, password string) {
+	t.Setenv(EnvAuthID, authID)
+	t.Setenv(EnvSubAuthID, subAuthID)
+	t.Setenv(EnvSubAuthUser, subAuthUser)
+	t.Setenv(EnvAuthPassword, password)
+}
+
+func TestNewFromEnv(t *testing.T) {
+	// given
+	setAuthEnv(t, "", "", "john", "doe")
+
+	// when
+	client, err := NewFromEnv(RequireAuth())
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, HTTPParams{"sub-auth-user": "john", "auth-password": "doe"}, client.auth.GetParams())
+}
+
+func TestAuthFromEnv(t *testing.T) {
+	setAuthEnv(t, "13", "", "", "test")
+	client, err := New(AuthFromEnv())
+	assert.NoError(t, err)
+	assert.Equal(t, HTTPParams{"auth-id": 13, "auth-password": "test"}, client.auth.GetParams())
+
+	setAuthEnv(t, "", "42", "", "dummy")
+	client, err = New(AuthFromEnv())
+	assert.NoError(t, err)
+	assert.Equal(t, HTTPParams{"sub-auth-id": 42, "auth-password": "dummy"}, client.auth.GetParams())
+}
+
+func TestAuthFromEnv_Invalid(t *testing.T) {
+	setAuthEnv(t, "", "", "", "test")
+	_, err := NewFromEnv()
+	assert.ErrorIs(t, err, ErrMissingCredentials, "should fail without any id")
+	assert.ErrorIs(t, err, ErrInvalidOptions)
+
+	setAuthEnv(t, "13", "", "", "")
+	_, err = NewFromEnv()
+	assert.ErrorIs(t, err, ErrMissingCredentials, "should fail without password")
+
+	setAuthEnv(t, "13", "", "john", "test")
+	_, err = NewFromEnv()
+	assert.ErrorIs(t, err, ErrMultipleCredentials, "should fail with multiple ids")
+	assert.Contains(t, err.Error(), "CLOUDNS_AUTH_ID, CLOUDNS_SUB_AUTH_USER")
+
+	setAuthEnv(t, "", "john", "", "test")
+	_, err = NewFromEnv()
+	assert.ErrorIs(t, err, ErrIllegalArgument, "should fail with non-numeric id")
+
+	setAuthEnv(t, "13", "", "", "test")
+	_, err = NewFromEnv(AuthSubUserName("hello", "world"))
+	assert.ErrorIs(t, err, ErrMultipleCredentials, "should conflict with other auth options")
+}
@@ -0,0 +1,186 @@
+package cloudns
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Cache is a simple key-value store for caching API responses of rarely changing metadata, e.g. available TTLs, record
+// types or nameservers. Expiration is handled by the API client, so implementations only have to store raw values.
+type Cache interface {
+	// Get returns the value stored for the given key and true, or false if no value is present
+	Get(key string) ([]byte, bool)
+	// Set stores the value for the given key, replacing any previous value
+	Set(key string, value []byte)
+}
+
+// MemoryCache is an in-memory Cache, which is shared by all API clients using the same instance
+type MemoryCache struct {
+	mutex  sync.RWMutex
+	values map[string][]byte
+}
+
+// FileCache is a persistent Cache which stores every value as a separate file within a directory. It allows short-lived
+// processes like CLI invocations to benefit from cached metadata across multiple runs.
+type FileCache struct {
+	directory string
+}
+
+// cacheEntry is the envelope stored within a cache, carrying the expiration time next to the raw API response
+type cacheEntry struct {
+	ExpiresAt time.Time       `json:"expiresAt"`
+	Body      json.RawMessage `json:"body"`
+}
+
+// NewMemoryCache instantiates a new empty in-memory cache
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{values: make(map[string][]byte)}
+}
+
+// NewFileCache instantiates a new persistent cache within the given directory, which gets created if missing
+func NewFileCache(directory string) (*FileCache, error) {
+	if err := os.MkdirAll(directory, 0o700); err != nil {
+		return nil, err
+	}
+
+	return &FileCache{directory: directory}, nil
+}
+
+// Get returns the value stored for the given key
+func (cache *MemoryCache) Get(key string) ([]byte, bool) {
+	cache.mutex.RLock()
+	defer cache.mutex.RUnlock()
+
+	value, ok := cache.values[key]
+	return value, ok
+}
+
+// Set stores the value for the given key
+func (cache *MemoryCache) Set(key string, value []byte) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	cache.values[key] = value
+}
+
+// Get returns the value stored for the given key. Unreadable files are treated as missing values.
+func (cache *FileCache) Get(key string) ([]byte, bool) {
+	value, err := os.ReadFile(cache.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	return value, true
+}
+
+// Set stores the value for the given key. The file is replaced atomically, so concurrent processes never observe
+// partially written values. Failures are silently ignored, as they only cause additional API requests.
+func (cache *FileCache) Set(key string, value []byte) {
+	_ = writeFileAtomic(cache.directory, cache.path(key), value)
+}
+
+func (cache *FileCache) path(key string) string {
+	return filepath.Join(cache.directory, key+".json")
+}
+
+// cacheKey returns the cache key for a request against the given endpoint, or an empty string if the endpoint is not
+// cacheable or caching is disabled. Parameters are part of the key, which includes the credentials, as the responses
+// might differ between accounts. Hashing ensures that no credentials get persisted.
+func (c *Client) cacheKey(endpoint string, params map[string]interface{}) string {
+	if c.cache == nil || !c.isCacheable(endpoint) {
+		return ""
+	}
+
+	jsonParams, err := json.Marshal(params)
+	if err != nil {
+		return ""
+	}
+
+	hash := sha256.Sum256(append([]byte(c.baseURL+endpoint+"\n"), jsonParams...))
+	return hex.EncodeToString(hash[:])
+}
+
+func (c *Client) getCachedResponse(key string) ([]byte, bool) {
+	if key == "" {
+		return nil, false
+	}
+
+	value, ok := c.cache.Get(key)
+	if !ok {
+		return nil, false
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(value, &entry); err != nil || !c.clock.Now().Before(entry.ExpiresAt) {
+		return nil, false
+	}
+
+	return entry.Body, true
+}
+
+func (c *Client) putCachedResponse(key string, respBody []byte) {
+	if key == "" || !json.Valid(respBody) {
+		return
+	}
+
+	value, err := json.Marshal(cacheEntry{ExpiresAt: c.clock.Now().Add(c.cacheTTL), Body: respBody})
+	if err != nil {
+		return
+	}
+
+	c.cache.Set(key, value)
+}
+
+// memoizedValue holds the result of an expensive operation for a limited duration. Concurrent callers wait for a single
+// pending operation instead of starting their own.
+type memoizedValue[T any] struct {
+	mutex     sync.Mutex
+	ttl       time.Duration
+	value     T
+	expiresAt time.Time
+}
+
+// get returns the memoized value if it has not expired yet, otherwise it is replaced by the result of the given function.
+// Errors are never memoized.
+func (memo *memoizedValue[T]) get(ctx context.Context, clock Clock, fn func(ctx context.Context) (T, error)) (T, error) {
+	memo.mutex.Lock()
+	defer memo.mutex.Unlock()
+
+	if !forceRefreshFromContext(ctx) && clock.Now().Before(memo.expiresAt) {
+		return memo.value, nil
+	}
+
+	value, err := fn(ctx)
+	if err != nil {
+		return value, err
+	}
+
+	memo.value = value
+	memo.expiresAt = clock.Now().Add(memo.ttl)
+	return value, nil
+}
+
+// writeFileAtomic writes the value into a temporary file within the given directory and renames it to the target path
+func writeFileAtomic(directory, path string, value []byte) error {
+	file, err := os.CreateTemp(directory, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(value)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), path)
+}
@@ -0,0 +1,130 @@
+package cloudns
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMemoryCache(t *testing.T) {
+	cache := NewMemoryCache()
+
+	_, ok := cache.Get("key")
+	assert.False(t, ok, "empty cache should not contain key")
+
+	cache.Set("key", []byte("value"))
+	value, ok := cache.Get("key")
+	assert.True(t, ok, "cache should contain key")
+	assert.Equal(t, []byte("value"), value)
+}
+
+func TestFileCache(t *testing.T) {
+	directory := t.TempDir()
+	cache, err := NewFileCache(directory)
+	assert.NoError(t, err)
+
+	_, ok := cache.Get("key")
+	assert.False(t, ok, "empty cache should not contain key")
+
+	cache.Set("key", []byte("value"))
+	otherCache, err := NewFileCache(directory)
+	assert.NoError(t, err)
+	value, ok := otherCache.Get("key")
+	assert.True(t, ok, "cache should persist key across instances")
+	assert.Equal(t, []byte("value"), value)
+}
+
+func TestClient_ResponseCache(t *testing.T) {
+	// given
+	requestCount := 0
+	clock := NewManualClock(time.Now())
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		requestCount++
+		return `[60, 300, 3600]`
+	}, ResponseCache(NewMemoryCache(), time.Hour), CustomClock(clock))
+
+	// when
+	ttls1, err1 := stubClient.Records.AvailableTTLs(context.Background(), testDomain)
+	ttls2, err2 := stubClient.Records.AvailableTTLs(context.Background(), testDomain)
+	_, err3 := stubClient.Records.AvailableTTLs(context.Background(), "other-"+testDomain)
+
+	// then
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+	assert.NoError(t, err3)
+	assert.Equal(t, []int{60, 300, 3600}, ttls1)
+	assert.Equal(t, ttls1, ttls2, "cached response should match")
+	assert.Equal(t, 2, requestCount, "second request for same zone should be cached")
+
+	// when
+	clock.Advance(time.Hour)
+	_, err := stubClient.Records.AvailableTTLs(context.Background(), testDomain)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 3, requestCount, "expired cache entry should cause new request")
+}
+
+func TestClient_ResponseCache_NonCacheable(t *testing.T) {
+	requestCount := 0
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		requestCount++
+		return `{"name":"api-example.com","type":"master","zone":"domain","status":"1"}`
+	}, ResponseCache(NewMemoryCache(), time.Hour))
+
+	_, _ = stubClient.Zones.Get(context.Background(), testDomain)
+	_, _ = stubClient.Zones.Get(context.Background(), testDomain)
+	assert.Equal(t, 2, requestCount, "zone details should not be cached")
+}
+
+func TestNameserverCacheTTL(t *testing.T) {
+	// given
+	var requestCount int32
+	clock := NewManualClock(time.Now())
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		atomic.AddInt32(&requestCount, 1)
+		return `[{"type":"premium","name":"pns1.cloudns.net","ip4":"185.136.96.66","ip6":"2a06:fb00:1::1:66","location":"Germany"}]`
+	}, NameserverCacheTTL(time.Hour), CustomClock(clock))
+
+	// when
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := stubClient.Zones.AvailableNameservers(context.Background())
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	// then
+	assert.EqualValues(t, 1, requestCount, "concurrent calls should share a single request")
+
+	// when
+	nameservers, err := stubClient.Zones.AvailableNameservers(WithForceRefresh(context.Background()))
+	clock.Advance(time.Hour)
+	_, _ = stubClient.Zones.AvailableNameservers(context.Background())
+
+	// then
+	assert.NoError(t, err)
+	assert.Len(t, nameservers, 1)
+	assert.EqualValues(t, 3, requestCount, "forced refresh and expiry should cause new requests")
+}
+
+func TestClient_ResponseCache_ForceRefresh(t *testing.T) {
+	requestCount := 0
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		requestCount++
+		return `[60, 300, 3600]`
+	}, ResponseCache(NewMemoryCache(), time.Hour))
+
+	_, _ = stubClient.Records.AvailableTTLs(context.Background(), testDomain)
+	_, _ = stubClient.Records.AvailableTTLs(WithForceRefresh(context.Background()), testDomain)
+	_, _ = stubClient.Records.AvailableTTLs(context.Background(), testDomain)
+	assert.Equal(t, 2, requestCount, "forced refresh should bypass but update the cache")
+}
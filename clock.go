@@ -0,0 +1,100 @@
+package cloudns
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Clock provides the current time and the ability to wait for a given duration. It is used by all time-dependent
+// functionality of cloudns-go, which allows consumers to replace it for deterministic unit tests.
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+	// Sleep blocks for the given duration or until the context is done, whichever happens first. The error of the
+	// context is returned in the latter case.
+	Sleep(ctx context.Context, duration time.Duration) error
+}
+
+// ManualClock is a Clock which only advances when being told to. Calls to Sleep return immediately after advancing the
+// clock by the requested duration, so time-dependent code can be tested without actually sleeping.
+type ManualClock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+type systemClock struct{}
+
+// lockedRand wraps a rand.Rand to allow concurrent usage, as the client might be shared by multiple goroutines
+type lockedRand struct {
+	mutex  sync.Mutex
+	random *rand.Rand
+}
+
+// NewManualClock instantiates a new ManualClock which starts at the given time
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+// Now returns the current time of the manual clock
+func (clock *ManualClock) Now() time.Time {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+
+	return clock.now
+}
+
+// Sleep advances the manual clock by the given duration and returns immediately, unless the context is already done
+func (clock *ManualClock) Sleep(ctx context.Context, duration time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	clock.Advance(duration)
+	return nil
+}
+
+// Advance moves the manual clock forward by the given duration
+func (clock *ManualClock) Advance(duration time.Duration) {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+
+	clock.now = clock.now.Add(duration)
+}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) Sleep(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func newLockedRand(source rand.Source) *lockedRand {
+	return &lockedRand{random: rand.New(source)}
+}
+
+// Float64 returns a pseudo-random number in [0.0,1.0)
+func (r *lockedRand) Float64() float64 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.random.Float64()
+}
+
+// Int63n returns a non-negative pseudo-random number in [0,n)
+func (r *lockedRand) Int63n(n int64) int64 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.random.Int63n(n)
+}
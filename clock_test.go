@@ -0,0 +1,57 @@
+package cloudns
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestManualClock(t *testing.T) {
+	// given
+	start := time.Date(2022, 12, 24, 0, 0, 0, 0, time.UTC)
+	clock := NewManualClock(start)
+
+	// when
+	clock.Advance(time.Minute)
+	err := clock.Sleep(context.Background(), time.Hour)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, start.Add(time.Hour+time.Minute), clock.Now(), "clock should have advanced")
+}
+
+func TestManualClock_Sleep_Cancelled(t *testing.T) {
+	// given
+	start := time.Date(2022, 12, 24, 0, 0, 0, 0, time.UTC)
+	clock := NewManualClock(start)
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// when
+	err := clock.Sleep(cancelledCtx, time.Hour)
+
+	// then
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, start, clock.Now(), "clock should not have advanced")
+}
+
+func TestSystemClock_Sleep_Cancelled(t *testing.T) {
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := systemClock{}.Sleep(cancelledCtx, time.Hour)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestOptions_ClockAndRandomSource(t *testing.T) {
+	clock := NewManualClock(time.Now())
+	client1, err := New(CustomClock(clock), RandomSource(rand.NewSource(42)))
+	assert.NoError(t, err)
+	client2, err := New(RandomSource(rand.NewSource(42)))
+	assert.NoError(t, err)
+
+	assert.Same(t, clock, client1.clock, "custom clock should be used")
+	assert.Equal(t, client1.random.Int63n(1000), client2.random.Int63n(1000), "same seed should yield same values")
+}
@@ -7,20 +7,29 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"math/rand"
+	"net"
 	"net/http"
-	"net/url"
+	"reflect"
+	"time"
 )
 
 // HTTPParams represents a map with string keys and a freely-chosen type. It is used to collect either GET or POST
-// parameters for the ClouDNS API.
+// parameters for the ClouDNS API. Slices and IndexedParam values are sent as parameters with multiple values.
 type HTTPParams map[string]interface{}
 
 // Client provides the main object for interacting with the ClouDNS API. All service objects and settings are being
 // stored underneath within this structure.
 type Client struct {
-	Account *AccountService
-	Zones   *ZoneService
-	Records *RecordService
+	Account      *AccountService
+	Zones        *ZoneService
+	Records      *RecordService
+	DNSSEC       *DNSSECService
+	Failover     *FailoverService
+	MailForwards *MailForwardService
+	Domains      *DomainService
+	SSL          *SSLService
 
 	baseURL    string
 	userAgent  string
@@ -28,6 +37,30 @@ type Client struct {
 	headers    http.Header
 	params     HTTPParams
 	httpClient *http.Client
+	clock      Clock
+	random     *lockedRand
+	cache      Cache
+	cacheTTL   time.Duration
+
+	rateLimiter      RateLimiter
+	retryPolicy      *RetryPolicy
+	statusTranslator func(description string) string
+	nameservers      *memoizedValue[[]Nameserver]
+	idempotency      *idempotencyTracker
+	schemaDrift      *SchemaDriftDetector
+	freezer          *zoneFreezer
+	ttlPolicy        *TTLPolicy
+	logger           *slog.Logger
+	metrics          MetricsRecorder
+	dnsPort          int
+
+	recordNormalizers []RecordNormalizer
+
+	requireAuth         bool
+	readOnly            bool
+	dryRun              bool
+	classifiedEndpoints map[string]Endpoint
+	paramEncoders       map[reflect.Type]func(value interface{}) string
 }
 
 // StatusResult is a common result used by all ClouDNS API methods for either
@@ -41,47 +74,184 @@ type StatusResult struct {
 func New(options ...Option) (*Client, error) {
 	client := &Client{
 		baseURL:   "https://api.cloudns.net",
-		userAgent: "cloudns-go",
+		userAgent: defaultUserAgent(),
 
 		auth:       NewAuth(),
 		headers:    make(http.Header),
 		params:     make(HTTPParams),
 		httpClient: http.DefaultClient,
+		dnsPort:    53,
+		clock:      systemClock{},
+		random:     newLockedRand(rand.NewSource(time.Now().UnixNano())),
+		freezer:    newZoneFreezer(),
 	}
 
 	if err := client.processOptions(options...); err != nil {
 		return nil, ErrInvalidOptions.wrap(err)
 	}
 
-	client.Account = &AccountService{api: client}
-	client.Zones = &ZoneService{api: client}
-	client.Records = &RecordService{api: client}
+	client.initServices()
 
 	return client, nil
 }
 
+// NewFromEnv instantiates a new ClouDNS client authenticated with the credentials of the environment, see AuthFromEnv.
+// Additional options are applied afterwards, e.g. for configuring retries or a custom HTTP client.
+func NewFromEnv(options ...Option) (*Client, error) {
+	return New(append([]Option{AuthFromEnv()}, options...)...)
+}
+
+func (c *Client) initServices() {
+	c.Account = &AccountService{api: c}
+	c.Zones = &ZoneService{api: c}
+	c.Records = &RecordService{api: c}
+	c.DNSSEC = &DNSSECService{api: c}
+	c.Failover = &FailoverService{api: c}
+	c.MailForwards = &MailForwardService{api: c}
+	c.Domains = &DomainService{api: c}
+	c.SSL = &SSLService{api: c}
+}
+
+// processOptions applies all given options and validates the resulting configuration afterwards. Instead of failing on
+// the first error, all errors of invalid options and conflicting combinations are joined, so that every misconfiguration
+// gets reported at once while each of them can still be matched with errors.Is.
 func (c *Client) processOptions(options ...Option) error {
+	var errs []error
 	for _, option := range options {
 		if err := option(c); err != nil {
-			return err
+			errs = append(errs, err)
 		}
 	}
 
-	return nil
+	errs = append(errs, c.validateOptions()...)
+	return errors.Join(errs...)
+}
+
+// validateOptions checks the configuration of the client for missing values and conflicting options, which can only be
+// detected once all options have been applied
+func (c *Client) validateOptions() []error {
+	var errs []error
+	if c.requireAuth && c.auth.Type == AuthTypeNone {
+		errs = append(errs, ErrMissingCredentials)
+	}
+	if c.httpClient == nil {
+		errs = append(errs, ErrIllegalArgument.wrap(errors.New("http client must not be nil")))
+	}
+	if c.clock == nil {
+		errs = append(errs, ErrIllegalArgument.wrap(errors.New("clock must not be nil")))
+	}
+	if c.cache != nil && c.cacheTTL <= 0 {
+		errs = append(errs, ErrIllegalArgument.wrap(fmt.Errorf("response cache requires a positive ttl, got %s", c.cacheTTL)))
+	}
+	if c.readOnly && c.dryRun {
+		errs = append(errs, ErrIllegalArgument.wrap(errors.New("ReadOnly and DryRun can not be combined, as mutating "+
+			"requests would either fail or succeed without being sent")))
+	}
+	if c.rateLimiter != nil && c.httpClient != nil {
+		if _, ok := c.httpClient.Transport.(RateLimiter); ok {
+			errs = append(errs, ErrIllegalArgument.wrap(errors.New("RateLimit can not be combined with an HTTPClient "+
+				"whose transport already implements RateLimiter, as requests would be limited twice")))
+		}
+	}
+
+	return errs
+}
+
+// withNetwork returns a shallow copy of the client which only connects to the API using the given network, e.g. `tcp4`
+// or `tcp6`. This requires the underlying HTTP client to use either the default or a custom *http.Transport.
+func (c *Client) withNetwork(network string) (*Client, error) {
+	var transport *http.Transport
+	switch t := c.httpClient.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = t.Clone()
+	default:
+		return nil, ErrIllegalArgument.wrap(fmt.Errorf("can not restrict http transport of type %T to network %s", t, network))
+	}
+
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	transport.DialContext = func(ctx context.Context, _, address string) (net.Conn, error) {
+		return dialer.DialContext(ctx, network, address)
+	}
+
+	httpClient := *c.httpClient
+	httpClient.Transport = transport
+
+	clone := *c
+	clone.httpClient = &httpClient
+	clone.initServices()
+
+	return &clone, nil
+}
+
+// Call invokes an arbitrary API endpoint using POST, e.g. one which is not wrapped by cloudns-go yet, and decodes the
+// response into the given target. The request is handled like all other requests of the client, using the
+// classification registered with ClassifyEndpoints for retries, caching and dry runs. Unknown endpoints are treated as
+// mutating.
+func (c *Client) Call(ctx context.Context, path string, params HTTPParams, target interface{}) error {
+	return c.request(ctx, "POST", path, params, nil, target)
 }
 
 func (c *Client) request(ctx context.Context, method, endpoint string, params HTTPParams, headers http.Header, target interface{}) error {
-	req, err := c.makeRequest(ctx, method, endpoint, params, headers)
+	err := c.doAPIRequest(ctx, method, endpoint, params, headers, target)
 	if err != nil {
+		err = newRequestError(method, endpoint, c.mergeParams(ctx, params), err)
+	}
+	if requestID := RequestIDFromContext(ctx); err != nil && requestID != "" {
+		return requestIDError{requestID: requestID, inner: err}
+	}
+
+	return err
+}
+
+func (c *Client) doAPIRequest(ctx context.Context, method, endpoint string, params HTTPParams, headers http.Header, target interface{}) error {
+	if c.readOnly && c.isMutating(endpoint) {
+		return ErrReadOnlyClient.wrap(fmt.Errorf("refusing to invoke mutating endpoint %s", endpoint))
+	}
+	if err := c.checkFrozen(endpoint, params); err != nil {
 		return err
 	}
+	if c.isDryRun(ctx) && c.isMutating(endpoint) {
+		return decodeResponse([]byte(dryRunResponse), target)
+	}
+
+	mergedParams := c.mergeParams(ctx, params)
+	cacheKey := c.cacheKey(endpoint, mergedParams)
+	if respBody, ok := c.getCachedResponse(cacheKey); ok && !forceRefreshFromContext(ctx) {
+		return decodeResponse(respBody, target)
+	}
+
+	idempotencyKey := c.idempotencyKey(endpoint, mergedParams)
+	if respBody, pending, ok := c.idempotency.lookup(idempotencyKey, c.clock.Now()); ok && !pending {
+		return decodeResponse(respBody, target)
+	} else if pending {
+		respBody, found, err := c.findCreatedRecord(ctx, endpoint, mergedParams)
+		if err != nil {
+			return err
+		} else if found {
+			c.idempotency.record(idempotencyKey, mergedParams, c.clock.Now(), respBody)
+			return decodeResponse(respBody, target)
+		}
+	}
 
-	_, err = c.doRequest(req, target)
+	respBody, err := c.sendWithRetries(ctx, method, endpoint, params, headers)
 	if err != nil {
+		if c.idempotency != nil && isAmbiguousError(err) {
+			c.idempotency.recordPending(idempotencyKey, mergedParams, c.clock.Now())
+		}
 		return err
 	}
 
-	return nil
+	c.putCachedResponse(cacheKey, respBody)
+	if c.idempotency != nil && c.isMutating(endpoint) {
+		c.idempotency.record(idempotencyKey, mergedParams, c.clock.Now(), respBody)
+	}
+	if c.schemaDrift != nil {
+		c.schemaDrift.inspect(endpoint, respBody, target)
+	}
+
+	return decodeResponse(respBody, target)
 }
 
 func (c *Client) makeRequest(ctx context.Context, method, endpoint string, params HTTPParams, headers http.Header) (*http.Request, error) {
@@ -97,19 +267,13 @@ func (c *Client) makeRequest(ctx context.Context, method, endpoint string, param
 	req.Header = mergedHeaders
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("User-Agent", c.userAgent)
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		req.Header.Set(requestIDHeader, requestID)
+	}
 
-	mergedParams := make(map[string]interface{})
-	copyParams(mergedParams, c.params)
-	copyParams(mergedParams, c.auth.GetParams())
-	copyParams(mergedParams, params)
-
+	mergedParams := c.mergeParams(ctx, params)
 	if containsString(method, []string{"HEAD", "GET", "DELETE"}) {
-		queryValues := make(url.Values)
-		for key, value := range mergedParams {
-			queryValues.Set(key, fmt.Sprintf("%s", value))
-		}
-
-		req.URL.RawQuery = queryValues.Encode()
+		req.URL.RawQuery = encodeQueryParams(mergedParams).Encode()
 	} else {
 		jsonBody, err := json.Marshal(mergedParams)
 		if err != nil {
@@ -123,13 +287,40 @@ func (c *Client) makeRequest(ctx context.Context, method, endpoint string, param
 	return req, nil
 }
 
-func (c *Client) doRequest(req *http.Request, target interface{}) (*http.Response, error) {
+// mergeParams combines all parameters for a request, with later ones taking precedence in the following order:
+//  1. client-wide parameters specified with the Params option, except for those excluded with WithoutParams
+//  2. parameters attached to the context with WithParams
+//  3. authentication parameters
+//  4. method-specific parameters
+func (c *Client) mergeParams(ctx context.Context, params HTTPParams) map[string]interface{} {
+	mergedParams := make(map[string]interface{})
+	copyParams(mergedParams, c.params)
+	for _, key := range excludedParamsFromContext(ctx) {
+		delete(mergedParams, key)
+	}
+
+	copyParams(mergedParams, paramsFromContext(ctx))
+	copyParams(mergedParams, c.auth.GetParams())
+	copyParams(mergedParams, params)
+	c.encodeParams(mergedParams)
+
+	return mergedParams
+}
+
+func (c *Client) doRequest(req *http.Request) ([]byte, error) {
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, ErrAPIInvocation.wrap(newAPIError("Failed", "Too many requests"))
+	}
+	if resp.StatusCode >= 500 {
+		return nil, ErrHTTPRequest.wrap(httpStatusError{statusCode: resp.StatusCode})
+	}
+
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, ErrHTTPRequest.wrap(err)
@@ -138,13 +329,37 @@ func (c *Client) doRequest(req *http.Request, target interface{}) (*http.Respons
 		return nil, err
 	}
 
-	if target != nil {
-		if err := json.Unmarshal(respBody, target); err != nil {
-			return nil, ErrHTTPRequest.wrap(err)
+	return respBody, nil
+}
+
+// decodeResponse unmarshals the response body into the given target. ClouDNS returns an empty JSON array instead of an
+// empty JSON object for various endpoints, which is why empty arrays are decoded as empty maps for all map targets.
+func decodeResponse(respBody []byte, target interface{}) error {
+	if target == nil {
+		return nil
+	}
+
+	if isEmptyJSONArray(respBody) {
+		if value := reflect.ValueOf(target); value.Kind() == reflect.Pointer && value.Elem().Kind() == reflect.Map {
+			value.Elem().Set(reflect.MakeMap(value.Elem().Type()))
+			return nil
 		}
 	}
 
-	return resp, nil
+	if err := json.Unmarshal(respBody, target); err != nil {
+		return ErrHTTPRequest.wrap(err)
+	}
+
+	return nil
+}
+
+func isEmptyJSONArray(data []byte) bool {
+	data = bytes.TrimSpace(data)
+	if len(data) < 2 || data[0] != '[' || data[len(data)-1] != ']' {
+		return false
+	}
+
+	return len(bytes.TrimSpace(data[1:len(data)-1])) == 0
 }
 
 func (c *Client) checkBaseResult(respBody []byte) error {
@@ -166,17 +381,34 @@ func (c *Client) checkBaseResult(respBody []byte) error {
 
 		// Return an API error in all other cases, based on either `StatusDescription` or `StatusMessage`
 		if result.StatusDescription != "" {
-			return ErrAPIInvocation.wrap(errors.New(result.StatusDescription))
+			return ErrAPIInvocation.wrap(c.newAPIError(result.Status, result.StatusDescription))
 		} else if result.StatusMessage != "" {
-			return ErrAPIInvocation.wrap(errors.New(result.StatusMessage))
+			return ErrAPIInvocation.wrap(c.newAPIError(result.Status, result.StatusMessage))
 		} else {
-			return ErrAPIInvocation.wrap(errors.New(string(respBody)))
+			return ErrAPIInvocation.wrap(c.newAPIError(result.Status, string(respBody)))
 		}
 	}
 
 	return nil
 }
 
+// newAPIError creates an APIError, translating the description beforehand if a translator has been configured, so
+// that structured information can be extracted from localized descriptions as well
+func (c *Client) newAPIError(status, description string) *APIError {
+	if c.statusTranslator == nil {
+		return newAPIError(status, description)
+	}
+
+	translated := c.statusTranslator(description)
+	if translated == "" || translated == description {
+		return newAPIError(status, description)
+	}
+
+	err := newAPIError(status, translated)
+	err.LocalizedDescription = description
+	return err
+}
+
 func copyHeaders(target, source http.Header) {
 	if source == nil {
 		return
@@ -3,15 +3,18 @@ package cloudns
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/stretchr/testify/assert"
 	"gopkg.in/dnaeon/go-vcr.v3/cassette"
 	"gopkg.in/dnaeon/go-vcr.v3/recorder"
+	"io"
 	"log"
 	"net/http"
 	"os"
-	"strconv"
 	"strings"
 	"testing"
+	"time"
 )
 
 const testDomain string = "api-example.com"
@@ -67,19 +70,13 @@ func setup(t *testing.T) func() {
 }
 
 func buildAuthFromEnv() Option {
-	if os.Getenv("CLOUDNS_USER_ID") == "" || os.Getenv("CLOUDNS_PASSWORD") == "" {
+	if os.Getenv(EnvAuthPassword) == "" {
 		return func(api *Client) error {
 			return nil
 		}
 	}
 
-	userPassword := os.Getenv("CLOUDNS_PASSWORD")
-	userID, err := strconv.Atoi(os.Getenv("CLOUDNS_USER_ID"))
-	if err != nil {
-		log.Fatalf("could not convert CLOUDNS_USER_ID to integer: %v", err)
-	}
-
-	return AuthUserID(userID, userPassword)
+	return AuthFromEnv()
 }
 
 func filterCookies(i *cassette.Interaction) error {
@@ -137,3 +134,144 @@ func filterZoneListing(i *cassette.Interaction) error {
 	i.Response.Body = string(jsonBody)
 	return nil
 }
+
+// stubTransport is a http.RoundTripper which passes all requests to a function, used for unit testing client behavior
+// without requiring recorded test fixtures
+type stubTransport func(req *http.Request) (*http.Response, error)
+
+func (fn stubTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
+
+func newStubResponse(body string) *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func newStubClient(t *testing.T, handler func(req *http.Request) string, options ...Option) *Client {
+	transport := stubTransport(func(req *http.Request) (*http.Response, error) {
+		return newStubResponse(handler(req)), nil
+	})
+
+	stubClient, err := New(append([]Option{HTTPClient(&http.Client{Transport: transport})}, options...)...)
+	if err != nil {
+		t.Fatalf("could not create stub client: %v", err)
+	}
+
+	return stubClient
+}
+
+func TestDecodeResponse_EmptyArray(t *testing.T) {
+	var records RecordMap
+	assert.NoError(t, decodeResponse([]byte(" [ ]\n"), &records))
+	assert.NotNil(t, records, "empty array should be decoded as empty map")
+	assert.Empty(t, records)
+
+	var servers map[string]MasterServer
+	assert.NoError(t, decodeResponse([]byte("[]"), &servers))
+	assert.NotNil(t, servers, "empty array should be decoded as empty map")
+
+	var ttls []int
+	assert.NoError(t, decodeResponse([]byte("[]"), &ttls))
+	assert.Empty(t, ttls)
+
+	assert.ErrorIs(t, decodeResponse([]byte("[1]"), &records), ErrHTTPRequest, "non-empty arrays should still fail")
+}
+
+func TestClient_ReadOnly(t *testing.T) {
+	// given
+	var requests []string
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		requests = append(requests, req.URL.Path)
+		if req.URL.Path == recordListURL {
+			return `[]`
+		}
+		return `{"status":"Success"}`
+	}, ReadOnly())
+
+	// when
+	_, listErr := stubClient.Records.List(context.Background(), testDomain)
+	_, createErr := stubClient.Records.Create(context.Background(), testDomain, NewRecordA("www", "192.0.2.1", testTTL))
+	_, renewErr := stubClient.Domains.Renew(context.Background(), testDomain, 1)
+
+	// then
+	assert.NoError(t, listErr, "read-only calls should not be affected")
+	assert.ErrorIs(t, createErr, ErrReadOnlyClient)
+	assert.ErrorIs(t, renewErr, ErrReadOnlyClient)
+	assert.Equal(t, []string{recordListURL}, requests, "mutating calls should not reach api")
+}
+
+func TestClient_ReadOnly_AllMutatingEndpoints(t *testing.T) {
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		t.Errorf("unexpected request to %s", req.URL.Path)
+		return `{"status":"Success"}`
+	}, ReadOnly())
+
+	for _, endpoint := range Endpoints() {
+		if !endpoint.Mutating {
+			continue
+		}
+
+		var result StatusResult
+		err := stubClient.request(context.Background(), endpoint.Method, endpoint.Path, HTTPParams{}, nil, &result)
+		assert.ErrorIs(t, err, ErrReadOnlyClient, "%s.%s should be rejected", endpoint.Service, endpoint.Name)
+	}
+}
+
+func TestNew_ReportsAllInvalidOptions(t *testing.T) {
+	// when
+	_, err := New(
+		RequireAuth(),
+		Retries(RetryPolicy{}),
+		AuthUserID(13, "test"),
+		AuthSubUserID(37, "test"),
+		HTTPClient(nil),
+		ResponseCache(NewMemoryCache(), 0),
+		DNSPort(0),
+	)
+
+	// then
+	assert.ErrorIs(t, err, ErrInvalidOptions)
+	assert.ErrorIs(t, err, ErrIllegalArgument)
+	assert.ErrorIs(t, err, ErrMultipleCredentials)
+	assert.Contains(t, err.Error(), "retry policy requires at least one attempt")
+	assert.Contains(t, err.Error(), "http client must not be nil")
+	assert.Contains(t, err.Error(), "response cache requires a positive ttl")
+	assert.Contains(t, err.Error(), "invalid dns port: 0")
+	assert.False(t, errors.Is(err, ErrMissingCredentials))
+}
+
+// limitingTransport is an http.RoundTripper which also implements RateLimiter, as done by transport-level limiters
+type limitingTransport struct {
+	stubTransport
+}
+
+func (limitingTransport) Take(context.Context, string) (time.Duration, error) {
+	return 0, nil
+}
+
+func TestNew_ReportsConflictingOptions(t *testing.T) {
+	// given
+	limiter, err := NewTokenBucket(1, 1, nil)
+	assert.NoError(t, err)
+
+	// when
+	_, err = New(
+		ReadOnly(),
+		DryRun(),
+		RateLimit(limiter),
+		HTTPClient(&http.Client{Transport: limitingTransport{}}),
+		AuthUserID(13, "test"),
+		AuthSubUserID(37, "test"),
+	)
+
+	// then
+	assert.ErrorIs(t, err, ErrInvalidOptions)
+	assert.ErrorIs(t, err, ErrIllegalArgument)
+	assert.ErrorIs(t, err, ErrMultipleCredentials)
+	assert.Contains(t, err.Error(), "ReadOnly and DryRun can not be combined")
+	assert.Contains(t, err.Error(), "RateLimit can not be combined with an HTTPClient")
+}
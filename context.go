@@ -0,0 +1,93 @@
+package cloudns
+
+import (
+	"context"
+)
+
+// contextKey is an unexported type for keys of context values defined by cloudns-go, which avoids collisions with
+// context keys defined in other packages
+type contextKey int
+
+const (
+	contextKeyParams contextKey = iota
+	contextKeyExcludedParams
+	contextKeyRequestID
+	contextKeyForceRefresh
+	contextKeyDryRun
+)
+
+// requestIDHeader is the name of the HTTP header which carries the request ID specified with WithRequestID
+const requestIDHeader = "X-Request-ID"
+
+// WithParams returns a copy of the context which causes all API requests using it to send the given parameters. These
+// override the client-wide parameters specified with the Params option, but are overridden by the authentication and
+// method-specific parameters. Calling WithParams multiple times merges all given parameters.
+func WithParams(ctx context.Context, params HTTPParams) context.Context {
+	mergedParams := make(HTTPParams)
+	copyParams(mergedParams, paramsFromContext(ctx))
+	copyParams(mergedParams, params)
+
+	return context.WithValue(ctx, contextKeyParams, mergedParams)
+}
+
+// WithoutParams returns a copy of the context which causes all API requests using it to omit the client-wide parameters
+// with the given keys, as specified with the Params option. Authentication and method-specific parameters can not be
+// excluded.
+func WithoutParams(ctx context.Context, keys ...string) context.Context {
+	excludedKeys := append(excludedParamsFromContext(ctx), keys...)
+	return context.WithValue(ctx, contextKeyExcludedParams, excludedKeys)
+}
+
+// WithRequestID returns a copy of the context which annotates all API requests using it with the given request ID. The
+// ID is sent as X-Request-ID header and included in all errors returned by these requests, so that API calls can be
+// correlated with the higher-level operation which triggered them.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, contextKeyRequestID, requestID)
+}
+
+// RequestIDFromContext returns the request ID attached to the context with WithRequestID, or an empty string if none
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(contextKeyRequestID).(string)
+	return requestID
+}
+
+// WithForceRefresh returns a copy of the context which causes all API requests using it to bypass cached responses and
+// memoized results, e.g. of AvailableNameservers. Fresh responses are still stored in the caches.
+func WithForceRefresh(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextKeyForceRefresh, true)
+}
+
+// WithDryRun returns a copy of the context which causes all API requests using it against mutating endpoints to succeed
+// without being sent, while read-only requests are still sent. This allows previewing the requests of higher-level
+// operations, but methods relying on the effects of earlier requests might behave differently than without a dry run.
+func WithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextKeyDryRun, true)
+}
+
+// dryRunResponse is returned for all requests against mutating endpoints during a dry run
+const dryRunResponse = `{"status":"Success","statusDescription":"Dry run, request has not been sent"}`
+
+func dryRunFromContext(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(contextKeyDryRun).(bool)
+	return dryRun
+}
+
+// isDryRun returns true if either the client has been configured with DryRun or the context with WithDryRun
+func (c *Client) isDryRun(ctx context.Context) bool {
+	return c.dryRun || dryRunFromContext(ctx)
+}
+
+func forceRefreshFromContext(ctx context.Context) bool {
+	forceRefresh, _ := ctx.Value(contextKeyForceRefresh).(bool)
+	return forceRefresh
+}
+
+func paramsFromContext(ctx context.Context) HTTPParams {
+	params, _ := ctx.Value(contextKeyParams).(HTTPParams)
+	return params
+}
+
+func excludedParamsFromContext(ctx context.Context) []string {
+	keys, _ := ctx.Value(contextKeyExcludedParams).([]string)
+	return append([]string(nil), keys...)
+}
@@ -0,0 +1,61 @@
+package cloudns
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestClient_MergeParams(t *testing.T) {
+	// given
+	client, err := New(
+		AuthUserID(13, "test"),
+		Params(HTTPParams{"group-id": 1, "rows-per-page": 10, "auth-id": 42}),
+	)
+	assert.NoError(t, err)
+
+	// when
+	baseCtx := context.Background()
+	defaultParams := client.mergeParams(baseCtx, HTTPParams{"domain-name": testDomain})
+	overrideParams := client.mergeParams(WithParams(baseCtx, HTTPParams{"group-id": 2, "auth-id": 7}), nil)
+	excludeParams := client.mergeParams(WithoutParams(baseCtx, "group-id", "auth-id"), nil)
+
+	// then
+	assert.Equal(t, map[string]interface{}{
+		"group-id": 1, "rows-per-page": 10, "auth-id": 13, "auth-password": "test", "domain-name": testDomain,
+	}, defaultParams, "client-wide params should be overridden by auth")
+	assert.Equal(t, 2, overrideParams["group-id"], "context params should override client-wide params")
+	assert.Equal(t, 13, overrideParams["auth-id"], "context params should not override auth")
+	assert.NotContains(t, excludeParams, "group-id", "excluded params should be omitted")
+	assert.Equal(t, 13, excludeParams["auth-id"], "auth params should not be excluded")
+}
+
+func TestWithParams_Merge(t *testing.T) {
+	ctx := WithParams(context.Background(), HTTPParams{"a": 1, "b": 2})
+	ctx = WithParams(ctx, HTTPParams{"b": 3})
+	ctx = WithoutParams(ctx, "c")
+	ctx = WithoutParams(ctx, "d")
+
+	assert.Equal(t, HTTPParams{"a": 1, "b": 3}, paramsFromContext(ctx))
+	assert.Equal(t, []string{"c", "d"}, excludedParamsFromContext(ctx))
+}
+
+func TestWithRequestID(t *testing.T) {
+	// given
+	var receivedID string
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		receivedID = req.Header.Get("X-Request-ID")
+		return `{"status":"Failed","statusDescription":"Invalid authentication"}`
+	})
+
+	// when
+	ctx := WithRequestID(context.Background(), "sync-42")
+	_, err := stubClient.Account.Login(ctx)
+
+	// then
+	assert.Equal(t, "sync-42", RequestIDFromContext(ctx))
+	assert.Equal(t, "sync-42", receivedID, "request id should be sent as header")
+	assert.ErrorIs(t, err, ErrAPIInvocation, "error should still be classified")
+	assert.Contains(t, err.Error(), "(request id: sync-42)", "error should contain request id")
+}
@@ -0,0 +1,196 @@
+package cloudns
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// SchemaDriftKind is an enumeration of the kinds of differences detected between API responses and result types
+type SchemaDriftKind int
+
+// Enumeration values for SchemaDriftKind
+const (
+	// SchemaDriftUnmapped indicates a JSON field in an API response without corresponding struct field
+	SchemaDriftUnmapped SchemaDriftKind = iota
+	// SchemaDriftMissing indicates a struct field which was not present in the JSON object of an API response
+	SchemaDriftMissing
+)
+
+// SchemaDrift represents a single difference between the JSON response of an API endpoint and its result type
+type SchemaDrift struct {
+	Endpoint string
+	Kind     SchemaDriftKind
+	// Path points to the JSON object containing the field, e.g. `$` for the top-level object or `$.*` for the values
+	// of a top-level map
+	Path  string
+	Field string
+}
+
+// SchemaDriftDetector collects differences between the JSON responses of the ClouDNS API and the structs they are
+// decoded into, which helps noticing new or deprecated fields. Each difference is only recorded and logged once.
+type SchemaDriftDetector struct {
+	mutex  sync.Mutex
+	logf   func(format string, args ...interface{})
+	drifts map[SchemaDrift]struct{}
+}
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// NewSchemaDriftDetector instantiates a new SchemaDriftDetector, which reports each newly detected difference using
+// the given logging function, e.g. log.Printf. The logging function may be nil.
+func NewSchemaDriftDetector(logf func(format string, args ...interface{})) *SchemaDriftDetector {
+	return &SchemaDriftDetector{logf: logf, drifts: make(map[SchemaDrift]struct{})}
+}
+
+// String returns a human-readable name of the drift kind
+func (kind SchemaDriftKind) String() string {
+	switch kind {
+	case SchemaDriftUnmapped:
+		return "unmapped"
+	case SchemaDriftMissing:
+		return "missing"
+	}
+
+	return "unknown"
+}
+
+// Report returns all differences detected so far, sorted by endpoint, path and field
+func (detector *SchemaDriftDetector) Report() []SchemaDrift {
+	detector.mutex.Lock()
+	defer detector.mutex.Unlock()
+
+	results := make([]SchemaDrift, 0, len(detector.drifts))
+	for drift := range detector.drifts {
+		results = append(results, drift)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Endpoint != b.Endpoint {
+			return a.Endpoint < b.Endpoint
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		if a.Field != b.Field {
+			return a.Field < b.Field
+		}
+		return a.Kind < b.Kind
+	})
+
+	return results
+}
+
+// inspect compares the given response body with the type of the target it has been decoded into
+func (detector *SchemaDriftDetector) inspect(endpoint string, respBody []byte, target interface{}) {
+	var data interface{}
+	if target == nil || json.Unmarshal(respBody, &data) != nil {
+		return
+	}
+
+	detector.compare(endpoint, "$", data, reflect.TypeOf(target))
+}
+
+func (detector *SchemaDriftDetector) compare(endpoint, path string, data interface{}, typ reflect.Type) {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ.Implements(jsonUnmarshalerType) || reflect.PointerTo(typ).Implements(jsonUnmarshalerType) {
+		return
+	}
+
+	switch value := data.(type) {
+	case map[string]interface{}:
+		switch typ.Kind() {
+		case reflect.Struct:
+			fields := jsonFields(typ)
+			for key, fieldValue := range value {
+				field, ok := fields[strings.ToLower(key)]
+				if !ok {
+					detector.record(SchemaDrift{Endpoint: endpoint, Kind: SchemaDriftUnmapped, Path: path, Field: key})
+					continue
+				}
+				detector.compare(endpoint, path+"."+field.name, fieldValue, field.typ)
+			}
+			for _, field := range fields {
+				if !hasKeyFold(value, field.name) {
+					detector.record(SchemaDrift{Endpoint: endpoint, Kind: SchemaDriftMissing, Path: path, Field: field.name})
+				}
+			}
+		case reflect.Map:
+			for _, mapValue := range value {
+				detector.compare(endpoint, path+".*", mapValue, typ.Elem())
+			}
+		}
+	case []interface{}:
+		if typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array {
+			for _, element := range value {
+				detector.compare(endpoint, path+"[*]", element, typ.Elem())
+			}
+		}
+	}
+}
+
+func (detector *SchemaDriftDetector) record(drift SchemaDrift) {
+	detector.mutex.Lock()
+	defer detector.mutex.Unlock()
+
+	if _, ok := detector.drifts[drift]; ok {
+		return
+	}
+
+	detector.drifts[drift] = struct{}{}
+	if detector.logf != nil {
+		detector.logf("cloudns: schema drift at %s: %s field %s.%s", drift.Endpoint, drift.Kind, drift.Path, drift.Field)
+	}
+}
+
+type jsonField struct {
+	name string
+	typ  reflect.Type
+}
+
+// jsonFields returns all fields of a struct which are decoded by encoding/json indexed by their lowercase name, as
+// encoding/json matches field names case-insensitively. Fields of embedded structs are flattened.
+func jsonFields(typ reflect.Type) map[string]jsonField {
+	fields := make(map[string]jsonField)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("json")
+		name := strings.Split(tag, ",")[0]
+		if tag == "-" {
+			continue
+		}
+
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			for key, embeddedField := range jsonFields(field.Type) {
+				if _, ok := fields[key]; !ok {
+					fields[key] = embeddedField
+				}
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		fields[strings.ToLower(name)] = jsonField{name: name, typ: field.Type}
+	}
+
+	return fields
+}
+
+func hasKeyFold(data map[string]interface{}, key string) bool {
+	for candidate := range data {
+		if strings.EqualFold(candidate, key) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,54 @@
+package cloudns
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestSchemaDriftDetection(t *testing.T) {
+	// given
+	var logged []string
+	detector := NewSchemaDriftDetector(func(format string, args ...interface{}) {
+		logged = append(logged, fmt.Sprintf(format, args...))
+	})
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `{"1":{"id":"1","host":"www","record":"192.0.2.1","type":"A","ttl":"3600","status":1,"geodns-code":"EU"}}`
+	}, SchemaDriftDetection(detector))
+
+	// when
+	_, err1 := stubClient.Records.List(context.Background(), testDomain)
+	_, err2 := stubClient.Records.List(context.Background(), testDomain)
+
+	// then
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+	assert.Contains(t, detector.Report(), SchemaDrift{Endpoint: recordListURL, Kind: SchemaDriftUnmapped, Path: "$.*", Field: "geodns-code"})
+	assert.Contains(t, detector.Report(), SchemaDrift{Endpoint: recordListURL, Kind: SchemaDriftMissing, Path: "$.*", Field: "priority"})
+	assert.Len(t, logged, len(detector.Report()), "each drift should only be logged once")
+	assert.Contains(t, logged, "cloudns: schema drift at /dns/records.json: unmapped field $.*.geodns-code")
+}
+
+func TestSchemaDriftDetector_EmbeddedAndCustomTypes(t *testing.T) {
+	// given
+	type inner struct {
+		Name string `json:"name"`
+	}
+	type outer struct {
+		inner
+		Active  APIBool `json:"status"`
+		Ignored string  `json:"-"`
+		Items   []inner `json:"items"`
+	}
+	detector := NewSchemaDriftDetector(nil)
+
+	// when
+	detector.inspect("/test.json", []byte(`{"name":"a","status":"1","items":[{"name":"b","extra":1}]}`), &outer{})
+
+	// then
+	assert.Equal(t, []SchemaDrift{
+		{Endpoint: "/test.json", Kind: SchemaDriftUnmapped, Path: "$.items[*]", Field: "extra"},
+	}, detector.Report())
+}
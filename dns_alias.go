@@ -0,0 +1,195 @@
+package cloudns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+
+	"github.com/miekg/dns"
+)
+
+// AliasInspectionOptions specifies how ALIAS records are inspected by RecordService.InspectAlias
+type AliasInspectionOptions struct {
+	// Nameservers which are queried for the flattened addresses, defaults to the apex NS records of the zone
+	Nameservers []string
+	// Resolver is a recursive resolver used for resolving the ALIAS target, defaults to the system resolver
+	Resolver string
+}
+
+// AliasInspection represents the comparison between the addresses an ALIAS record currently flattens to and the
+// addresses its target currently resolves to
+type AliasInspection struct {
+	Host   string
+	Target string
+	// Expected contains the addresses the ALIAS target currently resolves to
+	Expected []net.IP
+	// Nameservers contains the flattened addresses served by each queried nameserver
+	Nameservers []AliasFlattening
+}
+
+// AliasFlattening represents the addresses a single nameserver serves for an ALIAS record
+type AliasFlattening struct {
+	Server    string
+	Addresses []net.IP
+	// Missing contains addresses of the target which are not served by the nameserver
+	Missing []net.IP
+	// Unexpected contains addresses served by the nameserver which do not belong to the target (anymore)
+	Unexpected []net.IP
+	// Error is set if the nameserver could not be queried
+	Error error
+}
+
+// InspectAlias resolves what the ALIAS record of the given host currently flattens to by querying the nameservers
+// directly and compares it with the current addresses of the ALIAS target. This allows alerting once the upstream
+// target has changed unexpectedly or flattening does not pick up changes. Errors of single nameservers are reported
+// as part of the result.
+func (svc *RecordService) InspectAlias(ctx context.Context, zoneName, host string, options AliasInspectionOptions) (result AliasInspection, err error) {
+	aliases, err := svc.Search(ctx, zoneName, host, RecordTypeALIAS)
+	if err != nil {
+		return
+	}
+
+	for _, record := range aliases.SortedSlice() {
+		if normalizeRecordHost(record.Host) == normalizeRecordHost(host) {
+			result.Host = record.Host
+			result.Target = normalizeHostname(record.Record)
+			break
+		}
+	}
+	if result.Target == "" {
+		return result, ErrIllegalArgument.wrap(fmt.Errorf("no alias record found for host [%s]", host))
+	}
+
+	nameservers := options.Nameservers
+	if len(nameservers) == 0 {
+		if nameservers, err = svc.apexNameservers(ctx, zoneName); err != nil {
+			return
+		}
+	}
+
+	if result.Expected, err = svc.api.resolveAddresses(ctx, result.Target, options.Resolver); err != nil {
+		return
+	}
+
+	name := zoneName
+	if normalizeRecordHost(host) != "" {
+		name = host + "." + zoneName
+	}
+	for _, nameserver := range nameservers {
+		flattening := AliasFlattening{Server: nameserver}
+		flattening.Addresses, flattening.Error = svc.api.queryAddresses(ctx, nameserver, name, false)
+		if flattening.Error == nil {
+			flattening.Missing = subtractAddresses(result.Expected, flattening.Addresses)
+			flattening.Unexpected = subtractAddresses(flattening.Addresses, result.Expected)
+		}
+		result.Nameservers = append(result.Nameservers, flattening)
+	}
+
+	return result, nil
+}
+
+// IsConsistent returns true if all nameservers could be queried and serve exactly the addresses of the ALIAS target
+func (inspection AliasInspection) IsConsistent() bool {
+	for _, flattening := range inspection.Nameservers {
+		if flattening.Error != nil || len(flattening.Missing) > 0 || len(flattening.Unexpected) > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// apexNameservers returns the hostnames of all apex NS records of the zone
+func (svc *RecordService) apexNameservers(ctx context.Context, zoneName string) ([]string, error) {
+	records, err := svc.Search(ctx, zoneName, "", RecordTypeNS)
+	if err != nil {
+		return nil, err
+	}
+
+	var nameservers []string
+	for _, record := range records.SortedSlice() {
+		if normalizeRecordHost(record.Host) == "" {
+			nameservers = append(nameservers, normalizeHostname(record.Record))
+		}
+	}
+	if len(nameservers) == 0 {
+		return nil, ErrIllegalArgument.wrap(errors.New("zone has no apex nameservers"))
+	}
+
+	return nameservers, nil
+}
+
+// resolveAddresses resolves all IPv4 and IPv6 addresses of the given name, either using the given recursive resolver
+// or the system resolver if empty
+func (c *Client) resolveAddresses(ctx context.Context, name, resolver string) ([]net.IP, error) {
+	if resolver != "" {
+		return c.queryAddresses(ctx, resolver, name, true)
+	}
+
+	addresses, err := net.DefaultResolver.LookupIPAddr(ctx, name)
+	if err != nil {
+		return nil, ErrDNSQuery.wrap(err)
+	}
+
+	ips := make([]net.IP, 0, len(addresses))
+	for _, address := range addresses {
+		ips = append(ips, address.IP)
+	}
+
+	return sortAddresses(ips), nil
+}
+
+// queryAddresses queries the given nameserver for all A and AAAA records of the given name
+func (c *Client) queryAddresses(ctx context.Context, server, name string, recursive bool) ([]net.IP, error) {
+	var ips []net.IP
+	for _, qtype := range []uint16{dns.TypeA, dns.TypeAAAA} {
+		msg := new(dns.Msg)
+		msg.SetQuestion(dns.Fqdn(name), qtype)
+		msg.RecursionDesired = recursive
+
+		resp, err := c.exchangeDNS(ctx, msg, server)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, rr := range resp.Answer {
+			switch v := rr.(type) {
+			case *dns.A:
+				ips = append(ips, v.A)
+			case *dns.AAAA:
+				ips = append(ips, v.AAAA)
+			}
+		}
+	}
+
+	return sortAddresses(ips), nil
+}
+
+// subtractAddresses returns all addresses of a which are not contained in b
+func subtractAddresses(a, b []net.IP) []net.IP {
+	var results []net.IP
+	for _, ip := range a {
+		found := false
+		for _, other := range b {
+			if ip.Equal(other) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			results = append(results, ip)
+		}
+	}
+
+	return results
+}
+
+func sortAddresses(ips []net.IP) []net.IP {
+	sort.Slice(ips, func(i, j int) bool {
+		return ips[i].String() < ips[j].String()
+	})
+
+	return ips
+}
@@ -0,0 +1,60 @@
+package cloudns
+
+import (
+	"context"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"net/http"
+	"testing"
+)
+
+func TestRecordService_InspectAlias(t *testing.T) {
+	// given
+	answers := map[string]map[uint16]string{
+		"127.0.0.1": {dns.TypeA: "192.0.2.1", dns.TypeAAAA: "2001:db8::1"},
+		"127.0.0.2": {dns.TypeA: "192.0.2.1", dns.TypeAAAA: "2001:db8::1"},
+		"127.0.0.3": {dns.TypeA: "192.0.2.99"},
+	}
+	port := startTestUDPDNSServers(t, func(w dns.ResponseWriter, req *dns.Msg) {
+		host, _, _ := net.SplitHostPort(w.LocalAddr().String())
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+
+		question := req.Question[0]
+		if address, ok := answers[host][question.Qtype]; ok {
+			rr, _ := dns.NewRR(question.Name + " 300 IN " + dns.TypeToString[question.Qtype] + " " + address)
+			resp.Answer = append(resp.Answer, rr)
+		}
+		_ = w.WriteMsg(resp)
+	}, "127.0.0.1", "127.0.0.2", "127.0.0.3")
+
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `{"1":{"id":"1","host":"","record":"target.example.net","type":"ALIAS","ttl":"3600","status":1}}`
+	}, DNSPort(port))
+
+	// when
+	result, err := stubClient.Records.InspectAlias(context.Background(), testDomain, "@", AliasInspectionOptions{
+		Nameservers: []string{"127.0.0.2", "127.0.0.3"},
+		Resolver:    "127.0.0.1",
+	})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "target.example.net", result.Target)
+	assert.Equal(t, "192.0.2.1", result.Expected[0].String())
+	assert.False(t, result.IsConsistent(), "stale nameserver should be detected")
+	assert.Empty(t, result.Nameservers[0].Missing)
+	assert.Empty(t, result.Nameservers[0].Unexpected)
+	assert.Len(t, result.Nameservers[1].Missing, 2)
+	assert.Equal(t, "192.0.2.99", result.Nameservers[1].Unexpected[0].String())
+}
+
+func TestRecordService_InspectAlias_Missing(t *testing.T) {
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `[]`
+	})
+
+	_, err := stubClient.Records.InspectAlias(context.Background(), testDomain, "www", AliasInspectionOptions{})
+	assert.ErrorIs(t, err, ErrIllegalArgument)
+}
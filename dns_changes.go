@@ -0,0 +1,174 @@
+package cloudns
+
+import (
+	"context"
+	"fmt"
+)
+
+// RecordChangeType is an enumeration of all kinds of changes to a single record
+type RecordChangeType int
+
+// Enumeration values for RecordChangeType
+const (
+	RecordChangeCreate RecordChangeType = iota
+	RecordChangeUpdate
+	RecordChangeDelete
+)
+
+// RecordChange represents a single pending change to a record within a zone. ID is required for updates and deletions,
+// while Record contains the desired record for creations and updates. For deletions, Record should contain the record
+// being deleted, which allows pairing it with creations when coalescing.
+type RecordChange struct {
+	Type   RecordChangeType
+	ID     int
+	Record Record
+}
+
+// String returns a human-readable name of the change type
+func (changeType RecordChangeType) String() string {
+	switch changeType {
+	case RecordChangeCreate:
+		return "create"
+	case RecordChangeUpdate:
+		return "update"
+	case RecordChangeDelete:
+		return "delete"
+	}
+
+	return "unknown"
+}
+
+// CoalesceChanges merges a sequence of record changes into the minimal amount of API calls with the same outcome and
+// orders them to minimize intermediate broken states:
+//   - multiple updates of the same record are merged into the last update
+//   - updates of a record which gets deleted afterwards are dropped
+//   - deleting a record and creating an equivalent one cancel each other out
+//   - deleting a record and creating another one with same host and type are merged into an update
+//
+// All creations are ordered before updates and deletions, so that e.g. renamed records exist under their new name
+// before the old one disappears. The relative order of changes with the same type is preserved.
+func CoalesceChanges(changes []RecordChange) []RecordChange {
+	var creates, updates, deletes []RecordChange
+	updateIndex := make(map[int]int)
+	deleted := make(map[int]bool)
+
+	for _, change := range changes {
+		switch change.Type {
+		case RecordChangeCreate:
+			creates = append(creates, change)
+		case RecordChangeUpdate:
+			if deleted[change.ID] {
+				continue
+			}
+			if index, ok := updateIndex[change.ID]; ok {
+				updates[index].Record = change.Record
+				continue
+			}
+			updateIndex[change.ID] = len(updates)
+			updates = append(updates, change)
+		case RecordChangeDelete:
+			if deleted[change.ID] {
+				continue
+			}
+			deleted[change.ID] = true
+			deletes = append(deletes, change)
+		}
+	}
+
+	// Drop updates of deleted records while keeping the order of all other updates
+	var remainingUpdates []RecordChange
+	for _, change := range updates {
+		if !deleted[change.ID] {
+			remainingUpdates = append(remainingUpdates, change)
+		}
+	}
+	updates = remainingUpdates
+
+	// Pair deletions with creations of equivalent records first, then with creations of records with same host and type
+	var remainingDeletes []RecordChange
+	for _, change := range deletes {
+		if change.Record.RecordType == "" {
+			remainingDeletes = append(remainingDeletes, change)
+			continue
+		}
+
+		if index := indexOfEquivalentChange(creates, change.Record); index >= 0 {
+			creates = append(creates[:index], creates[index+1:]...)
+			continue
+		}
+
+		remainingDeletes = append(remainingDeletes, change)
+	}
+
+	deletes = nil
+	for _, change := range remainingDeletes {
+		index := -1
+		if change.Record.RecordType != "" {
+			index = indexOfSameRecordSetChange(creates, change.Record)
+		}
+		if index < 0 {
+			deletes = append(deletes, change)
+			continue
+		}
+
+		updates = append(updates, RecordChange{Type: RecordChangeUpdate, ID: change.ID, Record: creates[index].Record})
+		creates = append(creates[:index], creates[index+1:]...)
+	}
+
+	results := make([]RecordChange, 0, len(creates)+len(updates)+len(deletes))
+	results = append(results, creates...)
+	results = append(results, updates...)
+	results = append(results, deletes...)
+
+	return results
+}
+
+// ApplyChanges coalesces the given changes using CoalesceChanges and applies them to the zone. The returned slice
+// contains all changes which have been applied successfully, even if a later change has failed. The context is checked
+// before every change, so that cancelling it stops applying further changes right away.
+func (svc *RecordService) ApplyChanges(ctx context.Context, zoneName string, changes []RecordChange) (applied []RecordChange, err error) {
+	for _, change := range CoalesceChanges(changes) {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+
+		switch change.Type {
+		case RecordChangeCreate:
+			_, err = svc.Create(ctx, zoneName, change.Record)
+		case RecordChangeUpdate:
+			_, err = svc.Update(ctx, zoneName, change.ID, change.Record)
+		case RecordChangeDelete:
+			_, err = svc.Delete(ctx, zoneName, change.ID)
+		default:
+			err = ErrIllegalArgument.wrap(fmt.Errorf("unknown record change type: %d", change.Type))
+		}
+
+		if err != nil {
+			return
+		}
+		applied = append(applied, change)
+	}
+
+	return
+}
+
+func indexOfEquivalentChange(changes []RecordChange, record Record) int {
+	for index, change := range changes {
+		if recordsEquivalent(change.Record, record) {
+			return index
+		}
+	}
+
+	return -1
+}
+
+func indexOfSameRecordSetChange(changes []RecordChange, record Record) int {
+	key := recordSetKey(record.Host, record.RecordType)
+	for index, change := range changes {
+		if recordSetKey(change.Record.Host, change.Record.RecordType) == key {
+			return index
+		}
+	}
+
+	return -1
+}
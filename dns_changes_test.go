@@ -0,0 +1,90 @@
+package cloudns
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestCoalesceChanges(t *testing.T) {
+	// given
+	oldWWW := NewRecordA("www", "192.0.2.1", testTTL)
+	oldWWW.ID = 1
+	oldMail := NewRecordA("mail", "192.0.2.2", testTTL)
+	oldMail.ID = 2
+	oldFTP := NewRecordA("ftp", "192.0.2.3", testTTL)
+	oldFTP.ID = 3
+	oldLegacy := NewRecordA("legacy", "192.0.2.4", testTTL)
+	oldLegacy.ID = 4
+
+	changes := []RecordChange{
+		{Type: RecordChangeUpdate, ID: 1, Record: NewRecordA("www", "192.0.2.1", 60)},
+		{Type: RecordChangeUpdate, ID: 1, Record: NewRecordA("www", "192.0.2.10", 60)},
+		{Type: RecordChangeUpdate, ID: 4, Record: NewRecordA("legacy", "192.0.2.40", testTTL)},
+		{Type: RecordChangeDelete, ID: 4, Record: oldLegacy},
+		{Type: RecordChangeDelete, ID: 2, Record: oldMail},
+		{Type: RecordChangeCreate, Record: NewRecordA("mail", "192.0.2.2", testTTL)},
+		{Type: RecordChangeDelete, ID: 3, Record: oldFTP},
+		{Type: RecordChangeCreate, Record: NewRecordA("ftp", "192.0.2.30", testTTL)},
+		{Type: RecordChangeDelete, ID: 1, Record: oldWWW},
+		{Type: RecordChangeCreate, Record: NewRecordA("web", "192.0.2.10", 60)},
+	}
+
+	// when
+	results := CoalesceChanges(changes)
+
+	// then
+	assert.Equal(t, []RecordChange{
+		{Type: RecordChangeCreate, Record: NewRecordA("web", "192.0.2.10", 60)},
+		{Type: RecordChangeUpdate, ID: 3, Record: NewRecordA("ftp", "192.0.2.30", testTTL)},
+		{Type: RecordChangeDelete, ID: 4, Record: oldLegacy},
+		{Type: RecordChangeDelete, ID: 1, Record: oldWWW},
+	}, results)
+}
+
+func TestCoalesceChanges_MergeUpdates(t *testing.T) {
+	results := CoalesceChanges([]RecordChange{
+		{Type: RecordChangeUpdate, ID: 1, Record: NewRecordA("www", "192.0.2.1", 60)},
+		{Type: RecordChangeUpdate, ID: 2, Record: NewRecordA("mail", "192.0.2.2", 60)},
+		{Type: RecordChangeUpdate, ID: 1, Record: NewRecordA("www", "192.0.2.10", 60)},
+	})
+
+	assert.Equal(t, []RecordChange{
+		{Type: RecordChangeUpdate, ID: 1, Record: NewRecordA("www", "192.0.2.10", 60)},
+		{Type: RecordChangeUpdate, ID: 2, Record: NewRecordA("mail", "192.0.2.2", 60)},
+	}, results, "updates should be merged while keeping their order")
+}
+
+func TestRecordService_ApplyChanges(t *testing.T) {
+	// given
+	var requests []string
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		var params map[string]interface{}
+		_ = json.NewDecoder(req.Body).Decode(&params)
+		requests = append(requests, fmt.Sprintf("%s %v", req.URL.Path, params["record-id"]))
+
+		if req.URL.Path == recordDeleteURL {
+			return `{"status":"Failed","statusDescription":"Invalid record-id"}`
+		}
+		return `{"status":"Success"}`
+	})
+
+	// when
+	applied, err := stubClient.Records.ApplyChanges(context.Background(), testDomain, []RecordChange{
+		{Type: RecordChangeDelete, ID: 1},
+		{Type: RecordChangeUpdate, ID: 2, Record: NewRecordA("www", "192.0.2.2", testTTL)},
+		{Type: RecordChangeCreate, Record: NewRecordA("www", "192.0.2.3", testTTL)},
+	})
+
+	// then
+	assert.ErrorIs(t, err, ErrAPIInvocation)
+	assert.Len(t, applied, 2, "successful changes should be returned")
+	assert.Equal(t, []string{
+		recordCreateURL + " <nil>",
+		recordUpdateURL + " 2",
+		recordDeleteURL + " 1",
+	}, requests)
+}
@@ -0,0 +1,45 @@
+package cloudns
+
+import (
+	"context"
+)
+
+const cloudDomainListURL = "/dns/list-cloud-domains.json"
+const cloudDomainAddURL = "/dns/add-cloud-domain.json"
+const cloudDomainDeleteURL = "/dns/delete-cloud-domain.json"
+const cloudDomainSetMasterURL = "/dns/set-master-cloud-domain.json"
+
+// CloudDomain represents a single domain of a DNS cloud, which is a group of zones sharing the records of their master
+type CloudDomain struct {
+	Name     string  `json:"name"`
+	IsMaster APIBool `json:"cloudMaster"`
+}
+
+// ListCloudDomains returns all domains which belong to the same DNS cloud as the given zone, including its master
+func (svc *ZoneService) ListCloudDomains(ctx context.Context, zoneName string) (result []CloudDomain, err error) {
+	params := HTTPParams{"domain-name": zoneName}
+	err = svc.api.request(ctx, "POST", cloudDomainListURL, params, nil, &result)
+	return
+}
+
+// AddCloudDomain creates a new domain within the DNS cloud of the given master zone, which mirrors all its records
+func (svc *ZoneService) AddCloudDomain(ctx context.Context, masterZoneName, cloudDomainName string) (result StatusResult, err error) {
+	params := HTTPParams{"domain-name": masterZoneName, "cloud-domain-name": cloudDomainName}
+	err = svc.api.request(ctx, "POST", cloudDomainAddURL, params, nil, &result)
+	return
+}
+
+// DeleteCloudDomain removes the given domain from its DNS cloud and deletes it. The master of a DNS cloud can only be
+// deleted after another domain has been promoted to master using SetCloudMaster.
+func (svc *ZoneService) DeleteCloudDomain(ctx context.Context, cloudDomainName string) (result StatusResult, err error) {
+	params := HTTPParams{"domain-name": cloudDomainName}
+	err = svc.api.request(ctx, "POST", cloudDomainDeleteURL, params, nil, &result)
+	return
+}
+
+// SetCloudMaster promotes the given domain to the master of its DNS cloud
+func (svc *ZoneService) SetCloudMaster(ctx context.Context, cloudDomainName string) (result StatusResult, err error) {
+	params := HTTPParams{"domain-name": cloudDomainName}
+	err = svc.api.request(ctx, "POST", cloudDomainSetMasterURL, params, nil, &result)
+	return
+}
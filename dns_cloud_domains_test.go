@@ -0,0 +1,43 @@
+package cloudns
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestZoneService_ListCloudDomains(t *testing.T) {
+	// given
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `[{"name":"api-example.com","cloudMaster":"1"},{"name":"api-example.net","cloudMaster":"0"}]`
+	})
+
+	// when
+	domains, err := stubClient.Zones.ListCloudDomains(context.Background(), testDomain)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []CloudDomain{
+		{Name: "api-example.com", IsMaster: true},
+		{Name: "api-example.net", IsMaster: false},
+	}, domains)
+}
+
+func TestZoneService_AddCloudDomain(t *testing.T) {
+	// given
+	var params map[string]interface{}
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		_ = json.NewDecoder(req.Body).Decode(&params)
+		return `{"status":"Success","statusDescription":"The domain was added to the cloud."}`
+	})
+
+	// when
+	_, err := stubClient.Zones.AddCloudDomain(context.Background(), testDomain, "api-example.net")
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, testDomain, params["domain-name"])
+	assert.Equal(t, "api-example.net", params["cloud-domain-name"])
+}
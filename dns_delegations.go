@@ -0,0 +1,202 @@
+package cloudns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+)
+
+// Delegation describes the delegation of a child zone to a set of nameservers, including optional glue records for
+// nameservers which are located within the parent zone
+type Delegation struct {
+	// Host is the name of the child zone relative to the parent zone, e.g. `sub` for `sub.example.com`
+	Host string
+	// Nameservers contains the fully qualified hostnames of all nameservers responsible for the child zone
+	Nameservers []string
+	// Glue contains the IPv4 and/or IPv6 addresses for nameservers located within the parent zone, indexed by the
+	// fully qualified hostname of the nameserver. Nameservers within the child zone must have glue records.
+	Glue map[string][]net.IP
+	// TTL is used for all NS and glue records created or updated by EnsureDelegation
+	TTL int
+}
+
+// DelegationResult contains all changes performed by EnsureDelegation
+type DelegationResult struct {
+	Created []Record
+	// Updated contains all existing records whose TTL has been changed, including their ID
+	Updated []Record
+	Deleted []Record
+}
+
+// EnsureDelegation creates or updates the NS records for a child zone and the matching glue A/AAAA records, so that
+// the zone contains exactly the specified delegation afterwards. Consistency between nameservers and glue records is
+// verified before any change is performed. New records are created before stale records get removed, which ensures
+// that the delegation never ends up without nameservers.
+func (svc *RecordService) EnsureDelegation(ctx context.Context, zoneName string, delegation Delegation) (result DelegationResult, err error) {
+	records, err := svc.List(ctx, zoneName)
+	if err != nil {
+		return
+	}
+
+	create, update, remove, err := planDelegation(zoneName, records, delegation)
+	if err != nil {
+		return
+	}
+
+	for _, record := range create {
+		if _, err = svc.Create(ctx, zoneName, record); err != nil {
+			return
+		}
+		result.Created = append(result.Created, record)
+	}
+
+	for _, record := range update {
+		if _, err = svc.Update(ctx, zoneName, record.ID, record); err != nil {
+			return
+		}
+		result.Updated = append(result.Updated, record)
+	}
+
+	for _, record := range remove {
+		if _, err = svc.Delete(ctx, zoneName, record.ID); err != nil {
+			return
+		}
+		result.Deleted = append(result.Deleted, record)
+	}
+
+	return
+}
+
+// planDelegation compares the existing records of a zone with the desired delegation and returns the records which
+// have to be created, updated and removed. Records whose TTL differs from the desired TTL are updated in place.
+func planDelegation(zoneName string, records RecordMap, delegation Delegation) (create, update, remove []Record, err error) {
+	zoneName = normalizeHostname(zoneName)
+	childHost := normalizeHostname(delegation.Host)
+	childName := childHost + "." + zoneName
+
+	if childHost == "" {
+		return nil, nil, nil, ErrIllegalArgument.wrap(errors.New("delegation host must not be empty"))
+	}
+	if len(delegation.Nameservers) == 0 {
+		return nil, nil, nil, ErrIllegalArgument.wrap(errors.New("delegation requires at least one nameserver"))
+	}
+
+	nameservers := make([]string, 0, len(delegation.Nameservers))
+	for _, nameserver := range delegation.Nameservers {
+		nameservers = append(nameservers, normalizeHostname(nameserver))
+	}
+
+	// Verify that glue records are only specified for nameservers of the delegation inside the zone
+	glue := make(map[string][]net.IP)
+	for nameserver, addresses := range delegation.Glue {
+		nameserver = normalizeHostname(nameserver)
+		if !containsString(nameserver, nameservers) {
+			return nil, nil, nil, ErrIllegalArgument.wrap(fmt.Errorf("glue specified for unknown nameserver %s", nameserver))
+		}
+		if !strings.HasSuffix(nameserver, "."+zoneName) {
+			return nil, nil, nil, ErrIllegalArgument.wrap(fmt.Errorf("glue for nameserver %s is outside of zone %s", nameserver, zoneName))
+		}
+
+		for _, address := range addresses {
+			if address == nil {
+				return nil, nil, nil, ErrIllegalArgument.wrap(fmt.Errorf("glue for nameserver %s contains an invalid ip", nameserver))
+			}
+		}
+		glue[nameserver] = append(glue[nameserver], addresses...)
+	}
+
+	// Verify that all nameservers within the delegated zone have glue records, as they could not be resolved otherwise
+	for _, nameserver := range nameservers {
+		isInChild := nameserver == childName || strings.HasSuffix(nameserver, "."+childName)
+		if isInChild && len(glue[nameserver]) == 0 {
+			return nil, nil, nil, ErrIllegalArgument.wrap(fmt.Errorf("nameserver %s is within delegated zone and requires glue", nameserver))
+		}
+	}
+
+	// Build the full set of desired records, consisting of NS records and optional glue records
+	var desired []Record
+	for _, nameserver := range nameservers {
+		desired = append(desired, NewRecordNS(childHost, nameserver, delegation.TTL))
+	}
+	for nameserver, addresses := range glue {
+		glueHost := strings.TrimSuffix(nameserver, "."+zoneName)
+		for _, address := range addresses {
+			if address.To4() != nil {
+				desired = append(desired, NewRecordA(glueHost, address.String(), delegation.TTL))
+			} else {
+				desired = append(desired, NewRecordAAAA(glueHost, address.String(), delegation.TTL))
+			}
+		}
+	}
+
+	// Determine which existing records are managed by this delegation, which are all NS records of the child and all
+	// address records of nameservers with glue
+	isManaged := func(record Record) bool {
+		host := normalizeHostname(record.Host)
+		switch record.RecordType {
+		case RecordTypeNS:
+			return host == childHost
+		case RecordTypeA, RecordTypeAAAA:
+			_, ok := glue[host+"."+zoneName]
+			return ok
+		}
+		return false
+	}
+
+	// Keep a single existing record per key, preferring one with the desired TTL, and remove all duplicates
+	existing := make(map[string]Record)
+	for _, record := range records.SortedSlice() {
+		if !isManaged(record) {
+			continue
+		}
+
+		key := delegationRecordKey(record)
+		if previous, ok := existing[key]; !ok {
+			existing[key] = record
+		} else if previous.TTL != delegation.TTL && record.TTL == delegation.TTL {
+			existing[key] = record
+			remove = append(remove, previous)
+		} else {
+			remove = append(remove, record)
+		}
+	}
+
+	for _, record := range desired {
+		key := delegationRecordKey(record)
+		if current, ok := existing[key]; ok {
+			if current.TTL != delegation.TTL {
+				current.TTL = delegation.TTL
+				update = append(update, current)
+			}
+			delete(existing, key)
+			continue
+		}
+		create = append(create, record)
+	}
+
+	for _, record := range existing {
+		remove = append(remove, record)
+	}
+	sort.Slice(remove, func(i, j int) bool {
+		return remove[i].ID < remove[j].ID
+	})
+
+	return create, update, remove, nil
+}
+
+func delegationRecordKey(record Record) string {
+	value := normalizeHostname(record.Record)
+	if ip := net.ParseIP(value); ip != nil {
+		value = ip.String()
+	}
+
+	return fmt.Sprintf("%s|%s|%s", record.RecordType, normalizeHostname(record.Host), value)
+}
+
+// normalizeHostname lowercases a hostname and strips the trailing dot, so that it can be compared with others
+func normalizeHostname(hostname string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(hostname), "."))
+}
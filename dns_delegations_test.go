@@ -0,0 +1,95 @@
+package cloudns
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"testing"
+)
+
+func buildRecordMap(records ...Record) RecordMap {
+	result := make(RecordMap)
+	for index, record := range records {
+		record.ID = index + 1
+		result[record.ID] = record
+	}
+
+	return result
+}
+
+func TestPlanDelegation_Create(t *testing.T) {
+	// given
+	delegation := Delegation{
+		Host:        "sub",
+		Nameservers: []string{"ns1.sub.api-example.com", "ns2.provider.local."},
+		Glue:        map[string][]net.IP{"ns1.sub.api-example.com.": {net.ParseIP("192.0.2.1"), net.ParseIP("2001:db8::1")}},
+		TTL:         testTTL,
+	}
+
+	// when
+	create, update, remove, err := planDelegation(testDomain, buildRecordMap(), delegation)
+
+	// then
+	assert.NoError(t, err)
+	assert.Empty(t, update, "should not update any records")
+	assert.Empty(t, remove, "should not remove any records")
+	assert.ElementsMatch(t, []Record{
+		NewRecordNS("sub", "ns1.sub.api-example.com", testTTL),
+		NewRecordNS("sub", "ns2.provider.local", testTTL),
+		NewRecordA("ns1.sub", "192.0.2.1", testTTL),
+		NewRecordAAAA("ns1.sub", "2001:db8::1", testTTL),
+	}, create, "should create all NS and glue records")
+}
+
+func TestPlanDelegation_Update(t *testing.T) {
+	// given
+	records := buildRecordMap(
+		NewRecordNS("sub", "ns1.provider.local", testTTL),
+		NewRecordNS("sub", "ns2.provider.local", testTTL),
+		NewRecordNS("sub", "NS3.provider.local.", 60),
+		NewRecordNS("other", "ns1.provider.local", testTTL),
+		NewRecordA("www", "192.0.2.1", testTTL),
+	)
+	delegation := Delegation{
+		Host:        "sub",
+		Nameservers: []string{"ns2.provider.local", "ns3.provider.local"},
+		TTL:         testTTL,
+	}
+
+	// when
+	create, update, remove, err := planDelegation(testDomain, records, delegation)
+
+	// then
+	assert.NoError(t, err)
+	assert.Empty(t, create)
+	updated := records[3]
+	updated.TTL = testTTL
+	assert.Equal(t, []Record{updated}, update, "ttl should be updated in place")
+	assert.Equal(t, []Record{records[1]}, remove)
+}
+
+func TestPlanDelegation_Invalid(t *testing.T) {
+	test := func(delegation Delegation) {
+		_, _, _, err := planDelegation(testDomain, buildRecordMap(), delegation)
+		assert.True(t, errors.Is(err, ErrIllegalArgument), "delegation %+v should be rejected", delegation)
+	}
+
+	test(Delegation{Nameservers: []string{"ns1.provider.local"}})
+	test(Delegation{Host: "sub"})
+	test(Delegation{Host: "sub", Nameservers: []string{"ns1.sub.api-example.com"}})
+	test(Delegation{
+		Host:        "sub",
+		Nameservers: []string{"ns1.provider.local"},
+		Glue:        map[string][]net.IP{"ns1.provider.local": {net.ParseIP("192.0.2.1")}},
+	})
+	test(Delegation{
+		Host:        "sub",
+		Nameservers: []string{"ns1.sub.api-example.com"},
+		Glue:        map[string][]net.IP{"ns2.sub.api-example.com": {net.ParseIP("192.0.2.1")}},
+	})
+	test(Delegation{
+		Host:        "sub",
+		Nameservers: []string{"ns1.sub.api-example.com"},
+		Glue:        map[string][]net.IP{"ns1.sub.api-example.com": {net.ParseIP("invalid")}},
+	})
+}
@@ -0,0 +1,131 @@
+package cloudns
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const dnssecActivateURL = "/dns/activate-dnssec.json"
+const dnssecDeactivateURL = "/dns/deactivate-dnssec.json"
+const dnssecAvailableURL = "/dns/is-dnssec-available.json"
+const dnssecDSRecordsURL = "/dns/get-dnssec-ds-records.json"
+const dnssecOptOutURL = "/dns/set-dnssec-optout.json"
+
+// DNSSECService is a service object which groups all operations related to DNSSEC of ClouDNS zones
+type DNSSECService struct {
+	api *Client
+}
+
+// DSRecord represents a delegation signer record, which has to be published at the registrar of the domain to
+// establish the chain of trust for a DNSSEC-signed zone
+type DSRecord struct {
+	KeyTag     uint16
+	Algorithm  uint8
+	DigestType uint8
+	Digest     string
+}
+
+// Activate enables DNSSEC for the given zone. ClouDNS generates the keys and signs the zone afterwards, which might
+// take a few minutes until the DS records become available.
+func (svc *DNSSECService) Activate(ctx context.Context, zoneName string) (result StatusResult, err error) {
+	params := HTTPParams{"domain-name": zoneName}
+	err = svc.api.request(ctx, "POST", dnssecActivateURL, params, nil, &result)
+	return
+}
+
+// Deactivate disables DNSSEC for the given zone. The DS records should be removed at the registrar beforehand, as the
+// domain stops resolving for validating resolvers otherwise.
+func (svc *DNSSECService) Deactivate(ctx context.Context, zoneName string) (result StatusResult, err error) {
+	params := HTTPParams{"domain-name": zoneName}
+	err = svc.api.request(ctx, "POST", dnssecDeactivateURL, params, nil, &result)
+	return
+}
+
+// IsAvailable returns true if DNSSEC can be activated for the given zone, which depends on the plan and the TLD
+func (svc *DNSSECService) IsAvailable(ctx context.Context, zoneName string) (bool, error) {
+	var result struct {
+		Status APIBool `json:"status"`
+	}
+
+	params := HTTPParams{"domain-name": zoneName}
+	err := svc.api.request(ctx, "POST", dnssecAvailableURL, params, nil, &result)
+	return bool(result.Status), err
+}
+
+// GetDSRecords returns the DS records of the given zone, which have to be published at the registrar of the domain
+func (svc *DNSSECService) GetDSRecords(ctx context.Context, zoneName string) ([]DSRecord, error) {
+	var result struct {
+		DS []string `json:"ds"`
+	}
+
+	params := HTTPParams{"domain-name": zoneName}
+	if err := svc.api.request(ctx, "POST", dnssecDSRecordsURL, params, nil, &result); err != nil {
+		return nil, err
+	}
+
+	records := make([]DSRecord, 0, len(result.DS))
+	for _, value := range result.DS {
+		record, err := ParseDSRecord(value)
+		if err != nil {
+			return nil, ErrAPIInvocation.wrap(err)
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// SetOptOut enables or disables NSEC3 opt-out for the given zone, which skips signing insecure delegations. This is
+// mostly useful for zones containing a large amount of delegations to unsigned child zones.
+func (svc *DNSSECService) SetOptOut(ctx context.Context, zoneName string, isOptOut bool) (result StatusResult, err error) {
+	params := HTTPParams{"domain-name": zoneName}
+	if isOptOut {
+		params["status"] = 1
+	} else {
+		params["status"] = 0
+	}
+
+	err = svc.api.request(ctx, "POST", dnssecOptOutURL, params, nil, &result)
+	return
+}
+
+// ParseDSRecord parses a DS record in presentation format, e.g. `12345 13 2 1F2E...`. An optional owner name, TTL,
+// class and record type preceding the record data are skipped.
+func ParseDSRecord(value string) (record DSRecord, err error) {
+	fields := strings.Fields(value)
+	for index, field := range fields {
+		if strings.EqualFold(field, "DS") {
+			fields = fields[index+1:]
+			break
+		}
+	}
+	if len(fields) < 4 {
+		return record, ErrIllegalArgument.wrap(fmt.Errorf("invalid ds record: %s", value))
+	}
+
+	keyTag, err := strconv.ParseUint(fields[0], 10, 16)
+	if err != nil {
+		return record, ErrIllegalArgument.wrap(fmt.Errorf("invalid key tag of ds record: %w", err))
+	}
+	algorithm, err := strconv.ParseUint(fields[1], 10, 8)
+	if err != nil {
+		return record, ErrIllegalArgument.wrap(fmt.Errorf("invalid algorithm of ds record: %w", err))
+	}
+	digestType, err := strconv.ParseUint(fields[2], 10, 8)
+	if err != nil {
+		return record, ErrIllegalArgument.wrap(fmt.Errorf("invalid digest type of ds record: %w", err))
+	}
+
+	record.KeyTag = uint16(keyTag)
+	record.Algorithm = uint8(algorithm)
+	record.DigestType = uint8(digestType)
+	record.Digest = strings.ToUpper(strings.Join(fields[3:], ""))
+	return record, nil
+}
+
+// String returns the DS record data in presentation format
+func (record DSRecord) String() string {
+	return fmt.Sprintf("%d %d %d %s", record.KeyTag, record.Algorithm, record.DigestType, record.Digest)
+}
@@ -0,0 +1,72 @@
+package cloudns
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestParseDSRecord(t *testing.T) {
+	expected := DSRecord{KeyTag: 2371, Algorithm: 13, DigestType: 2, Digest: "1F987CC6583E92DF0890718C42"}
+
+	record, err := ParseDSRecord("2371 13 2 1f987cc6583e92df0890718c42")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, record)
+
+	record, err = ParseDSRecord("api-example.com. 3600 IN DS 2371 13 2 1F987CC6583E92DF 0890718C42")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, record, "owner name and whitespace within digest should be handled")
+	assert.Equal(t, "2371 13 2 1F987CC6583E92DF0890718C42", record.String())
+
+	_, err = ParseDSRecord("2371 13")
+	assert.ErrorIs(t, err, ErrIllegalArgument)
+	_, err = ParseDSRecord("invalid 13 2 ABCD")
+	assert.ErrorIs(t, err, ErrIllegalArgument)
+}
+
+func TestDNSSECService_GetDSRecords(t *testing.T) {
+	// given
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		assert.Equal(t, dnssecDSRecordsURL, req.URL.Path)
+		return `{"ds":["2371 13 2 1F987CC6583E92DF0890718C42","2371 13 4 ABCDEF"]}`
+	})
+
+	// when
+	records, err := stubClient.DNSSEC.GetDSRecords(context.Background(), testDomain)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []DSRecord{
+		{KeyTag: 2371, Algorithm: 13, DigestType: 2, Digest: "1F987CC6583E92DF0890718C42"},
+		{KeyTag: 2371, Algorithm: 13, DigestType: 4, Digest: "ABCDEF"},
+	}, records)
+}
+
+func TestDNSSECService_IsAvailable(t *testing.T) {
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `{"status":"1"}`
+	})
+
+	available, err := stubClient.DNSSEC.IsAvailable(context.Background(), testDomain)
+	assert.NoError(t, err)
+	assert.True(t, available)
+}
+
+func TestDNSSECService_SetOptOut(t *testing.T) {
+	// given
+	var params map[string]interface{}
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		_ = json.NewDecoder(req.Body).Decode(&params)
+		return `{"status":"Success","statusDescription":"OK"}`
+	})
+
+	// when
+	_, err := stubClient.DNSSEC.SetOptOut(context.Background(), testDomain, true)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, testDomain, params["domain-name"])
+	assert.EqualValues(t, 1, params["status"])
+}
@@ -0,0 +1,156 @@
+package cloudns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+// IPSource returns the current public IP address of the host, which is used by RecordService.RunDynamicUpdater to
+// detect when the dynamic record needs to be updated
+type IPSource func(ctx context.Context) (net.IP, error)
+
+// DynamicUpdaterOptions specifies how RecordService.RunDynamicUpdater detects and reports changes of the public IP
+type DynamicUpdaterOptions struct {
+	// Interval is the time waited between two checks of the public IP, defaulting to five minutes
+	Interval time.Duration
+	// Source returns the current public IP, defaulting to AccountService.GetCurrentIP
+	Source IPSource
+	// OnUpdate is called after the dynamic URL has been invoked successfully because the public IP changed. The
+	// previous IP is nil for the initial update after starting the updater. It is never called during dry runs.
+	OnUpdate func(previous, current net.IP)
+	// OnError is called for every failed check or update, after which the updater continues with the next check
+	OnError func(err error)
+	// Store persists the IP of the last successful update, so that a restarted updater does not invoke the dynamic URL
+	// again if the IP did not change in the meantime. Optional.
+	Store Store
+}
+
+// UpdateDynamicURL invokes the given DynDNS URL, which causes ClouDNS to update the record to the IP address it sees
+// while being connected to. Invoking the URL modifies the zone and is therefore refused for read-only clients and
+// frozen zones and skipped for dry runs, see Records.GetDynamicURL for retrieving the URL. Like API requests, it waits
+// for the rate limiter and gets logged and reported to the metrics recorder using the path of the URL as endpoint.
+func (svc *RecordService) UpdateDynamicURL(ctx context.Context, zoneName string, dynamicURL DynamicURL) error {
+	if svc.api.readOnly {
+		return ErrReadOnlyClient.wrap(fmt.Errorf("refusing to invoke dynamic url of %s", dynamicURL.Host))
+	}
+	if svc.api.IsZoneFrozen(zoneName) {
+		return ErrZoneFrozen.wrap(fmt.Errorf("zone %s is frozen", normalizeHostname(zoneName)))
+	}
+	if svc.api.isDryRun(ctx) {
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", dynamicURL.URL, nil)
+	if err != nil {
+		return ErrHTTPRequest.wrap(err)
+	}
+	req.Header.Set("User-Agent", svc.api.userAgent)
+
+	// The query of the URL contains the secret token identifying the record and is therefore never logged
+	_, err = svc.api.dispatch(ctx, "GET", req.URL.Path, nil, func() ([]byte, error) {
+		resp, err := svc.api.httpClient.Do(req)
+		if err != nil {
+			return nil, ErrHTTPRequest.wrap(err)
+		}
+		defer resp.Body.Close()
+		_, _ = io.Copy(io.Discard, resp.Body)
+
+		if resp.StatusCode >= 400 {
+			return nil, ErrHTTPRequest.wrap(httpStatusError{statusCode: resp.StatusCode})
+		}
+		return nil, nil
+	})
+
+	return err
+}
+
+// RunDynamicUpdater keeps the given record pointed at the public IP of the host, replacing tools like ddclient. The
+// DynDNS URL of the record is retrieved once, after which the public IP is checked periodically and the URL is invoked
+// whenever the IP differs from the one of the last successful update, including once right after starting. As ClouDNS
+// updates the record to the IP address it sees, a custom IPSource must return the address the host connects from.
+// It blocks until the context is done and returns the error of the context, unless retrieving the URL or restoring the
+// last IP from the store fails.
+func (svc *RecordService) RunDynamicUpdater(ctx context.Context, zoneName string, recordID int,
+	options DynamicUpdaterOptions) error {
+	if options.Interval <= 0 {
+		options.Interval = 5 * time.Minute
+	}
+	if options.Source == nil {
+		options.Source = svc.api.Account.GetCurrentIP
+	}
+
+	dynamicURL, err := svc.GetDynamicURL(ctx, zoneName, recordID)
+	if err != nil {
+		return err
+	}
+	if dynamicURL.URL == "" {
+		return ErrIllegalArgument.wrap(errors.New("dynamic url is not enabled for record"))
+	}
+
+	var lastIP net.IP
+	storeKey := fmt.Sprintf("dynamic-updater/%s/%d", normalizeHostname(zoneName), recordID)
+	if options.Store != nil {
+		value, ok, err := options.Store.Get(ctx, storeKey)
+		if err != nil {
+			return err
+		}
+		if ok {
+			lastIP = net.ParseIP(string(value))
+		}
+	}
+
+	for {
+		if err := svc.checkDynamicIP(ctx, zoneName, dynamicURL, options, storeKey, &lastIP); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if options.OnError != nil {
+				options.OnError(err)
+			}
+		}
+
+		if err := svc.api.clock.Sleep(ctx, options.Interval); err != nil {
+			return err
+		}
+	}
+}
+
+// checkDynamicIP invokes the dynamic URL if the public IP returned by the source differs from the last one, which gets
+// persisted within the store of the options after a successful update
+func (svc *RecordService) checkDynamicIP(ctx context.Context, zoneName string, dynamicURL DynamicURL,
+	options DynamicUpdaterOptions, storeKey string, lastIP *net.IP) error {
+	currentIP, err := options.Source(ctx)
+	if err != nil {
+		return err
+	}
+	if currentIP == nil {
+		return ErrIllegalArgument.wrap(errors.New("ip source returned no address"))
+	}
+	if currentIP.Equal(*lastIP) {
+		return nil
+	}
+
+	if err := svc.UpdateDynamicURL(ctx, zoneName, dynamicURL); err != nil {
+		return err
+	}
+	if svc.api.isDryRun(ctx) {
+		// The dynamic URL has not been invoked, so the record still points at the previous IP
+		return nil
+	}
+
+	previousIP := *lastIP
+	*lastIP = currentIP
+	if options.OnUpdate != nil {
+		options.OnUpdate(previousIP, currentIP)
+	}
+	if options.Store != nil {
+		return options.Store.Put(ctx, storeKey, []byte(currentIP.String()))
+	}
+
+	return nil
+}
@@ -0,0 +1,198 @@
+package cloudns
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRecordService_RunDynamicUpdater(t *testing.T) {
+	// given
+	ctx, cancel := context.WithCancel(context.Background())
+	var invocations int
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		if req.URL.Path == recordGetDynamicURL {
+			return `{"host":"home","url":"https://ipv4.cloudns.net/api/dynamicURL/?q=secret"}`
+		}
+
+		assert.Equal(t, "ipv4.cloudns.net", req.URL.Host)
+		assert.Equal(t, "secret", req.URL.Query().Get("q"))
+		invocations++
+		return `OK`
+	}, CustomClock(NewManualClock(time.Now())))
+
+	sources := []func() (net.IP, error){
+		func() (net.IP, error) { return net.ParseIP("192.0.2.1"), nil },
+		func() (net.IP, error) { return net.ParseIP("192.0.2.1"), nil },
+		func() (net.IP, error) { return nil, errors.New("lookup failed") },
+		func() (net.IP, error) { return net.ParseIP("192.0.2.2"), nil },
+	}
+	source := func(ctx context.Context) (net.IP, error) {
+		next := sources[0]
+		if sources = sources[1:]; len(sources) == 0 {
+			cancel()
+		}
+		return next()
+	}
+
+	// when
+	var updates [][]string
+	var updateErrors int
+	err := stubClient.Records.RunDynamicUpdater(ctx, testDomain, 1337, DynamicUpdaterOptions{
+		Source: source,
+		OnUpdate: func(previous, current net.IP) {
+			updates = append(updates, []string{previous.String(), current.String()})
+		},
+		OnError: func(err error) { updateErrors++ },
+	})
+
+	// then
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 2, invocations, "dynamic url should only be invoked when the ip changes")
+	assert.Equal(t, [][]string{{"<nil>", "192.0.2.1"}, {"192.0.2.1", "192.0.2.2"}}, updates)
+	assert.Equal(t, 1, updateErrors)
+}
+
+func TestRecordService_RunDynamicUpdater_Store(t *testing.T) {
+	// given
+	ctx, cancel := context.WithCancel(context.Background())
+	var invocations int
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		if req.URL.Path == recordGetDynamicURL {
+			return `{"host":"home","url":"https://ipv4.cloudns.net/api/dynamicURL/?q=secret"}`
+		}
+		invocations++
+		return `OK`
+	}, CustomClock(NewManualClock(time.Now())))
+
+	store := NewMemoryStore()
+	assert.NoError(t, store.Put(context.Background(), "dynamic-updater/api-example.com/1337", []byte("192.0.2.1")))
+	ips := []string{"192.0.2.1", "192.0.2.2"}
+	source := func(ctx context.Context) (net.IP, error) {
+		next := ips[0]
+		if ips = ips[1:]; len(ips) == 0 {
+			cancel()
+		}
+		return net.ParseIP(next), nil
+	}
+
+	// when
+	var updates [][]string
+	err := stubClient.Records.RunDynamicUpdater(ctx, testDomain, 1337, DynamicUpdaterOptions{
+		Source: source,
+		Store:  store,
+		OnUpdate: func(previous, current net.IP) {
+			updates = append(updates, []string{previous.String(), current.String()})
+		},
+	})
+
+	// then
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, invocations, "unchanged ip should not be updated again after restart")
+	assert.Equal(t, [][]string{{"192.0.2.1", "192.0.2.2"}}, updates)
+	value, _, _ := store.Get(context.Background(), "dynamic-updater/api-example.com/1337")
+	assert.Equal(t, "192.0.2.2", string(value), "ip of successful update should be persisted")
+}
+
+func TestRecordService_RunDynamicUpdater_DryRun(t *testing.T) {
+	// given
+	ctx, cancel := context.WithCancel(WithDryRun(context.Background()))
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		assert.Equal(t, recordGetDynamicURL, req.URL.Path, "dynamic url should not be invoked")
+		return `{"host":"home","url":"https://ipv4.cloudns.net/api/dynamicURL/?q=secret"}`
+	}, CustomClock(NewManualClock(time.Now())))
+
+	checks := 0
+	source := func(ctx context.Context) (net.IP, error) {
+		if checks++; checks == 2 {
+			cancel()
+		}
+		return net.ParseIP("192.0.2.1"), nil
+	}
+
+	// when
+	var updates int
+	err := stubClient.Records.RunDynamicUpdater(ctx, testDomain, 1337, DynamicUpdaterOptions{
+		Source:   source,
+		OnUpdate: func(previous, current net.IP) { updates++ },
+	})
+
+	// then
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Zero(t, updates, "dry runs should not be reported as updates")
+}
+
+func TestRecordService_RunDynamicUpdater_Disabled(t *testing.T) {
+	stubClient := newStubClient(t, func(req *http.Request) string { return `{"host":"home","url":""}` })
+	err := stubClient.Records.RunDynamicUpdater(context.Background(), testDomain, 1337, DynamicUpdaterOptions{})
+	assert.ErrorIs(t, err, ErrIllegalArgument)
+}
+
+func TestRecordService_UpdateDynamicURL_ReadOnly(t *testing.T) {
+	// given
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		t.Fatalf("unexpected request to %s", req.URL)
+		return ``
+	}, ReadOnly())
+
+	// when
+	err := stubClient.Records.UpdateDynamicURL(context.Background(), testDomain, DynamicURL{
+		Host: "home",
+		URL:  "https://ipv4.cloudns.net/api/dynamicURL/?q=secret",
+	})
+
+	// then
+	assert.ErrorIs(t, err, ErrReadOnlyClient)
+}
+
+func TestRecordService_UpdateDynamicURL_Failed(t *testing.T) {
+	// given
+	transport := stubTransport(func(req *http.Request) (*http.Response, error) {
+		response := newStubResponse(`Invalid request.`)
+		response.StatusCode = http.StatusNotFound
+		return response, nil
+	})
+	stubClient, _ := New(HTTPClient(&http.Client{Transport: transport}))
+
+	// when
+	err := stubClient.Records.UpdateDynamicURL(context.Background(), testDomain, DynamicURL{
+		URL: "https://ipv4.cloudns.net/api/dynamicURL/?q=invalid",
+	})
+
+	// then
+	assert.ErrorIs(t, err, ErrHTTPRequest)
+}
+
+func TestRecordService_UpdateDynamicURL_DryRun(t *testing.T) {
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		t.Fatalf("unexpected request to %s", req.URL)
+		return ``
+	})
+
+	err := stubClient.Records.UpdateDynamicURL(WithDryRun(context.Background()), testDomain, DynamicURL{
+		URL: "https://ipv4.cloudns.net/api/dynamicURL/?q=secret",
+	})
+	assert.NoError(t, err)
+}
+
+func TestRecordService_UpdateDynamicURL_Metrics(t *testing.T) {
+	// given
+	metrics := NewRequestMetrics()
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `OK`
+	}, Metrics(metrics))
+
+	// when
+	err := stubClient.Records.UpdateDynamicURL(context.Background(), testDomain, DynamicURL{
+		URL: "https://ipv4.cloudns.net/api/dynamicURL/?q=secret",
+	})
+
+	// then
+	assert.NoError(t, err)
+	key := requestMetricsKey{endpoint: "/api/dynamicURL/", status: RequestStatusSuccess}
+	assert.Equal(t, uint64(1), metrics.requests[key].count, "dynamic url should be reported without its token")
+}
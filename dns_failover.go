@@ -0,0 +1,268 @@
+package cloudns
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const failoverActivateURL = "/dns/failover-activate.json"
+const failoverDeactivateURL = "/dns/failover-deactivate.json"
+const failoverSettingsURL = "/dns/failover-settings.json"
+const failoverModifyURL = "/dns/failover-modify.json"
+const failoverStateURL = "/dns/failover-state.json"
+
+// failoverMaxBackupIPs is the maximum amount of backup IPs supported by ClouDNS per record
+const failoverMaxBackupIPs = 5
+
+// FailoverService is a service object which groups all operations related to ClouDNS failover and monitoring
+type FailoverService struct {
+	api *Client
+}
+
+// FailoverDownAction is an enumeration of all actions ClouDNS can take when a monitored record goes down
+type FailoverDownAction int
+
+// Enumeration values for FailoverDownAction
+const (
+	FailoverDownNothing FailoverDownAction = iota
+	FailoverDownDeactivateRecord
+	FailoverDownReplaceWithBackup
+)
+
+// FailoverUpAction is an enumeration of all actions ClouDNS can take when a monitored record comes up again
+type FailoverUpAction int
+
+// Enumeration values for FailoverUpAction
+const (
+	FailoverUpNothing FailoverUpAction = iota
+	FailoverUpActivateRecord
+	FailoverUpReturnToMain
+)
+
+// FailoverState is an enumeration of all states of a monitored record
+type FailoverState int
+
+// Enumeration values for FailoverState
+const (
+	FailoverStateUnknown FailoverState = iota
+	FailoverStateUp
+	FailoverStateDown
+)
+
+// String returns a human-readable name of the failover state
+func (state FailoverState) String() string {
+	switch state {
+	case FailoverStateUp:
+		return "up"
+	case FailoverStateDown:
+		return "down"
+	}
+
+	return "unknown"
+}
+
+// FailoverSettings represents the monitoring check and failover behavior of a single record
+type FailoverSettings struct {
+	// CheckType is the numeric type of the monitoring check, e.g. ping, HTTP or TCP, as listed by ClouDNS
+	CheckType int
+	// DownAction is taken as soon as the check considers the record to be down
+	DownAction FailoverDownAction
+	// UpAction is taken as soon as the check considers the record to be up again
+	UpAction FailoverUpAction
+	// MainIP is the IP address which is monitored and restored when returning to main
+	MainIP string
+	// BackupIPs are used in order when replacing the record with a backup, up to five addresses are supported
+	BackupIPs []string
+
+	// Host, Port, Path and Content are used by HTTP and TCP checks, depending on the check type
+	Host    string
+	Port    int
+	Path    string
+	Content string
+	// QueryType and QueryResponse are used by DNS checks
+	QueryType     string
+	QueryResponse string
+
+	// CheckPeriod is the interval between two checks in seconds
+	CheckPeriod int
+	// Timeout is the time in seconds after which a single check is considered as failed
+	Timeout int
+	// CheckRegion restricts the monitoring locations, e.g. `eu` or `us`, using all locations if empty
+	CheckRegion string
+	// NotificationMail receives notifications about state changes if not empty
+	NotificationMail string
+}
+
+// failoverSettingsJSON represents the flat representation of failover settings used by the ClouDNS API
+type failoverSettingsJSON struct {
+	CheckType        int    `json:"check_type,string"`
+	DownEventHandler int    `json:"down_event_handler,string"`
+	UpEventHandler   int    `json:"up_event_handler,string"`
+	MainIP           string `json:"main_ip"`
+	BackupIP1        string `json:"backup_ip_1"`
+	BackupIP2        string `json:"backup_ip_2"`
+	BackupIP3        string `json:"backup_ip_3"`
+	BackupIP4        string `json:"backup_ip_4"`
+	BackupIP5        string `json:"backup_ip_5"`
+	Host             string `json:"host"`
+	Port             int    `json:"port,string"`
+	Path             string `json:"path"`
+	Content          string `json:"content"`
+	QueryType        string `json:"query_type"`
+	QueryResponse    string `json:"query_response"`
+	CheckPeriod      int    `json:"check_period,string"`
+	Timeout          int    `json:"timeout,string"`
+	CheckRegion      string `json:"check_region"`
+	NotificationMail string `json:"notification_mail"`
+}
+
+// Activate enables monitoring and failover for the given record using the specified settings
+func (svc *FailoverService) Activate(ctx context.Context, zoneName string, recordID int, settings FailoverSettings) (result StatusResult, err error) {
+	params, err := settings.AsParams()
+	if err != nil {
+		return
+	}
+
+	params["domain-name"] = zoneName
+	params["record-id"] = recordID
+	err = svc.api.request(ctx, "POST", failoverActivateURL, params, nil, &result)
+	return
+}
+
+// Deactivate disables monitoring and failover for the given record
+func (svc *FailoverService) Deactivate(ctx context.Context, zoneName string, recordID int) (result StatusResult, err error) {
+	params := HTTPParams{"domain-name": zoneName, "record-id": recordID}
+	err = svc.api.request(ctx, "POST", failoverDeactivateURL, params, nil, &result)
+	return
+}
+
+// Get returns the current monitoring and failover settings of the given record
+func (svc *FailoverService) Get(ctx context.Context, zoneName string, recordID int) (result FailoverSettings, err error) {
+	params := HTTPParams{"domain-name": zoneName, "record-id": recordID}
+	err = svc.api.request(ctx, "POST", failoverSettingsURL, params, nil, &result)
+	return
+}
+
+// Update replaces the monitoring and failover settings of the given record, which must have failover activated. Unlike
+// Activate, empty optional fields like Port, NotificationMail or unused BackupIPs are sent explicitly and therefore
+// cleared, so settings should be retrieved with Get and modified instead of being built from scratch. CheckPeriod and
+// Timeout can not be cleared and keep their current values if zero.
+func (svc *FailoverService) Update(ctx context.Context, zoneName string, recordID int, settings FailoverSettings) (result StatusResult, err error) {
+	params, err := settings.asParams(true)
+	if err != nil {
+		return
+	}
+
+	params["domain-name"] = zoneName
+	params["record-id"] = recordID
+	err = svc.api.request(ctx, "POST", failoverModifyURL, params, nil, &result)
+	return
+}
+
+// GetState returns the current state of the monitoring check of the given record
+func (svc *FailoverService) GetState(ctx context.Context, zoneName string, recordID int) (FailoverState, error) {
+	var result struct {
+		State string `json:"state"`
+	}
+
+	params := HTTPParams{"domain-name": zoneName, "record-id": recordID}
+	if err := svc.api.request(ctx, "POST", failoverStateURL, params, nil, &result); err != nil {
+		return FailoverStateUnknown, err
+	}
+
+	switch strings.ToLower(result.State) {
+	case "up", "1":
+		return FailoverStateUp, nil
+	case "down", "0":
+		return FailoverStateDown, nil
+	}
+
+	return FailoverStateUnknown, nil
+}
+
+// AsParams returns the HTTP parameters for failover settings for use within the other API methods. Empty optional fields
+// are omitted, so that ClouDNS applies its defaults.
+func (settings FailoverSettings) AsParams() (HTTPParams, error) {
+	return settings.asParams(false)
+}
+
+// asParams returns the HTTP parameters for failover settings. If clear is true, empty optional fields which can be
+// cleared are sent as well instead of being omitted.
+func (settings FailoverSettings) asParams(clear bool) (HTTPParams, error) {
+	if len(settings.BackupIPs) > failoverMaxBackupIPs {
+		return nil, ErrIllegalArgument.wrap(fmt.Errorf("at most %d backup ips are supported", failoverMaxBackupIPs))
+	}
+
+	params := HTTPParams{
+		"check_type":         settings.CheckType,
+		"down_event_handler": int(settings.DownAction),
+		"up_event_handler":   int(settings.UpAction),
+		"main_ip":            settings.MainIP,
+	}
+	for index := 0; index < failoverMaxBackupIPs; index++ {
+		if index < len(settings.BackupIPs) {
+			params[fmt.Sprintf("backup_ip_%d", index+1)] = settings.BackupIPs[index]
+		} else if clear {
+			params[fmt.Sprintf("backup_ip_%d", index+1)] = ""
+		}
+	}
+
+	clearable := map[string]interface{}{
+		"host":              settings.Host,
+		"port":              settings.Port,
+		"path":              settings.Path,
+		"content":           settings.Content,
+		"query_type":        settings.QueryType,
+		"query_response":    settings.QueryResponse,
+		"check_region":      settings.CheckRegion,
+		"notification_mail": settings.NotificationMail,
+	}
+	for key, value := range clearable {
+		if clear || (value != "" && value != 0) {
+			params[key] = value
+		}
+	}
+
+	if settings.CheckPeriod != 0 {
+		params["check_period"] = settings.CheckPeriod
+	}
+	if settings.Timeout != 0 {
+		params["timeout"] = settings.Timeout
+	}
+
+	return params, nil
+}
+
+// UnmarshalJSON converts the flat representation of failover settings used by the ClouDNS API
+func (settings *FailoverSettings) UnmarshalJSON(data []byte) error {
+	var raw failoverSettingsJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*settings = FailoverSettings{
+		CheckType:        raw.CheckType,
+		DownAction:       FailoverDownAction(raw.DownEventHandler),
+		UpAction:         FailoverUpAction(raw.UpEventHandler),
+		MainIP:           raw.MainIP,
+		Host:             raw.Host,
+		Port:             raw.Port,
+		Path:             raw.Path,
+		Content:          raw.Content,
+		QueryType:        raw.QueryType,
+		QueryResponse:    raw.QueryResponse,
+		CheckPeriod:      raw.CheckPeriod,
+		Timeout:          raw.Timeout,
+		CheckRegion:      raw.CheckRegion,
+		NotificationMail: raw.NotificationMail,
+	}
+	for _, backupIP := range []string{raw.BackupIP1, raw.BackupIP2, raw.BackupIP3, raw.BackupIP4, raw.BackupIP5} {
+		if backupIP != "" {
+			settings.BackupIPs = append(settings.BackupIPs, backupIP)
+		}
+	}
+
+	return nil
+}
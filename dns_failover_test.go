@@ -0,0 +1,109 @@
+package cloudns
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestFailoverSettings_AsParams(t *testing.T) {
+	// given
+	settings := FailoverSettings{
+		CheckType:   17,
+		DownAction:  FailoverDownReplaceWithBackup,
+		UpAction:    FailoverUpReturnToMain,
+		MainIP:      "192.0.2.1",
+		BackupIPs:   []string{"192.0.2.2", "192.0.2.3"},
+		Host:        "www.api-example.com",
+		Port:        443,
+		CheckPeriod: 60,
+	}
+
+	// when
+	params, err := settings.AsParams()
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, HTTPParams{
+		"check_type":         17,
+		"down_event_handler": 2,
+		"up_event_handler":   2,
+		"main_ip":            "192.0.2.1",
+		"backup_ip_1":        "192.0.2.2",
+		"backup_ip_2":        "192.0.2.3",
+		"host":               "www.api-example.com",
+		"port":               443,
+		"check_period":       60,
+	}, params, "empty optional settings should be omitted")
+}
+
+func TestFailoverSettings_AsParams_TooManyBackups(t *testing.T) {
+	settings := FailoverSettings{BackupIPs: []string{"a", "b", "c", "d", "e", "f"}}
+
+	_, err := settings.AsParams()
+	assert.ErrorIs(t, err, ErrIllegalArgument)
+}
+
+func TestFailoverService_Update_ClearsFields(t *testing.T) {
+	// given
+	var params map[string]interface{}
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		_ = json.NewDecoder(req.Body).Decode(&params)
+		return `{"status":"Success","statusDescription":"OK"}`
+	})
+	settings := FailoverSettings{CheckType: 17, MainIP: "192.0.2.1", BackupIPs: []string{"192.0.2.2"}}
+
+	// when
+	_, err := stubClient.Failover.Update(context.Background(), testDomain, 1, settings)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, float64(0), params["port"], "empty port should be cleared")
+	assert.Equal(t, "", params["notification_mail"], "empty notification mail should be cleared")
+	assert.Equal(t, "192.0.2.2", params["backup_ip_1"])
+	assert.Equal(t, "", params["backup_ip_5"], "unused backup ips should be cleared")
+	assert.NotContains(t, params, "check_period", "check period should keep its current value")
+}
+
+func TestFailoverService_Get(t *testing.T) {
+	// given
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `{"check_type":"17","down_event_handler":"2","up_event_handler":"1","main_ip":"192.0.2.1",
+			"backup_ip_1":"192.0.2.2","backup_ip_2":"","port":"80","check_period":"300","timeout":"5"}`
+	})
+
+	// when
+	settings, err := stubClient.Failover.Get(context.Background(), testDomain, 1)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, FailoverSettings{
+		CheckType:   17,
+		DownAction:  FailoverDownReplaceWithBackup,
+		UpAction:    FailoverUpActivateRecord,
+		MainIP:      "192.0.2.1",
+		BackupIPs:   []string{"192.0.2.2"},
+		Port:        80,
+		CheckPeriod: 300,
+		Timeout:     5,
+	}, settings)
+}
+
+func TestFailoverService_GetState(t *testing.T) {
+	// given
+	var params map[string]interface{}
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		_ = json.NewDecoder(req.Body).Decode(&params)
+		return `{"state":"DOWN"}`
+	})
+
+	// when
+	state, err := stubClient.Failover.GetState(context.Background(), testDomain, 42)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, FailoverStateDown, state)
+	assert.EqualValues(t, 42, params["record-id"])
+}
@@ -0,0 +1,75 @@
+package cloudns
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const geoDNSLocationsURL = "/dns/get-geodns-locations.json"
+
+// GeoDNSLocation represents a location which can be assigned to records of GeoDNS zones, causing them to be only served
+// to clients within that location
+type GeoDNSLocation struct {
+	ID   int    `json:"id,string"`
+	Name string `json:"name"`
+	Code string `json:"code"`
+}
+
+// GeoDNSLocations represents a list of GeoDNS locations
+type GeoDNSLocations []GeoDNSLocation
+
+// GeoDNSLocations returns all GeoDNS locations available for the given zone sorted by their ID
+func (svc *RecordService) GeoDNSLocations(ctx context.Context, zoneName string) (GeoDNSLocations, error) {
+	var result map[string]GeoDNSLocation
+
+	params := HTTPParams{"domain-name": zoneName}
+	if err := svc.api.request(ctx, "POST", geoDNSLocationsURL, params, nil, &result); err != nil {
+		return nil, err
+	}
+
+	locations := make(GeoDNSLocations, 0, len(result))
+	for _, location := range result {
+		locations = append(locations, location)
+	}
+	sort.Slice(locations, func(i, j int) bool {
+		return locations[i].ID < locations[j].ID
+	})
+
+	return locations, nil
+}
+
+// WithGeoDNSLocation returns a copy of the record assigned to the GeoDNS location with the given name or code, which is
+// resolved using the locations available for the given zone
+func (svc *RecordService) WithGeoDNSLocation(ctx context.Context, zoneName string, record Record, location string) (Record, error) {
+	locations, err := svc.GeoDNSLocations(ctx, zoneName)
+	if err != nil {
+		return record, err
+	}
+
+	return locations.Apply(record, location)
+}
+
+// Find returns the location with the given name or code, compared case-insensitively, or false if no such location
+// exists
+func (locations GeoDNSLocations) Find(location string) (GeoDNSLocation, bool) {
+	for _, candidate := range locations {
+		if strings.EqualFold(candidate.Name, location) || (candidate.Code != "" && strings.EqualFold(candidate.Code, location)) {
+			return candidate, true
+		}
+	}
+
+	return GeoDNSLocation{}, false
+}
+
+// Apply returns a copy of the record assigned to the location with the given name or code
+func (locations GeoDNSLocations) Apply(record Record, location string) (Record, error) {
+	match, ok := locations.Find(location)
+	if !ok {
+		return record, ErrIllegalArgument.wrap(fmt.Errorf("unknown geodns location: %s", location))
+	}
+
+	record.GeoDNSLocationID = match.ID
+	return record, nil
+}
@@ -0,0 +1,80 @@
+package cloudns
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRecordService_GeoDNSLocations(t *testing.T) {
+	// given
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `{"Default":{"id":"0","name":"Default","code":""},"Europe":{"id":"2","name":"Europe","code":"EU"},
+			"Germany":{"id":"38","name":"Germany","code":"DE"}}`
+	})
+
+	// when
+	locations, err := stubClient.Records.GeoDNSLocations(context.Background(), testDomain)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, GeoDNSLocations{
+		{ID: 0, Name: "Default"},
+		{ID: 2, Name: "Europe", Code: "EU"},
+		{ID: 38, Name: "Germany", Code: "DE"},
+	}, locations)
+
+	location, ok := locations.Find("germany")
+	assert.True(t, ok)
+	assert.Equal(t, 38, location.ID)
+	location, ok = locations.Find("eu")
+	assert.True(t, ok, "locations should be found by code")
+	assert.Equal(t, 2, location.ID)
+	_, ok = locations.Find("")
+	assert.False(t, ok, "empty codes should not match")
+}
+
+func TestRecordService_GeoDNSLocations_Cached(t *testing.T) {
+	// given
+	requests := 0
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		requests++
+		return `{"Germany":{"id":"38","name":"Germany","code":"DE"}}`
+	}, ResponseCache(NewMemoryCache(), time.Hour))
+
+	// when
+	_, err1 := stubClient.Records.GeoDNSLocations(context.Background(), testDomain)
+	_, err2 := stubClient.Records.GeoDNSLocations(context.Background(), testDomain)
+
+	// then
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+	assert.Equal(t, 1, requests, "locations should be served from the response cache")
+}
+
+func TestRecordService_WithGeoDNSLocation(t *testing.T) {
+	// given
+	var createParams map[string]interface{}
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		if req.URL.Path == geoDNSLocationsURL {
+			return `{"Germany":{"id":"38","name":"Germany","code":"DE"}}`
+		}
+		_ = json.NewDecoder(req.Body).Decode(&createParams)
+		return `{"status":"Success","statusDescription":"The record was added successfully."}`
+	})
+
+	// when
+	record, err := stubClient.Records.WithGeoDNSLocation(context.Background(), testDomain, NewRecordA("www", "192.0.2.1", testTTL), "Germany")
+	_, createErr := stubClient.Records.Create(context.Background(), testDomain, record)
+	_, unknownErr := stubClient.Records.WithGeoDNSLocation(context.Background(), testDomain, record, "Mars")
+
+	// then
+	assert.NoError(t, err)
+	assert.NoError(t, createErr)
+	assert.Equal(t, 38, record.GeoDNSLocationID)
+	assert.EqualValues(t, 38, createParams["geodns-location"], "location should be sent when creating records")
+	assert.ErrorIs(t, unknownErr, ErrIllegalArgument)
+}
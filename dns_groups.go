@@ -0,0 +1,81 @@
+package cloudns
+
+import (
+	"context"
+	"sort"
+)
+
+const groupListURL = "/dns/list-groups.json"
+const groupCreateURL = "/dns/add-group.json"
+const groupRenameURL = "/dns/rename-group.json"
+const groupDeleteURL = "/dns/delete-group.json"
+const groupChangeURL = "/dns/change-group.json"
+
+// ZoneGroup represents a group of zones, which can be used for organizing zones and filtering ZoneService.Search
+type ZoneGroup struct {
+	ID   int    `json:"id,string"`
+	Name string `json:"name"`
+}
+
+// Groups returns all zone groups of the account sorted by their ID
+func (svc *ZoneService) Groups(ctx context.Context) ([]ZoneGroup, error) {
+	var result map[string]ZoneGroup
+	if err := svc.api.request(ctx, "POST", groupListURL, nil, nil, &result); err != nil {
+		return nil, err
+	}
+
+	groups := make([]ZoneGroup, 0, len(result))
+	for _, group := range result {
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].ID < groups[j].ID
+	})
+
+	return groups, nil
+}
+
+// CreateGroup creates a new zone group with the given name, into which the given zone is moved, as ClouDNS does not
+// support empty groups
+func (svc *ZoneService) CreateGroup(ctx context.Context, groupName, zoneName string) (result StatusResult, err error) {
+	params := HTTPParams{"domain-name": zoneName, "name": groupName}
+	err = svc.api.request(ctx, "POST", groupCreateURL, params, nil, &result)
+	return
+}
+
+// RenameGroup changes the name of the zone group with the given ID
+func (svc *ZoneService) RenameGroup(ctx context.Context, groupID int, groupName string) (result StatusResult, err error) {
+	params := HTTPParams{"group-id": groupID, "new-name": groupName}
+	err = svc.api.request(ctx, "POST", groupRenameURL, params, nil, &result)
+	return
+}
+
+// DeleteGroup deletes the zone group with the given ID. Zones within the group are kept, but no longer belong to a group.
+func (svc *ZoneService) DeleteGroup(ctx context.Context, groupID int) (result StatusResult, err error) {
+	params := HTTPParams{"group-id": groupID}
+	err = svc.api.request(ctx, "POST", groupDeleteURL, params, nil, &result)
+	return
+}
+
+// SetGroup moves the given zone into the zone group with the given ID
+func (svc *ZoneService) SetGroup(ctx context.Context, zoneName string, groupID int) (result StatusResult, err error) {
+	params := HTTPParams{"domain-name": zoneName, "group-id": groupID}
+	err = svc.api.request(ctx, "POST", groupChangeURL, params, nil, &result)
+	return
+}
+
+// GroupByName returns the zone group with the given name, or false if no such group exists
+func (svc *ZoneService) GroupByName(ctx context.Context, groupName string) (ZoneGroup, bool, error) {
+	groups, err := svc.Groups(ctx)
+	if err != nil {
+		return ZoneGroup{}, false, err
+	}
+
+	for _, group := range groups {
+		if group.Name == groupName {
+			return group, true, nil
+		}
+	}
+
+	return ZoneGroup{}, false, nil
+}
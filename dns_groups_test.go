@@ -0,0 +1,56 @@
+package cloudns
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestZoneService_Groups(t *testing.T) {
+	// given
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `{"21":{"id":"21","name":"internal"},"4":{"id":"4","name":"external"}}`
+	})
+
+	// when
+	groups, err := stubClient.Zones.Groups(context.Background())
+	group, found, groupErr := stubClient.Zones.GroupByName(context.Background(), "internal")
+	_, missing, _ := stubClient.Zones.GroupByName(context.Background(), "unknown")
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []ZoneGroup{{ID: 4, Name: "external"}, {ID: 21, Name: "internal"}}, groups)
+	assert.NoError(t, groupErr)
+	assert.True(t, found)
+	assert.Equal(t, 21, group.ID)
+	assert.False(t, missing)
+}
+
+func TestZoneService_Groups_Empty(t *testing.T) {
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `[]`
+	})
+
+	groups, err := stubClient.Zones.Groups(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, groups)
+}
+
+func TestZoneService_SetGroup(t *testing.T) {
+	// given
+	var params map[string]interface{}
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		_ = json.NewDecoder(req.Body).Decode(&params)
+		return `{"status":"Success","statusDescription":"The group was changed successfully."}`
+	})
+
+	// when
+	_, err := stubClient.Zones.SetGroup(context.Background(), testDomain, 21)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, testDomain, params["domain-name"])
+	assert.EqualValues(t, 21, params["group-id"])
+}
@@ -0,0 +1,157 @@
+package cloudns
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// NameserverLatency represents the query latency measured from the client to a single nameserver
+type NameserverLatency struct {
+	Nameserver
+	// UDP and TCP contain the median round-trip time of all successful probes of the respective protocol, which is zero
+	// if the protocol has not been probed or all probes failed
+	UDP time.Duration
+	TCP time.Duration
+	// Error contains the error of the last failed probe, if all probes of any protocol failed
+	Error error
+}
+
+// LatencyProbeOptions specifies how the latency to nameservers is measured
+type LatencyProbeOptions struct {
+	// Probes is the amount of queries sent per nameserver and protocol, defaulting to 3
+	Probes int
+	// Timeout limits the time waited for every single query, defaulting to 2 seconds
+	Timeout time.Duration
+	// TCP enables probing via TCP in addition to UDP, which includes establishing the connection
+	TCP bool
+	// IPv6 probes the IPv6 addresses of the nameservers instead of their IPv4 addresses
+	IPv6 bool
+	// Concurrency is the amount of nameservers probed in parallel, defaulting to 8
+	Concurrency int
+	// Port is the port the nameservers are probed on, defaulting to 53 or the DNS port of the client for
+	// ZoneService.MeasureNameserverLatency
+	Port int
+}
+
+// MeasureNameserverLatency measures the query latency from the client to each nameserver available to the account,
+// see MeasureNameserverLatency for details
+func (svc *ZoneService) MeasureNameserverLatency(ctx context.Context, options LatencyProbeOptions) ([]NameserverLatency, error) {
+	available, err := svc.AvailableNameservers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Port <= 0 {
+		options.Port = svc.api.dnsPort
+	}
+
+	return MeasureNameserverLatency(ctx, available, options)
+}
+
+// MeasureNameserverLatency measures the query latency from the client to each of the given nameservers by sending SOA
+// queries via UDP and optionally TCP, which helps to choose nameservers close to the users of a region. Every answer
+// counts as successful probe regardless of its response code, as nameservers might refuse queries for unknown zones.
+// The results are sorted by UDP latency, followed by all nameservers which could not be reached.
+func MeasureNameserverLatency(ctx context.Context, nameservers []Nameserver, options LatencyProbeOptions) ([]NameserverLatency, error) {
+	if options.Probes <= 0 {
+		options.Probes = 3
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = 2 * time.Second
+	}
+	if options.Concurrency <= 0 {
+		options.Concurrency = 8
+	}
+	if options.Port <= 0 {
+		options.Port = 53
+	}
+
+	results := make([]NameserverLatency, len(nameservers))
+	semaphore := make(chan struct{}, options.Concurrency)
+	var wg sync.WaitGroup
+	for index, nameserver := range nameservers {
+		wg.Add(1)
+		go func(index int, nameserver Nameserver) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			results[index] = probeNameserver(ctx, nameserver, options)
+		}(index, nameserver)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if (results[i].UDP == 0) != (results[j].UDP == 0) {
+			return results[i].UDP != 0
+		}
+		return results[i].UDP < results[j].UDP
+	})
+
+	return results, nil
+}
+
+// probeNameserver measures the latency to a single nameserver using all protocols enabled by the options
+func probeNameserver(ctx context.Context, nameserver Nameserver, options LatencyProbeOptions) NameserverLatency {
+	result := NameserverLatency{Nameserver: nameserver}
+
+	address, family := nameserver.IPv4, "ipv4"
+	if options.IPv6 {
+		address, family = nameserver.IPv6, "ipv6"
+	}
+	if address == nil {
+		result.Error = ErrDNSQuery.wrap(fmt.Errorf("nameserver %s has no %s address", nameserver.Name, family))
+		return result
+	}
+
+	result.UDP, result.Error = probeLatency(ctx, "udp", address.String(), options)
+	if options.TCP {
+		var err error
+		if result.TCP, err = probeLatency(ctx, "tcp", address.String(), options); err != nil {
+			result.Error = err
+		}
+	}
+
+	return result
+}
+
+// probeLatency sends the configured amount of probes to the server and returns the median round-trip time of all
+// successful ones, or the error of the last probe if all of them failed
+func probeLatency(ctx context.Context, network, server string, options LatencyProbeOptions) (time.Duration, error) {
+	client := &dns.Client{Net: network, Timeout: options.Timeout}
+	msg := new(dns.Msg)
+	msg.SetQuestion(".", dns.TypeSOA)
+	msg.RecursionDesired = false
+
+	var rtts []time.Duration
+	var lastErr error
+	for probe := 0; probe < options.Probes && ctx.Err() == nil; probe++ {
+		_, rtt, err := client.ExchangeContext(ctx, msg, net.JoinHostPort(server, strconv.Itoa(options.Port)))
+		if err != nil {
+			lastErr = ErrDNSQuery.wrap(err)
+			continue
+		}
+		rtts = append(rtts, rtt)
+	}
+
+	if len(rtts) == 0 {
+		if lastErr == nil {
+			lastErr = ctx.Err()
+		}
+		return 0, lastErr
+	}
+
+	sort.Slice(rtts, func(i, j int) bool { return rtts[i] < rtts[j] })
+	return rtts[len(rtts)/2], nil
+}
@@ -0,0 +1,100 @@
+package cloudns
+
+import (
+	"context"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// startTestTCPDNSServer starts a local DNS server on the given loopback address using TCP and the given port, which has
+// usually been chosen by startTestUDPDNSServers
+func startTestTCPDNSServer(t *testing.T, handler dns.HandlerFunc, address string, port int) {
+	listener, err := net.Listen("tcp", net.JoinHostPort(address, strconv.Itoa(port)))
+	if err != nil {
+		t.Skipf("could not listen on %s for test dns server: %v", address, err)
+	}
+
+	server := &dns.Server{Listener: listener, Handler: handler}
+	go func() {
+		_ = server.ActivateAndServe()
+	}()
+	t.Cleanup(func() {
+		_ = server.Shutdown()
+	})
+}
+
+func newRefusingDNSHandler() dns.HandlerFunc {
+	return func(w dns.ResponseWriter, req *dns.Msg) {
+		resp := new(dns.Msg)
+		resp.SetRcode(req, dns.RcodeRefused)
+		_ = w.WriteMsg(resp)
+	}
+}
+
+func TestMeasureNameserverLatency(t *testing.T) {
+	// given
+	handler := newRefusingDNSHandler()
+	port := startTestUDPDNSServers(t, handler, "127.0.0.1")
+	startTestTCPDNSServer(t, handler, "127.0.0.1", port)
+	nameservers := []Nameserver{
+		{Name: "ns-missing.example", IPv6: net.ParseIP("::1")},
+		{Name: "ns1.example", IPv4: net.ParseIP("127.0.0.1")},
+	}
+
+	// when
+	results, err := MeasureNameserverLatency(context.Background(), nameservers, LatencyProbeOptions{Probes: 2, TCP: true, Port: port})
+
+	// then
+	assert.NoError(t, err)
+	if assert.Len(t, results, 2) {
+		assert.Equal(t, "ns1.example", results[0].Name, "reachable nameservers should be sorted first")
+		assert.NoError(t, results[0].Error, "refused queries should count as successful probes")
+		assert.Positive(t, results[0].UDP)
+		assert.Positive(t, results[0].TCP)
+
+		assert.Equal(t, "ns-missing.example", results[1].Name)
+		assert.ErrorIs(t, results[1].Error, ErrDNSQuery)
+		assert.Zero(t, results[1].UDP)
+	}
+}
+
+func TestMeasureNameserverLatency_Unreachable(t *testing.T) {
+	// given
+	port := startTestUDPDNSServers(t, func(w dns.ResponseWriter, req *dns.Msg) {}, "127.0.0.1")
+	nameservers := []Nameserver{{Name: "ns1.example", IPv4: net.ParseIP("127.0.0.1")}}
+
+	// when
+	results, err := MeasureNameserverLatency(context.Background(), nameservers, LatencyProbeOptions{
+		Probes:  1,
+		Timeout: 50 * time.Millisecond,
+		Port:    port,
+	})
+
+	// then
+	assert.NoError(t, err)
+	assert.Zero(t, results[0].UDP)
+	assert.ErrorIs(t, results[0].Error, ErrDNSQuery)
+}
+
+func TestZoneService_MeasureNameserverLatency(t *testing.T) {
+	// given
+	port := startTestUDPDNSServers(t, newRefusingDNSHandler(), "127.0.0.1")
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `[{"type":"free","name":"ns1.example","ip4":"127.0.0.1","ip6":"::1","location":"Local","location_cc":"XX"}]`
+	}, DNSPort(port))
+
+	// when
+	results, err := stubClient.Zones.MeasureNameserverLatency(context.Background(), LatencyProbeOptions{Probes: 1})
+
+	// then
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, "Local", results[0].Location, "nameserver details should be retained")
+		assert.Positive(t, results[0].UDP)
+	}
+}
@@ -0,0 +1,69 @@
+package cloudns
+
+import (
+	"context"
+	"sort"
+)
+
+const mailForwardListURL = "/dns/mail-forwards.json"
+const mailForwardCreateURL = "/dns/add-mail-forward.json"
+const mailForwardDeleteURL = "/dns/delete-mail-forward.json"
+
+// MailForwardService is a service object which groups all operations related to mail forwarding of ClouDNS zones
+type MailForwardService struct {
+	api *Client
+}
+
+// MailForwardMap represents a map of mail forwards indexed by the mail forward ID
+type MailForwardMap map[int]MailForward
+
+// MailForward forwards mails sent to a mailbox within a zone to another address. Mails to `info@mail.example.com` are
+// represented with the box `info` and the host `mail`, while an empty host refers to the zone apex. The box `*` acts as
+// catch-all for the host.
+type MailForward struct {
+	ID          int     `json:"id,string,omitempty"`
+	Box         string  `json:"box"`
+	Host        string  `json:"host"`
+	Destination string  `json:"destination"`
+	IsActive    APIBool `json:"status"`
+}
+
+// List returns all mail forwards of the given zone
+func (svc *MailForwardService) List(ctx context.Context, zoneName string) (result MailForwardMap, err error) {
+	params := HTTPParams{"domain-name": zoneName}
+	err = svc.api.request(ctx, "POST", mailForwardListURL, params, nil, &result)
+	return
+}
+
+// Create adds a new mail forward to the given zone
+func (svc *MailForwardService) Create(ctx context.Context, zoneName string, forward MailForward) (result StatusResult, err error) {
+	params := HTTPParams{
+		"domain-name": zoneName,
+		"box":         forward.Box,
+		"host":        forward.Host,
+		"destination": forward.Destination,
+	}
+
+	err = svc.api.request(ctx, "POST", mailForwardCreateURL, params, nil, &result)
+	return
+}
+
+// Delete removes the mail forward with the given ID from the zone
+func (svc *MailForwardService) Delete(ctx context.Context, zoneName string, forwardID int) (result StatusResult, err error) {
+	params := HTTPParams{"domain-name": zoneName, "mail-forward-id": forwardID}
+	err = svc.api.request(ctx, "POST", mailForwardDeleteURL, params, nil, &result)
+	return
+}
+
+// SortedSlice converts a MailForwardMap to a slice of mail forwards which is sorted by the mail forward ID
+func (mfm MailForwardMap) SortedSlice() []MailForward {
+	results := make([]MailForward, 0, len(mfm))
+	for _, value := range mfm {
+		results = append(results, value)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].ID < results[j].ID
+	})
+
+	return results
+}
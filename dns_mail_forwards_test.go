@@ -0,0 +1,59 @@
+package cloudns
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestMailForwardService_List(t *testing.T) {
+	// given
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `{
+			"12":{"id":"12","box":"*","host":"","destination":"catchall@example.net","status":"0"},
+			"7":{"id":"7","box":"info","host":"mail","destination":"info@example.net","status":"1"}
+		}`
+	})
+
+	// when
+	forwards, err := stubClient.MailForwards.List(context.Background(), testDomain)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []MailForward{
+		{ID: 7, Box: "info", Host: "mail", Destination: "info@example.net", IsActive: true},
+		{ID: 12, Box: "*", Host: "", Destination: "catchall@example.net", IsActive: false},
+	}, forwards.SortedSlice())
+}
+
+func TestMailForwardService_List_Empty(t *testing.T) {
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `[]`
+	})
+
+	forwards, err := stubClient.MailForwards.List(context.Background(), testDomain)
+	assert.NoError(t, err)
+	assert.Empty(t, forwards)
+}
+
+func TestMailForwardService_Create(t *testing.T) {
+	// given
+	var params map[string]interface{}
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		_ = json.NewDecoder(req.Body).Decode(&params)
+		return `{"status":"Success","statusDescription":"Mail forward was added successfully."}`
+	})
+
+	// when
+	forward := MailForward{Box: "info", Destination: "info@example.net"}
+	result, err := stubClient.MailForwards.Create(context.Background(), testDomain, forward)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "Success", result.Status)
+	assert.Equal(t, "info", params["box"])
+	assert.Equal(t, "", params["host"])
+	assert.Equal(t, "info@example.net", params["destination"])
+}
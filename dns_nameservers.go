@@ -0,0 +1,223 @@
+package cloudns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// NameserverRotation describes the migration of zones from one set of nameservers to another one
+type NameserverRotation struct {
+	// From contains the nameservers which should be replaced. Apex NS records pointing to other nameservers are kept.
+	From []string
+	// To contains the nameservers which should be used instead, all of which must be available to the account
+	To []string
+	// TTL is used for all newly created NS records
+	TTL int
+	// StageSize specifies the amount of zones which are migrated per stage. Zero migrates all zones in a single stage.
+	StageSize int
+	// AfterStage is called after every completed stage including the final one with the results of all zones migrated
+	// so far, e.g. for verifying resolution or waiting for propagation. Returning an error aborts the rollout before the
+	// next stage and is returned by RotateNameservers.
+	AfterStage func(ctx context.Context, results []NameserverRotationResult) error
+}
+
+// NameserverRotationResult contains all changes performed for a single zone during a nameserver rotation
+type NameserverRotationResult struct {
+	Zone    string
+	Created []Record
+	Deleted []Record
+}
+
+// RotateNameservers replaces the apex NS records of all given zones pointing to one set of nameservers with another
+// set, e.g. when migrating to premium or DDoS-protected nameservers. Zones which do not use any of the old nameservers
+// are skipped. New NS records are created before old ones get removed. The rollout stops at the first failure and
+// returns the results of all zones migrated until then.
+func (svc *ZoneService) RotateNameservers(ctx context.Context, zoneNames []string, rotation NameserverRotation) (results []NameserverRotationResult, err error) {
+	if len(rotation.From) == 0 || len(rotation.To) == 0 {
+		return nil, ErrIllegalArgument.wrap(errors.New("rotation requires both old and new nameservers"))
+	}
+
+	available, err := svc.AvailableNameservers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	availableNames := make([]string, 0, len(available))
+	for _, nameserver := range available {
+		availableNames = append(availableNames, normalizeHostname(nameserver.Name))
+	}
+	for _, nameserver := range rotation.To {
+		if !containsString(normalizeHostname(nameserver), availableNames) {
+			return nil, ErrIllegalArgument.wrap(fmt.Errorf("nameserver %s is not available for this account", nameserver))
+		}
+	}
+
+	stageSize := rotation.StageSize
+	if stageSize <= 0 {
+		stageSize = len(zoneNames)
+	}
+
+	for stageStart := 0; stageStart < len(zoneNames); stageStart += stageSize {
+		stageEnd := stageStart + stageSize
+		if stageEnd > len(zoneNames) {
+			stageEnd = len(zoneNames)
+		}
+
+		for _, zoneName := range zoneNames[stageStart:stageEnd] {
+			result, err := svc.rotateZoneNameservers(ctx, zoneName, rotation)
+			if err != nil {
+				return results, err
+			}
+			if len(result.Created) > 0 || len(result.Deleted) > 0 {
+				results = append(results, result)
+			}
+		}
+
+		if rotation.AfterStage != nil {
+			if err := rotation.AfterStage(ctx, results); err != nil {
+				return results, err
+			}
+		}
+	}
+
+	return results, nil
+}
+
+func (svc *ZoneService) rotateZoneNameservers(ctx context.Context, zoneName string, rotation NameserverRotation) (result NameserverRotationResult, err error) {
+	result.Zone = zoneName
+
+	records, err := svc.api.Records.Search(ctx, zoneName, "", RecordTypeNS)
+	if err != nil {
+		return
+	}
+
+	create, remove := planNameserverRotation(records, rotation)
+	for _, record := range create {
+		if _, err = svc.api.Records.Create(ctx, zoneName, record); err != nil {
+			return
+		}
+		result.Created = append(result.Created, record)
+	}
+	for _, record := range remove {
+		if _, err = svc.api.Records.Delete(ctx, zoneName, record.ID); err != nil {
+			return
+		}
+		result.Deleted = append(result.Deleted, record)
+	}
+
+	return
+}
+
+// planNameserverRotation returns the apex NS records which have to be created and removed for the given rotation. No
+// changes are planned if none of the existing NS records points to one of the old nameservers.
+func planNameserverRotation(records RecordMap, rotation NameserverRotation) (create, remove []Record) {
+	from := make([]string, 0, len(rotation.From))
+	for _, nameserver := range rotation.From {
+		from = append(from, normalizeHostname(nameserver))
+	}
+
+	var existing []string
+	for _, record := range records.SortedSlice() {
+		if record.RecordType != RecordTypeNS || (record.Host != "" && record.Host != "@") {
+			continue
+		}
+
+		target := normalizeHostname(record.Record)
+		if containsString(target, from) {
+			remove = append(remove, record)
+		} else {
+			existing = append(existing, target)
+		}
+	}
+
+	if len(remove) == 0 {
+		return nil, nil
+	}
+
+	for _, nameserver := range rotation.To {
+		nameserver = normalizeHostname(nameserver)
+		if !containsString(nameserver, existing) {
+			create = append(create, NewRecordNS("", nameserver, rotation.TTL))
+			existing = append(existing, nameserver)
+		}
+	}
+
+	return create, remove
+}
+
+// NameserverPolicy specifies how nameservers are selected from the nameservers available to the account, e.g. for
+// ZoneCreateOptions.Nameservers
+type NameserverPolicy struct {
+	// Types restricts the selection to nameservers of the given types, e.g. `premium`, allowing all types if empty
+	Types []string
+	// PreferDDoSProtected selects DDoS-protected nameservers before all others
+	PreferDDoSProtected bool
+	// PreferredCountries contains country codes in descending order of preference, e.g. `DE` or `US`. Nameservers in
+	// other countries are only selected after all nameservers in preferred countries.
+	PreferredCountries []string
+	// MinCount is the minimum amount of nameservers which must be selected, otherwise an error is returned
+	MinCount int
+	// MaxCount is the maximum amount of selected nameservers, selecting all matching nameservers if zero
+	MaxCount int
+}
+
+// SelectNameservers selects nameservers available to the account according to the given policy and returns their names
+func (svc *ZoneService) SelectNameservers(ctx context.Context, policy NameserverPolicy) ([]string, error) {
+	available, err := svc.AvailableNameservers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return SelectNameservers(available, policy)
+}
+
+// SelectNameservers selects nameservers from the given list according to the policy and returns their names. The order
+// of the result is deterministic: preferred nameservers come first, ties are broken by name.
+func SelectNameservers(available []Nameserver, policy NameserverPolicy) ([]string, error) {
+	var candidates []Nameserver
+	for _, nameserver := range available {
+		matches := len(policy.Types) == 0
+		for _, nameserverType := range policy.Types {
+			matches = matches || strings.EqualFold(nameserverType, nameserver.Type)
+		}
+		if matches {
+			candidates = append(candidates, nameserver)
+		}
+	}
+
+	countryRank := func(nameserver Nameserver) int {
+		for index, country := range policy.PreferredCountries {
+			if strings.EqualFold(country, nameserver.CountryCode) {
+				return index
+			}
+		}
+		return len(policy.PreferredCountries)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if policy.PreferDDoSProtected && a.DDoSProtected != b.DDoSProtected {
+			return bool(a.DDoSProtected)
+		}
+		if rankA, rankB := countryRank(a), countryRank(b); rankA != rankB {
+			return rankA < rankB
+		}
+		return a.Name < b.Name
+	})
+
+	if policy.MaxCount > 0 && len(candidates) > policy.MaxCount {
+		candidates = candidates[:policy.MaxCount]
+	}
+	if len(candidates) < policy.MinCount {
+		return nil, ErrIllegalArgument.wrap(fmt.Errorf("only %d of at least %d nameservers match the policy",
+			len(candidates), policy.MinCount))
+	}
+
+	names := make([]string, 0, len(candidates))
+	for _, nameserver := range candidates {
+		names = append(names, nameserver.Name)
+	}
+
+	return names, nil
+}
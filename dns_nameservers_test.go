@@ -0,0 +1,123 @@
+package cloudns
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestPlanNameserverRotation(t *testing.T) {
+	// given
+	records := buildRecordMap(
+		NewRecordNS("", "ns1.cloudns.net", testTTL),
+		NewRecordNS("", "NS2.cloudns.net.", testTTL),
+		NewRecordNS("", "pns1.cloudns.net", testTTL),
+		NewRecordNS("sub", "ns1.cloudns.net", testTTL),
+	)
+	rotation := NameserverRotation{
+		From: []string{"ns1.cloudns.net", "ns2.cloudns.net"},
+		To:   []string{"pns1.cloudns.net", "pns2.cloudns.net"},
+		TTL:  testTTL,
+	}
+
+	// when
+	create, remove := planNameserverRotation(records, rotation)
+
+	// then
+	assert.Equal(t, []Record{NewRecordNS("", "pns2.cloudns.net", testTTL)}, create)
+	assert.Equal(t, []Record{records[1], records[2]}, remove)
+}
+
+func TestPlanNameserverRotation_Unaffected(t *testing.T) {
+	records := buildRecordMap(NewRecordNS("", "ns1.provider.local", testTTL))
+	rotation := NameserverRotation{From: []string{"ns1.cloudns.net"}, To: []string{"pns1.cloudns.net"}}
+
+	create, remove := planNameserverRotation(records, rotation)
+	assert.Empty(t, create, "zone without old nameservers should not be changed")
+	assert.Empty(t, remove, "zone without old nameservers should not be changed")
+}
+
+func TestZoneService_RotateNameservers_Staged(t *testing.T) {
+	// given
+	var deleted []string
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		switch req.URL.Path {
+		case zoneAvailableNameserversURL:
+			return `[{"type":"premium","name":"pns1.cloudns.net"}]`
+		case recordListURL:
+			return `{"1":{"id":"1","host":"","record":"ns1.cloudns.net","type":"NS","ttl":"3600","status":1}}`
+		case recordDeleteURL:
+			body, _ := io.ReadAll(req.Body)
+			deleted = append(deleted, string(body))
+		}
+		return `{"status":"Success","statusDescription":"OK"}`
+	})
+
+	var stages [][]NameserverRotationResult
+	rotation := NameserverRotation{
+		From:      []string{"ns1.cloudns.net"},
+		To:        []string{"pns1.cloudns.net"},
+		TTL:       testTTL,
+		StageSize: 2,
+		AfterStage: func(ctx context.Context, results []NameserverRotationResult) error {
+			stages = append(stages, results)
+			return nil
+		},
+	}
+
+	// when
+	results, err := stubClient.Zones.RotateNameservers(context.Background(), []string{"a.local", "b.local", "c.local"}, rotation)
+
+	// then
+	assert.NoError(t, err)
+	assert.Len(t, results, 3, "all zones should be migrated")
+	assert.Len(t, deleted, 3, "old nameserver should be deleted in all zones")
+	assert.Len(t, stages, 2, "callback should be called after every stage")
+	assert.Len(t, stages[0], 2, "first stage should contain two zones")
+	assert.Len(t, stages[1], 3, "final stage should contain all zones")
+}
+
+func TestZoneService_RotateNameservers_Unavailable(t *testing.T) {
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `[{"type":"free","name":"ns1.cloudns.net"}]`
+	})
+
+	rotation := NameserverRotation{From: []string{"ns1.cloudns.net"}, To: []string{"pns1.cloudns.net"}}
+	_, err := stubClient.Zones.RotateNameservers(context.Background(), []string{"a.local"}, rotation)
+	assert.True(t, errors.Is(err, ErrIllegalArgument), "unavailable target nameserver should be rejected")
+}
+
+func TestSelectNameservers(t *testing.T) {
+	// given
+	available := []Nameserver{
+		{Type: "free", Name: "ns1.cloudns.net", CountryCode: "DE"},
+		{Type: "premium", Name: "pns2.cloudns.net", CountryCode: "US"},
+		{Type: "premium", Name: "pns1.cloudns.net", CountryCode: "DE"},
+		{Type: "premium", Name: "pns3.cloudns.net", CountryCode: "US", DDoSProtected: true},
+		{Type: "premium", Name: "pns4.cloudns.net", CountryCode: "JP"},
+	}
+	policy := NameserverPolicy{
+		Types:               []string{"Premium"},
+		PreferDDoSProtected: true,
+		PreferredCountries:  []string{"de"},
+		MinCount:            2,
+		MaxCount:            3,
+	}
+
+	// when
+	names, err := SelectNameservers(available, policy)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"pns3.cloudns.net", "pns1.cloudns.net", "pns2.cloudns.net"}, names)
+}
+
+func TestSelectNameservers_MinCount(t *testing.T) {
+	available := []Nameserver{{Type: "free", Name: "ns1.cloudns.net"}}
+
+	_, err := SelectNameservers(available, NameserverPolicy{Types: []string{"premium"}, MinCount: 1})
+	assert.ErrorIs(t, err, ErrIllegalArgument)
+}
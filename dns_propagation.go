@@ -0,0 +1,294 @@
+package cloudns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UpdatePollOptions specifies the intervals used by ZoneService.WaitForUpdate. Polling starts with the initial interval,
+// which gets multiplied after every unsuccessful attempt up to the maximum interval.
+type UpdatePollOptions struct {
+	// InitialInterval is the delay before the second attempt, defaults to one second
+	InitialInterval time.Duration
+	// MaxInterval is the upper bound for the delay between attempts, defaults to 30 seconds
+	MaxInterval time.Duration
+	// Multiplier is applied to the interval after every attempt, defaults to 2
+	Multiplier float64
+	// MaxTriggerAttempts is the maximum amount of throttled update triggers retried by ZoneService.ForceSync, defaults
+	// to 5
+	MaxTriggerAttempts int
+}
+
+// ZonePropagation represents the propagation of a zone update to all ClouDNS nameservers, as observed by polling
+type ZonePropagation struct {
+	Zone      string
+	StartedAt time.Time
+	// CompletedAt is the time at which all nameservers have been seen as updated, or zero if propagation did not finish
+	CompletedAt time.Time
+	Nameservers []NameserverPropagation
+}
+
+// NameserverPropagation represents the propagation of a zone update to a single nameserver
+type NameserverPropagation struct {
+	Server string
+	// FirstSeenAt is the time of the first attempt at which the nameserver was seen as updated, or zero if never seen
+	FirstSeenAt time.Time
+	// Error is set by ZoneService.WaitForSerial if the nameserver can not be queried at all because it has neither an
+	// IPv4 nor an IPv6 address, in which case it is skipped
+	Error error
+}
+
+// WaitForUpdate polls the update status of the zone until all nameservers have been updated or the context is done,
+// using adaptive intervals which start fast and back off over time. The result contains the time at which each
+// nameserver was first seen as updated, which allows tracking propagation latency. If the context is done before
+// propagation has finished, the partial result is returned together with the error of the context.
+func (svc *ZoneService) WaitForUpdate(ctx context.Context, zoneName string, options UpdatePollOptions) (result ZonePropagation, err error) {
+	options = options.withDefaults()
+	clock := svc.api.clock
+
+	result.Zone = zoneName
+	result.StartedAt = clock.Now()
+	seen := make(map[string]int)
+	interval := options.InitialInterval
+
+	for {
+		statuses, err := svc.GetUpdateStatus(ctx, zoneName)
+		if err != nil {
+			return result, err
+		}
+
+		now := clock.Now()
+		complete := len(statuses) > 0
+		for _, status := range statuses {
+			index, ok := seen[status.Server]
+			if !ok {
+				index = len(result.Nameservers)
+				seen[status.Server] = index
+				result.Nameservers = append(result.Nameservers, NameserverPropagation{Server: status.Server})
+			}
+
+			if !status.IsUpdated {
+				complete = false
+			} else if result.Nameservers[index].FirstSeenAt.IsZero() {
+				result.Nameservers[index].FirstSeenAt = now
+			}
+		}
+
+		if complete {
+			result.CompletedAt = now
+			return result, nil
+		}
+
+		if err := clock.Sleep(ctx, interval); err != nil {
+			return result, err
+		}
+		interval = options.nextInterval(interval)
+	}
+}
+
+// UpdateThrottledError is returned by ZoneService.TriggerUpdate if ClouDNS rejected the trigger because the previous
+// one was too recent. It matches ErrUpdateThrottled with errors.Is and wraps the original API error.
+type UpdateThrottledError struct {
+	Zone string
+	// NextTrigger is the earliest time at which the update may be triggered again, or zero if not mentioned by the API
+	NextTrigger time.Time
+
+	inner error
+}
+
+var (
+	updateThrottledPattern = regexp.MustCompile(`(?i)too soon|too often|too frequent|once (?:every|per)|please wait`)
+	updateWaitPattern      = regexp.MustCompile(`(?i)(\d+)\s*(seconds?|secs?|minutes?|mins?|hours?)\b`)
+)
+
+// newUpdateThrottledError returns an UpdateThrottledError if the given error of TriggerUpdate indicates that the
+// update has been triggered too soon, using the given time of the response as reference for NextTrigger. All other
+// errors are returned as-is.
+func newUpdateThrottledError(zoneName string, now time.Time, err error) error {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !updateThrottledPattern.MatchString(apiErr.Description) {
+		return err
+	}
+
+	throttledErr := &UpdateThrottledError{Zone: zoneName, inner: err}
+	if match := updateWaitPattern.FindStringSubmatch(apiErr.Description); match != nil {
+		amount, _ := strconv.Atoi(match[1])
+		unit := time.Second
+		switch name := strings.ToLower(match[2]); {
+		case strings.HasPrefix(name, "min"):
+			unit = time.Minute
+		case strings.HasPrefix(name, "hour"):
+			unit = time.Hour
+		}
+		throttledErr.NextTrigger = now.Add(time.Duration(amount) * unit)
+	}
+
+	return throttledErr
+}
+
+func (err *UpdateThrottledError) Error() string {
+	if err.NextTrigger.IsZero() {
+		return fmt.Sprintf("%s: %s: %v", ErrUpdateThrottled, err.Zone, err.inner)
+	}
+	return fmt.Sprintf("%s: %s: next trigger at %s: %v", ErrUpdateThrottled, err.Zone,
+		err.NextTrigger.Format(time.RFC3339), err.inner)
+}
+
+// Is returns true if the target is ErrUpdateThrottled
+func (err *UpdateThrottledError) Is(target error) bool {
+	return target == ErrUpdateThrottled
+}
+
+func (err *UpdateThrottledError) Unwrap() error {
+	return err.inner
+}
+
+// ForceSync triggers an update of the zone and waits for it to propagate to all nameservers, see WaitForUpdate. If
+// ClouDNS rejects the trigger because the previous one was too recent, ForceSync waits until the earliest time for the
+// next trigger instead of retrying immediately. If that time is unknown, the intervals of the given options are used.
+// The wait is never shorter than the initial interval, and after MaxTriggerAttempts throttled triggers the last
+// UpdateThrottledError is returned.
+func (svc *ZoneService) ForceSync(ctx context.Context, zoneName string, options UpdatePollOptions) (ZonePropagation, error) {
+	options = options.withDefaults()
+	clock := svc.api.clock
+	interval := options.InitialInterval
+
+	for attempt := 1; ; attempt++ {
+		_, err := svc.TriggerUpdate(ctx, zoneName)
+		var throttledErr *UpdateThrottledError
+		if !errors.As(err, &throttledErr) {
+			if err != nil {
+				return ZonePropagation{Zone: zoneName}, err
+			}
+			break
+		}
+		if attempt >= options.MaxTriggerAttempts {
+			return ZonePropagation{Zone: zoneName}, err
+		}
+
+		wait := interval
+		if !throttledErr.NextTrigger.IsZero() {
+			wait = throttledErr.NextTrigger.Sub(clock.Now())
+			if wait < options.InitialInterval {
+				wait = options.InitialInterval
+			}
+		} else {
+			interval = options.nextInterval(interval)
+		}
+		if err := clock.Sleep(ctx, wait); err != nil {
+			return ZonePropagation{Zone: zoneName}, err
+		}
+	}
+
+	return svc.WaitForUpdate(ctx, zoneName, options)
+}
+
+// GetSerial returns the current SOA serial of the zone as known by the ClouDNS API, which might not have propagated to
+// all nameservers yet, see WaitForSerial
+func (svc *ZoneService) GetSerial(ctx context.Context, zoneName string) (uint32, error) {
+	soa, err := svc.api.Records.GetSOA(ctx, zoneName)
+	return uint32(soa.Serial), err
+}
+
+// WaitForSerial polls all ClouDNS nameservers of the zone directly via DNS until each of them serves at least the given
+// SOA serial, e.g. the serial returned by GetSerial after a change, or until the context is done. Serials are compared
+// using serial number arithmetic according to RFC1982. Failed queries are treated like outdated serials and retried with
+// the next attempt. Nameservers without any address are skipped and reported with an error instead of being waited for.
+// The result contains the time at which each nameserver was first seen with the serial. If the context
+// is done before all nameservers serve the serial, the partial result is returned together with the error of the
+// context.
+func (svc *ZoneService) WaitForSerial(ctx context.Context, zoneName string, serial uint32, options UpdatePollOptions) (result ZonePropagation, err error) {
+	options = options.withDefaults()
+	clock := svc.api.clock
+
+	result.Zone = zoneName
+	result.StartedAt = clock.Now()
+
+	nameservers, err := svc.GetUpdateStatus(ctx, zoneName)
+	if err != nil {
+		return result, err
+	}
+	for _, nameserver := range nameservers {
+		result.Nameservers = append(result.Nameservers, NameserverPropagation{Server: nameserver.Server})
+	}
+
+	interval := options.InitialInterval
+	for {
+		now := clock.Now()
+		complete := true
+		for index, nameserver := range nameservers {
+			if !result.Nameservers[index].FirstSeenAt.IsZero() {
+				continue
+			}
+
+			address := nameserver.IPv4
+			if address == "" {
+				address = nameserver.IPv6
+			}
+			if address == "" {
+				if result.Nameservers[index].Error == nil {
+					result.Nameservers[index].Error = ErrDNSQuery.wrap(fmt.Errorf("nameserver %s has no address", nameserver.Server))
+				}
+				continue
+			}
+
+			current, err := svc.api.querySOASerial(ctx, address, zoneName)
+			if err != nil || serialIsBehind(current, serial) {
+				complete = false
+				continue
+			}
+			result.Nameservers[index].FirstSeenAt = now
+		}
+
+		if complete {
+			result.CompletedAt = now
+			return result, nil
+		}
+
+		if err := clock.Sleep(ctx, interval); err != nil {
+			return result, err
+		}
+		interval = options.nextInterval(interval)
+	}
+}
+
+// Latency returns the time between the start of polling and the nameserver being first seen as updated, or zero if
+// the nameserver was never seen as updated
+func (propagation NameserverPropagation) Latency(startedAt time.Time) time.Duration {
+	if propagation.FirstSeenAt.IsZero() {
+		return 0
+	}
+
+	return propagation.FirstSeenAt.Sub(startedAt)
+}
+
+func (options UpdatePollOptions) withDefaults() UpdatePollOptions {
+	if options.InitialInterval <= 0 {
+		options.InitialInterval = time.Second
+	}
+	if options.MaxInterval <= 0 {
+		options.MaxInterval = 30 * time.Second
+	}
+	if options.Multiplier < 1 {
+		options.Multiplier = 2
+	}
+	if options.MaxTriggerAttempts <= 0 {
+		options.MaxTriggerAttempts = 5
+	}
+
+	return options
+}
+
+func (options UpdatePollOptions) nextInterval(interval time.Duration) time.Duration {
+	next := time.Duration(float64(interval) * options.Multiplier)
+	if next > options.MaxInterval {
+		return options.MaxInterval
+	}
+
+	return next
+}
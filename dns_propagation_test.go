@@ -0,0 +1,234 @@
+package cloudns
+
+import (
+	"context"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestZoneService_WaitForUpdate(t *testing.T) {
+	// given
+	start := time.Date(2022, 12, 24, 0, 0, 0, 0, time.UTC)
+	clock := NewManualClock(start)
+	responses := []string{
+		`[{"server":"ns1","updated":false},{"server":"ns2","updated":false}]`,
+		`[{"server":"ns1","updated":true},{"server":"ns2","updated":false}]`,
+		`[{"server":"ns1","updated":true},{"server":"ns2","updated":false}]`,
+		`[{"server":"ns1","updated":true},{"server":"ns2","updated":true}]`,
+	}
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		response := responses[0]
+		responses = responses[1:]
+		return response
+	}, CustomClock(clock))
+
+	// when
+	result, err := stubClient.Zones.WaitForUpdate(context.Background(), testDomain, UpdatePollOptions{
+		InitialInterval: time.Second,
+		MaxInterval:     3 * time.Second,
+	})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, start, result.StartedAt)
+	assert.Equal(t, start.Add(6*time.Second), result.CompletedAt, "intervals should back off as 1s, 2s, 3s")
+	assert.Equal(t, []NameserverPropagation{
+		{Server: "ns1", FirstSeenAt: start.Add(time.Second)},
+		{Server: "ns2", FirstSeenAt: start.Add(6 * time.Second)},
+	}, result.Nameservers)
+	assert.Equal(t, time.Second, result.Nameservers[0].Latency(result.StartedAt))
+}
+
+func TestZoneService_WaitForUpdate_Cancelled(t *testing.T) {
+	// given
+	cancelCtx, cancel := context.WithCancel(context.Background())
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		cancel()
+		return `[{"server":"ns1","updated":false}]`
+	}, CustomClock(NewManualClock(time.Now())))
+
+	// when
+	result, err := stubClient.Zones.WaitForUpdate(cancelCtx, testDomain, UpdatePollOptions{})
+
+	// then
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.True(t, result.CompletedAt.IsZero())
+	assert.Equal(t, time.Duration(0), result.Nameservers[0].Latency(result.StartedAt))
+}
+
+func TestZoneService_TriggerUpdate_Throttled(t *testing.T) {
+	// given
+	start := time.Date(2022, 12, 24, 0, 0, 0, 0, time.UTC)
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `{"status":"Failed","statusDescription":"Too soon, please wait 5 minutes before updating the zone again."}`
+	}, CustomClock(NewManualClock(start)))
+
+	// when
+	_, err := stubClient.Zones.TriggerUpdate(context.Background(), testDomain)
+
+	// then
+	var throttledErr *UpdateThrottledError
+	assert.ErrorIs(t, err, ErrUpdateThrottled)
+	assert.ErrorIs(t, err, ErrAPIInvocation)
+	assert.ErrorAs(t, err, &throttledErr)
+	assert.Equal(t, testDomain, throttledErr.Zone)
+	assert.Equal(t, start.Add(5*time.Minute), throttledErr.NextTrigger)
+}
+
+func TestZoneService_TriggerUpdate_OtherFailure(t *testing.T) {
+	// given
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `{"status":"Failed","statusDescription":"Domain name is not in your account."}`
+	})
+
+	// when
+	_, err := stubClient.Zones.TriggerUpdate(context.Background(), testDomain)
+
+	// then
+	assert.ErrorIs(t, err, ErrZoneNotFound)
+	assert.NotErrorIs(t, err, ErrUpdateThrottled)
+}
+
+func TestZoneService_ForceSync(t *testing.T) {
+	// given
+	start := time.Date(2022, 12, 24, 0, 0, 0, 0, time.UTC)
+	clock := NewManualClock(start)
+	var triggers []time.Time
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		if req.URL.Path == zoneTriggerUpdateURL {
+			triggers = append(triggers, clock.Now())
+			if len(triggers) == 1 {
+				return `{"status":"Failed","statusDescription":"Too soon, please wait 90 seconds."}`
+			}
+			return `{"status":"Success","statusDescription":"Zone updated."}`
+		}
+		return `[{"server":"ns1","updated":true}]`
+	}, CustomClock(clock))
+
+	// when
+	result, err := stubClient.Zones.ForceSync(context.Background(), testDomain, UpdatePollOptions{})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Time{start, start.Add(90 * time.Second)}, triggers)
+	assert.Equal(t, start.Add(90*time.Second), result.CompletedAt)
+}
+
+func TestZoneService_ForceSync_PastNextTrigger(t *testing.T) {
+	// given
+	start := time.Date(2022, 12, 24, 0, 0, 0, 0, time.UTC)
+	clock := NewManualClock(start)
+	var triggers []time.Time
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		if req.URL.Path == zoneTriggerUpdateURL {
+			triggers = append(triggers, clock.Now())
+			if len(triggers) == 1 {
+				return `{"status":"Failed","statusDescription":"Too soon, please wait 0 seconds."}`
+			}
+			return `{"status":"Success","statusDescription":"Zone updated."}`
+		}
+		return `[{"server":"ns1","updated":true}]`
+	}, CustomClock(clock))
+
+	// when
+	_, err := stubClient.Zones.ForceSync(context.Background(), testDomain, UpdatePollOptions{})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Time{start, start.Add(time.Second)}, triggers, "wait should be clamped to initial interval")
+}
+
+func TestZoneService_ForceSync_MaxTriggerAttempts(t *testing.T) {
+	// given
+	requests := 0
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		requests++
+		return `{"status":"Failed","statusDescription":"Too soon, please wait 90 seconds."}`
+	}, CustomClock(NewManualClock(time.Now())))
+
+	// when
+	_, err := stubClient.Zones.ForceSync(context.Background(), testDomain, UpdatePollOptions{MaxTriggerAttempts: 3})
+
+	// then
+	assert.ErrorIs(t, err, ErrUpdateThrottled)
+	assert.Equal(t, 3, requests)
+}
+
+func TestZoneService_ForceSync_Failure(t *testing.T) {
+	// given
+	requests := 0
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		requests++
+		return `{"status":"Failed","statusDescription":"Domain name is not in your account."}`
+	}, CustomClock(NewManualClock(time.Now())))
+
+	// when
+	_, err := stubClient.Zones.ForceSync(context.Background(), testDomain, UpdatePollOptions{})
+
+	// then
+	assert.ErrorIs(t, err, ErrZoneNotFound)
+	assert.Equal(t, 1, requests)
+}
+
+func TestZoneService_WaitForSerial(t *testing.T) {
+	// given
+	var queries int32
+	port := startTestUDPDNSServers(t, func(w dns.ResponseWriter, req *dns.Msg) {
+		serials := map[string]uint32{"127.0.0.1": 2022122402, "127.0.0.2": 2022122401}
+		if atomic.AddInt32(&queries, 1) > 3 {
+			serials["127.0.0.2"] = 2022122402
+		}
+		newTestSOAHandler(serials)(w, req)
+	}, "127.0.0.1", "127.0.0.2")
+
+	start := time.Date(2022, 12, 24, 0, 0, 0, 0, time.UTC)
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		assert.Equal(t, zoneUpdateStatusURL, req.URL.Path)
+		return `[{"server":"ns1","ip4":"127.0.0.1","updated":true},{"server":"ns2","ip4":"127.0.0.2","updated":true}]`
+	}, CustomClock(NewManualClock(start)), DNSPort(port))
+
+	// when
+	result, err := stubClient.Zones.WaitForSerial(context.Background(), testDomain, 2022122402, UpdatePollOptions{})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []NameserverPropagation{
+		{Server: "ns1", FirstSeenAt: start},
+		{Server: "ns2", FirstSeenAt: start.Add(3 * time.Second)},
+	}, result.Nameservers, "updated nameservers should not be queried again")
+	assert.Equal(t, start.Add(3*time.Second), result.CompletedAt)
+}
+
+func TestZoneService_WaitForSerial_NoAddress(t *testing.T) {
+	// given
+	port := startTestUDPDNSServers(t, newTestSOAHandler(map[string]uint32{"127.0.0.1": 2022122402}), "127.0.0.1")
+	start := time.Date(2022, 12, 24, 0, 0, 0, 0, time.UTC)
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `[{"server":"ns1","ip4":"127.0.0.1","updated":true},{"server":"ns2","ip4":"","ip6":"","updated":true}]`
+	}, CustomClock(NewManualClock(start)), DNSPort(port))
+
+	// when
+	result, err := stubClient.Zones.WaitForSerial(context.Background(), testDomain, 2022122402, UpdatePollOptions{})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, start, result.Nameservers[0].FirstSeenAt)
+	assert.True(t, result.Nameservers[1].FirstSeenAt.IsZero(), "nameserver without address should not be seen")
+	assert.ErrorIs(t, result.Nameservers[1].Error, ErrDNSQuery)
+	assert.Equal(t, start, result.CompletedAt, "nameserver without address should not block completion")
+}
+
+func TestZoneService_GetSerial(t *testing.T) {
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `{"serialNumber":"2022122401","primaryNS":"ns1.cloudns.net","adminMail":"admin@api-example.com",
+			"refresh":"7200","retry":"1800","expire":"1209600","defaultTTL":"3600"}`
+	})
+
+	serial, err := stubClient.Zones.GetSerial(context.Background(), testDomain)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(2022122401), serial)
+}
@@ -0,0 +1,60 @@
+package cloudns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/miekg/dns"
+)
+
+// querySOASerial queries the given nameserver for the SOA record of the zone and returns its serial. The nameserver
+// must answer authoritatively, as a cached or recursive answer does not reflect the state of the nameserver itself.
+func (c *Client) querySOASerial(ctx context.Context, server, zoneName string) (uint32, error) {
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(zoneName), dns.TypeSOA)
+	msg.RecursionDesired = false
+
+	resp, err := c.exchangeDNS(ctx, msg, server)
+	if err != nil {
+		return 0, err
+	}
+	if !resp.Authoritative {
+		return 0, ErrDNSQuery.wrap(fmt.Errorf("nameserver %s is not authoritative for %s", server, zoneName))
+	}
+
+	for _, rr := range resp.Answer {
+		if soa, ok := rr.(*dns.SOA); ok {
+			return soa.Serial, nil
+		}
+	}
+
+	return 0, ErrDNSQuery.wrap(fmt.Errorf("nameserver %s returned no soa record for %s", server, zoneName))
+}
+
+// exchangeDNS sends a DNS query to the given nameserver, which may be specified as hostname or IP address without port,
+// using the DNS port of the client
+func (c *Client) exchangeDNS(ctx context.Context, msg *dns.Msg, server string) (*dns.Msg, error) {
+	if server == "" {
+		return nil, ErrDNSQuery.wrap(errors.New("nameserver has no address"))
+	}
+
+	client := new(dns.Client)
+	resp, _, err := client.ExchangeContext(ctx, msg, net.JoinHostPort(server, strconv.Itoa(c.dnsPort)))
+	if err != nil {
+		return nil, ErrDNSQuery.wrap(err)
+	}
+	if resp.Rcode != dns.RcodeSuccess {
+		return nil, ErrDNSQuery.wrap(fmt.Errorf("nameserver %s answered with %s", server, dns.RcodeToString[resp.Rcode]))
+	}
+
+	return resp, nil
+}
+
+// serialIsBehind compares two SOA serials using serial number arithmetic according to RFC1982 and returns true if the
+// first serial is older than the second one
+func serialIsBehind(serial, reference uint32) bool {
+	return serial != reference && int32(reference-serial) > 0
+}
@@ -0,0 +1,67 @@
+package cloudns
+
+import (
+	"context"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"strconv"
+	"testing"
+)
+
+func TestSerialIsBehind(t *testing.T) {
+	assert.True(t, serialIsBehind(1, 2))
+	assert.False(t, serialIsBehind(2, 2))
+	assert.False(t, serialIsBehind(3, 2))
+	assert.True(t, serialIsBehind(4294967295, 1), "serial should wrap around according to RFC1982")
+	assert.False(t, serialIsBehind(1, 4294967295), "serial should wrap around according to RFC1982")
+}
+
+// startTestUDPDNSServers starts local DNS servers on all given loopback addresses sharing the same random UDP port and
+// returns this port, which can be passed to DNSPort
+func startTestUDPDNSServers(t *testing.T, handler dns.HandlerFunc, addresses ...string) int {
+	port := "0"
+	for _, address := range addresses {
+		conn, err := net.ListenPacket("udp", net.JoinHostPort(address, port))
+		if err != nil {
+			t.Skipf("could not listen on %s for test dns server: %v", address, err)
+		}
+		_, port, _ = net.SplitHostPort(conn.LocalAddr().String())
+
+		server := &dns.Server{PacketConn: conn, Handler: handler}
+		go func() {
+			_ = server.ActivateAndServe()
+		}()
+		t.Cleanup(func() {
+			_ = server.Shutdown()
+		})
+	}
+
+	result, _ := strconv.Atoi(port)
+	return result
+}
+
+// newTestSOAHandler returns a DNS handler answering SOA queries authoritatively with a serial depending on the local
+// address of the server receiving the query
+func newTestSOAHandler(serials map[string]uint32) dns.HandlerFunc {
+	return func(w dns.ResponseWriter, req *dns.Msg) {
+		host, _, _ := net.SplitHostPort(w.LocalAddr().String())
+		soa, _ := dns.NewRR(req.Question[0].Name + " 3600 IN SOA ns1.local. admin.local. 0 7200 1800 1209600 3600")
+		soa.(*dns.SOA).Serial = serials[host]
+
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+		resp.Authoritative = true
+		resp.Answer = []dns.RR{soa}
+		_ = w.WriteMsg(resp)
+	}
+}
+
+func TestQuerySOASerial(t *testing.T) {
+	port := startTestUDPDNSServers(t, newTestSOAHandler(map[string]uint32{"127.0.0.1": 2022122401}), "127.0.0.1")
+	client, _ := New(DNSPort(port))
+
+	serial, err := client.querySOASerial(context.Background(), "127.0.0.1", testDomain)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(2022122401), serial)
+}
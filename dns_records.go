@@ -2,8 +2,12 @@ package cloudns
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
+	"fmt"
+	"iter"
+	"net"
+	"sort"
+	"strings"
 )
 
 const recordSOAGetURL = "/dns/soa-details.json"
@@ -23,13 +27,16 @@ const recordUpdateURL = "/dns/mod-record.json"
 const recordDeleteURL = "/dns/delete-record.json"
 const recordSetActiveURL = "/dns/change-record-status.json"
 
-// RecordFormat is an enumeration of all supported record formats
+// RecordFormat is an enumeration of all supported record formats. Records can be imported in BIND and TinyDNS format,
+// while all formats are supported for exporting with RecordMap.Export.
 type RecordFormat int
 
 // Enumeration values for RecordFormat
 const (
 	RecordFormatBIND RecordFormat = iota
 	RecordFormatTinyDNS
+	RecordFormatJSON
+	RecordFormatCSV
 )
 
 // RecordType is an enumeration of all known record types. It is based on a string, as this allows usage of new or
@@ -43,12 +50,19 @@ const (
 	RecordTypeAAAA        RecordType = "AAAA"
 	RecordTypeALIAS       RecordType = "ALIAS"
 	RecordTypeCAA         RecordType = "CAA"
+	RecordTypeCERT        RecordType = "CERT"
 	RecordTypeCNAME       RecordType = "CNAME"
+	RecordTypeDNAME       RecordType = "DNAME"
+	RecordTypeDS          RecordType = "DS"
+	RecordTypeHINFO       RecordType = "HINFO"
+	RecordTypeLOC         RecordType = "LOC"
 	RecordTypeMX          RecordType = "MX"
 	RecordTypeNAPTR       RecordType = "NAPTR"
 	RecordTypeNS          RecordType = "NS"
+	RecordTypeOPENPGPKEY  RecordType = "OPENPGPKEY"
 	RecordTypePTR         RecordType = "PTR"
 	RecordTypeRP          RecordType = "RP"
+	RecordTypeSMIMEA      RecordType = "SMIMEA"
 	RecordTypeSRV         RecordType = "SRV"
 	RecordTypeSSHFP       RecordType = "SSHFP"
 	RecordTypeTLSA        RecordType = "TLSA"
@@ -80,8 +94,13 @@ type Record struct {
 
 	// Type-specific record fields
 	CAA
+	CERT
+	DS
+	HINFO
+	LOC
 	NAPTR
 	RP
+	SMIMEA
 	SRV
 	SSHFP
 	TLSA
@@ -120,12 +139,61 @@ type TLSA struct {
 	MatchingType uint8 `json:"tlsa_matching_type,string,omitempty"`
 }
 
-// WebRedirect represents parameters specifically for web redirect records
-type WebRedirect struct {
-	MobileMeta   APIBool `json:"mobile_meta"`
-	SavePath     APIBool `json:"save_path,omitempty"`
-	RedirectType int     `json:"redirect_type,string,omitempty"`
+// SMIMEA represents parameters specifically for SMIMEA records
+type SMIMEA struct {
+	Usage        uint8 `json:"smimea_usage,string,omitempty"`
+	Selector     uint8 `json:"smimea_selector,string,omitempty"`
+	MatchingType uint8 `json:"smimea_matching_type,string,omitempty"`
+}
+
+// DS represents parameters specifically for DS records, whose record value contains the digest
+type DS struct {
+	KeyTag     uint16 `json:"key_tag,string,omitempty"`
+	Algorithm  uint8  `json:"ds_algorithm,string,omitempty"`
+	DigestType uint8  `json:"digest_type,string,omitempty"`
+}
 
+// CERT represents parameters specifically for CERT records, whose record value contains the certificate
+type CERT struct {
+	Type      uint16 `json:"cert_type,string,omitempty"`
+	KeyTag    uint16 `json:"cert_key_tag,string,omitempty"`
+	Algorithm uint8  `json:"cert_algorithm,string,omitempty"`
+}
+
+// HINFO represents parameters specifically for HINFO records
+type HINFO struct {
+	CPU string `json:"cpu,omitempty"`
+	OS  string `json:"os,omitempty"`
+}
+
+// LOC represents parameters specifically for LOC records according to RFC1876. Directions are either `N` or `S` for
+// the latitude and `E` or `W` for the longitude, while altitude, size and precisions are specified in meters.
+type LOC struct {
+	LatitudeDegrees     uint8   `json:"lat_deg,string,omitempty"`
+	LatitudeMinutes     uint8   `json:"lat_min,string,omitempty"`
+	LatitudeSeconds     float64 `json:"lat_sec,string,omitempty"`
+	LatitudeDirection   string  `json:"lat_dir,omitempty"`
+	LongitudeDegrees    uint8   `json:"long_deg,string,omitempty"`
+	LongitudeMinutes    uint8   `json:"long_min,string,omitempty"`
+	LongitudeSeconds    float64 `json:"long_sec,string,omitempty"`
+	LongitudeDirection  string  `json:"long_dir,omitempty"`
+	Altitude            float64 `json:"altitude,string,omitempty"`
+	Size                float64 `json:"size,string,omitempty"`
+	HorizontalPrecision float64 `json:"h_precision,string,omitempty"`
+	VerticalPrecision   float64 `json:"v_precision,string,omitempty"`
+}
+
+// WebRedirect represents parameters specifically for web redirect records. Geo-based redirect targets within GeoDNS
+// zones are represented as separate web redirect records with Record.GeoDNSLocationID set, see RecordService.WithGeoDNSLocation.
+type WebRedirect struct {
+	// MobileMeta adds a viewport meta tag for mobile devices to framed redirects
+	MobileMeta APIBool `json:"mobile_meta"`
+	// SavePath appends the requested path to the redirect target
+	SavePath APIBool `json:"save_path,omitempty"`
+	// RedirectType is the HTTP status code of non-framed redirects, either 301 or 302
+	RedirectType int `json:"redirect_type,string,omitempty"`
+
+	// IsFrame serves the redirect target within a frame, using the given title and meta tags for the framing page
 	IsFrame          APIBool `json:"frame,omitempty"`
 	FrameTitle       string  `json:"frame_title,omitempty"`
 	FrameKeywords    string  `json:"frame_keywords,omitempty"`
@@ -173,9 +241,14 @@ func (svc *RecordService) GetSOA(ctx context.Context, zoneName string) (result S
 	return
 }
 
-// UpdateSOA updates the SOA record of the given zone
+// UpdateSOA updates the SOA record of the given zone. The admin mail may be specified in either email or DNS style and
+// is normalized into email style, see NormalizeAdminMail.
 // Official Docs: https://www.cloudns.net/wiki/article/63/
 func (svc *RecordService) UpdateSOA(ctx context.Context, zoneName string, soa SOA) (result StatusResult, err error) {
+	if soa.AdminMail, err = NormalizeAdminMail(soa.AdminMail); err != nil {
+		return
+	}
+
 	params := soa.AsParams()
 	params["domain-name"] = zoneName
 
@@ -201,21 +274,41 @@ func (svc *RecordService) Search(ctx context.Context, zoneName, host string, rec
 		params["type"] = recordType
 	}
 
-	// Fetch all DNS records with a twist: Unmarshalling to the record map fails if the zone contains no records, as
-	// ClouDNS decided to return an empty array instead of a JSON object when no records have been found. In this
-	// specific case, we silence the error and return an empty map instead.
-	err = svc.api.request(ctx, "POST", recordListURL, params, nil, &result)
-	var typeError *json.UnmarshalTypeError
-	if errors.As(err, &typeError) && typeError.Value == "array" {
-		return make(RecordMap), nil
+	if err = svc.api.request(ctx, "POST", recordListURL, params, nil, &result); err != nil {
+		return
 	}
 
+	svc.api.normalizeRecords(result)
 	return
 }
 
+// Get returns the record with the given ID within the given zone, or fails with ErrRecordNotFound if it does not exist.
+// The API does not support fetching single records, so the records of the zone are listed and filtered client-side.
+func (svc *RecordService) Get(ctx context.Context, zoneName string, recordID int) (Record, error) {
+	records, err := svc.List(ctx, zoneName)
+	if err != nil {
+		return Record{}, err
+	}
+
+	record, ok := records[recordID]
+	if !ok {
+		return Record{}, ErrRecordNotFound.wrap(fmt.Errorf("record %d does not exist in zone %s", recordID, zoneName))
+	}
+
+	return record, nil
+}
+
 // Create a new record within the given zone
 // Official Docs: https://www.cloudns.net/wiki/article/58/
 func (svc *RecordService) Create(ctx context.Context, zoneName string, record Record) (result StatusResult, err error) {
+	record = svc.api.normalizeRecord(record)
+	if record, err = svc.api.applyTTLPolicy(record); err != nil {
+		return
+	}
+	if err = record.Validate(); err != nil {
+		return
+	}
+
 	params := record.AsParams()
 	params["domain-name"] = zoneName
 
@@ -226,6 +319,14 @@ func (svc *RecordService) Create(ctx context.Context, zoneName string, record Re
 // Update modifies a specific record with a given record ID inside the given zone
 // Official Docs: https://www.cloudns.net/wiki/article/60/
 func (svc *RecordService) Update(ctx context.Context, zoneName string, recordID int, record Record) (result StatusResult, err error) {
+	record = svc.api.normalizeRecord(record)
+	if record, err = svc.api.applyTTLPolicy(record); err != nil {
+		return
+	}
+	if err = record.Validate(); err != nil {
+		return
+	}
+
 	params := record.AsParams()
 	params["domain-name"] = zoneName
 	params["record-id"] = recordID
@@ -242,6 +343,32 @@ func (svc *RecordService) Delete(ctx context.Context, zoneName string, recordID
 	return
 }
 
+// DeleteMatching deletes all records within the given zone which exactly match the given host and record type, e.g. for
+// removing stale ACME challenges. An empty record type matches all types. The IDs of all deleted records are returned in
+// ascending order, including the ones deleted before a failure.
+func (svc *RecordService) DeleteMatching(ctx context.Context, zoneName, host string, recordType RecordType) (deleted []int, err error) {
+	records, err := svc.Search(ctx, zoneName, host, recordType)
+	if err != nil {
+		return
+	}
+
+	for _, record := range records.SortedSlice() {
+		if normalizeRecordHost(record.Host) != normalizeRecordHost(host) {
+			continue
+		}
+		if recordType != RecordTypeUnknown && record.RecordType != recordType {
+			continue
+		}
+
+		if _, err = svc.Delete(ctx, zoneName, record.ID); err != nil {
+			return
+		}
+		deleted = append(deleted, record.ID)
+	}
+
+	return
+}
+
 // SetActive enables or disables a given record ID within the specified zone
 // Official Docs: https://www.cloudns.net/wiki/article/66/
 func (svc *RecordService) SetActive(ctx context.Context, zoneName string, recordID int, isActive bool) (result StatusResult, err error) {
@@ -272,26 +399,8 @@ func (svc *RecordService) CopyFromZone(ctx context.Context, targetZoneName, sour
 
 // Import records with a specific format into the zone, optionally overwriting the existing records
 // Official Docs: https://www.cloudns.net/wiki/article/156/
-func (svc *RecordService) Import(ctx context.Context, zoneName string, format RecordFormat, content string, overwrite bool) (result StatusResult, err error) {
-	params := HTTPParams{"domain-name": zoneName, "content": content}
-
-	switch format {
-	case RecordFormatBIND:
-		params["format"] = "bind"
-	case RecordFormatTinyDNS:
-		params["format"] = "tinydns"
-	default:
-		return result, ErrIllegalArgument.wrap(errors.New("invalid record format"))
-	}
-
-	if overwrite {
-		params["delete-existing-records"] = 1
-	} else {
-		params["delete-existing-records"] = 0
-	}
-
-	err = svc.api.request(ctx, "POST", recordImportURL, params, nil, &result)
-	return
+func (svc *RecordService) Import(ctx context.Context, zoneName string, format RecordFormat, content string, overwrite bool) (result ImportResult, err error) {
+	return svc.ImportWithOptions(ctx, zoneName, format, content, ImportOptions{Overwrite: overwrite})
 }
 
 // ImportTransfer imports records from an authoritative nameserver into the zone using AXFR, overwriting all records
@@ -501,6 +610,64 @@ func NewRecordTLSA(host string, usage, selector, matchingType uint8, value strin
 	return result
 }
 
+// NewRecordDNAME instantiates a new DNAME record. This can also be achieved by manually calling NewRecord.
+func NewRecordDNAME(host, target string, ttl int) Record {
+	return NewRecord(RecordTypeDNAME, host, target, ttl)
+}
+
+// NewRecordOPENPGPKEY instantiates a new OPENPGPKEY record with the given base64-encoded public key. This can also be
+// achieved by manually calling NewRecord.
+func NewRecordOPENPGPKEY(host, publicKey string, ttl int) Record {
+	return NewRecord(RecordTypeOPENPGPKEY, host, publicKey, ttl)
+}
+
+// NewRecordHINFO instantiates a new HINFO record. This can also be achieved by manually calling NewRecord and setting
+// the required additional parameters.
+func NewRecordHINFO(host, cpu, os string, ttl int) Record {
+	result := NewRecord(RecordTypeHINFO, host, "", ttl)
+	result.HINFO.CPU = cpu
+	result.HINFO.OS = os
+	return result
+}
+
+// NewRecordDS instantiates a new DS record. This can also be achieved by manually calling NewRecord and setting the
+// required additional parameters.
+func NewRecordDS(host string, keyTag uint16, algorithm, digestType uint8, digest string, ttl int) Record {
+	result := NewRecord(RecordTypeDS, host, digest, ttl)
+	result.DS.KeyTag = keyTag
+	result.DS.Algorithm = algorithm
+	result.DS.DigestType = digestType
+	return result
+}
+
+// NewRecordCERT instantiates a new CERT record with the given base64-encoded certificate. This can also be achieved by
+// manually calling NewRecord and setting the required additional parameters.
+func NewRecordCERT(host string, certType, keyTag uint16, algorithm uint8, certificate string, ttl int) Record {
+	result := NewRecord(RecordTypeCERT, host, certificate, ttl)
+	result.CERT.Type = certType
+	result.CERT.KeyTag = keyTag
+	result.CERT.Algorithm = algorithm
+	return result
+}
+
+// NewRecordSMIMEA instantiates a new SMIMEA record. This can also be achieved by manually calling NewRecord and setting
+// the required additional parameters.
+func NewRecordSMIMEA(host string, usage, selector, matchingType uint8, value string, ttl int) Record {
+	result := NewRecord(RecordTypeSMIMEA, host, value, ttl)
+	result.SMIMEA.Usage = usage
+	result.SMIMEA.Selector = selector
+	result.SMIMEA.MatchingType = matchingType
+	return result
+}
+
+// NewRecordLOC instantiates a new LOC record. This can also be achieved by manually calling NewRecord and setting the
+// required additional parameters.
+func NewRecordLOC(host string, location LOC, ttl int) Record {
+	result := NewRecord(RecordTypeLOC, host, "", ttl)
+	result.LOC = location
+	return result
+}
+
 // NewRecordWebRedirect instantiates a new web redirect record. This can also be achieved by manually calling NewRecord
 // and setting the required additional parameters.
 func NewRecordWebRedirect(host, target string, options WebRedirect, ttl int) Record {
@@ -517,6 +684,9 @@ func (rec Record) AsParams() HTTPParams {
 		"record-type": rec.RecordType,
 		"ttl":         rec.TTL,
 	}
+	if rec.GeoDNSLocationID != 0 {
+		params["geodns-location"] = rec.GeoDNSLocationID
+	}
 
 	switch rec.RecordType {
 	case RecordTypeMX:
@@ -529,6 +699,7 @@ func (rec Record) AsParams() HTTPParams {
 		isFrame, _ := rec.WebRedirect.IsFrame.MarshalJSON()
 
 		params["save-path"] = rec.WebRedirect.SavePath
+		params["mobile-meta"] = rec.WebRedirect.MobileMeta
 		params["redirect-type"] = rec.WebRedirect.RedirectType
 		params["frame"] = string(isFrame)
 		params["frame-title"] = rec.WebRedirect.FrameTitle
@@ -555,11 +726,145 @@ func (rec Record) AsParams() HTTPParams {
 		params["params"] = rec.NAPTR.Service
 		params["regexp"] = rec.NAPTR.Regexp
 		params["replace"] = rec.NAPTR.Replacement
+	case RecordTypeSMIMEA:
+		params["smimea-usage"] = rec.SMIMEA.Usage
+		params["smimea-selector"] = rec.SMIMEA.Selector
+		params["smimea-matching-type"] = rec.SMIMEA.MatchingType
+	case RecordTypeDS:
+		params["key-tag"] = rec.DS.KeyTag
+		params["algorithm"] = rec.DS.Algorithm
+		params["digest-type"] = rec.DS.DigestType
+	case RecordTypeCERT:
+		params["cert-type"] = rec.CERT.Type
+		params["cert-key-tag"] = rec.CERT.KeyTag
+		params["cert-algorithm"] = rec.CERT.Algorithm
+	case RecordTypeHINFO:
+		params["cpu"] = rec.HINFO.CPU
+		params["os"] = rec.HINFO.OS
+	case RecordTypeLOC:
+		params["lat-deg"] = rec.LOC.LatitudeDegrees
+		params["lat-min"] = rec.LOC.LatitudeMinutes
+		params["lat-sec"] = rec.LOC.LatitudeSeconds
+		params["lat-dir"] = rec.LOC.LatitudeDirection
+		params["long-deg"] = rec.LOC.LongitudeDegrees
+		params["long-min"] = rec.LOC.LongitudeMinutes
+		params["long-sec"] = rec.LOC.LongitudeSeconds
+		params["long-dir"] = rec.LOC.LongitudeDirection
+		params["altitude"] = rec.LOC.Altitude
+		params["size"] = rec.LOC.Size
+		params["h-precision"] = rec.LOC.HorizontalPrecision
+		params["v-precision"] = rec.LOC.VerticalPrecision
 	}
 
 	return params
 }
 
+// Validate performs client-side sanity checks on a record which the ClouDNS API would otherwise accept silently, e.g.
+// SRV records pointing to an IP address instead of a hostname as required by RFC2782.
+func (rec Record) Validate() error {
+	switch rec.RecordType {
+	case RecordTypeSRV:
+		target := strings.TrimSuffix(rec.Record, ".")
+		if net.ParseIP(target) != nil {
+			return ErrIllegalArgument.wrap(fmt.Errorf("srv target must be a hostname, got ip address: %s", rec.Record))
+		}
+	}
+
+	return nil
+}
+
+// SRVService returns the service and protocol labels of a SRV record host, e.g. `_sip` and `_tls` for `_sip._tls` or
+// `_sip._tls.voice`. Hosts which do not start with two underscore-prefixed labels return false as the last value.
+func (rec Record) SRVService() (service, protocol string, ok bool) {
+	labels := strings.Split(strings.TrimSuffix(rec.Host, "."), ".")
+	if len(labels) < 2 || !strings.HasPrefix(labels[0], "_") || !strings.HasPrefix(labels[1], "_") {
+		return "", "", false
+	}
+
+	return strings.ToLower(labels[0]), strings.ToLower(labels[1]), true
+}
+
+// FilterSRV returns all SRV records claiming the given service and protocol (e.g. `_sip` and `_tls`), regardless of
+// the name they are attached to. The leading underscores may be omitted.
+func (rm RecordMap) FilterSRV(service, protocol string) []Record {
+	service = "_" + strings.TrimPrefix(strings.ToLower(service), "_")
+	protocol = "_" + strings.TrimPrefix(strings.ToLower(protocol), "_")
+
+	var results []Record
+	for _, record := range rm.SortedSlice() {
+		if record.RecordType != RecordTypeSRV {
+			continue
+		}
+		if recService, recProtocol, ok := record.SRVService(); ok && recService == service && recProtocol == protocol {
+			results = append(results, record)
+		}
+	}
+
+	return results
+}
+
+// SRVConflicts returns all groups of SRV records which share the same host (and therefore service and protocol) as
+// well as the same target and port, indexed by the lowercased host. Such records are duplicates from the view of a
+// client, even if they differ in priority or weight.
+func (rm RecordMap) SRVConflicts() map[string][]Record {
+	groups := make(map[string][]Record)
+	for _, record := range rm.SortedSlice() {
+		if record.RecordType != RecordTypeSRV {
+			continue
+		}
+
+		key := fmt.Sprintf("%s|%s|%d",
+			strings.ToLower(strings.TrimSuffix(record.Host, ".")),
+			strings.ToLower(strings.TrimSuffix(record.Record, ".")),
+			record.SRV.Port,
+		)
+		groups[key] = append(groups[key], record)
+	}
+
+	results := make(map[string][]Record)
+	for _, group := range groups {
+		if len(group) > 1 {
+			host := strings.ToLower(strings.TrimSuffix(group[0].Host, "."))
+			results[host] = append(results[host], group...)
+		}
+	}
+
+	return results
+}
+
+// SortedSlice converts a RecordMap to a slice of records which is sorted by the record ID
+func (rm RecordMap) SortedSlice() []Record {
+	results := rm.AsSlice()
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].ID < results[j].ID
+	})
+
+	return results
+}
+
+// All returns an iterator over all records in unspecified order, which avoids allocating an intermediate slice like
+// AsSlice. Use SortedSlice if the records are required in a stable order.
+func (rm RecordMap) All() iter.Seq[Record] {
+	return func(yield func(Record) bool) {
+		for _, record := range rm {
+			if !yield(record) {
+				return
+			}
+		}
+	}
+}
+
+// ByType returns an iterator over all records with the given record type in unspecified order
+func (rm RecordMap) ByType(recordType RecordType) iter.Seq[Record] {
+	return func(yield func(Record) bool) {
+		for _, record := range rm {
+			if record.RecordType == recordType && !yield(record) {
+				return
+			}
+		}
+	}
+}
+
 // AsSlice converts a RecordMap to a slice of records for easier handling
 func (rm RecordMap) AsSlice() []Record {
 	results := make([]Record, 0, len(rm))
@@ -0,0 +1,110 @@
+package cloudns
+
+import (
+	"context"
+	"fmt"
+)
+
+// CopyOptions specifies how records are copied between zones by RecordService.CopyFromZoneWithOptions
+type CopyOptions struct {
+	// Overwrite replaces existing records. Without any filters, ClouDNS deletes all existing records of the target zone.
+	// When filtering, only existing records sharing host and type with a copied record are replaced, while such copied
+	// records are skipped if Overwrite is not set.
+	Overwrite bool
+	// RecordTypes restricts copying to the given record types, all types are copied if empty
+	RecordTypes []RecordType
+	// SkipNS skips all NS records, which usually differ between zones. The SOA record is never copied.
+	SkipNS bool
+	// DryRun only returns the changes which would be performed without modifying the target zone. Without filters, the
+	// changes mirror the server-side copy, which means that all existing records of the target zone are deleted if
+	// Overwrite is set.
+	DryRun bool
+}
+
+// CopyResult represents the outcome of copying records between zones
+type CopyResult struct {
+	StatusResult
+
+	// Changes contains all changes of a client-side copy, which were applied or would be applied for a dry run
+	Changes []RecordChange
+	// Skipped contains all source records which were filtered or already present in the target zone
+	Skipped []Record
+}
+
+// CopyFromZoneWithOptions copies records from one zone into another. Without filters, the copy is performed by ClouDNS
+// using CopyFromZone. Otherwise the source records are filtered client-side and applied using single record operations,
+// which makes it safe to use on target zones with local customizations.
+// Official Docs: https://www.cloudns.net/wiki/article/61/
+func (svc *RecordService) CopyFromZoneWithOptions(ctx context.Context, targetZoneName, sourceZoneName string, options CopyOptions) (result CopyResult, err error) {
+	isServerSide := len(options.RecordTypes) == 0 && !options.SkipNS
+	if !options.DryRun && isServerSide {
+		result.StatusResult, err = svc.CopyFromZone(ctx, targetZoneName, sourceZoneName, options.Overwrite)
+		return
+	}
+
+	source, err := svc.List(ctx, sourceZoneName)
+	if err != nil {
+		return
+	}
+	target, err := svc.List(ctx, targetZoneName)
+	if err != nil {
+		return
+	}
+
+	var copied []Record
+	for _, record := range source.SortedSlice() {
+		isFiltered := len(options.RecordTypes) > 0 && !containsRecordType(record.RecordType, options.RecordTypes)
+		if isFiltered || (options.SkipNS && record.RecordType == RecordTypeNS) {
+			result.Skipped = append(result.Skipped, record)
+			continue
+		}
+
+		record.ID = 0
+		copied = append(copied, record)
+	}
+
+	policy := ImportConflictSkip
+	if options.Overwrite {
+		policy = ImportConflictOverwrite
+	}
+
+	var plan importPlan
+	if isServerSide && options.Overwrite {
+		// The server-side copy replaces the whole target zone instead of only conflicting records
+		plan = importPlan{create: copied, delete: target.SortedSlice()}
+		err = plan.checkCNAMEConflicts(target)
+	} else {
+		plan, err = planImport(target, copied, policy)
+	}
+	if err != nil {
+		return
+	}
+
+	result.Skipped = append(result.Skipped, plan.skipped...)
+	result.Changes = CoalesceChanges(plan.changes())
+	if options.DryRun {
+		result.Status = "Success"
+		result.StatusDescription = fmt.Sprintf("dry run: %d changes", len(result.Changes))
+		return
+	}
+
+	applied, err := svc.ApplyChanges(ctx, targetZoneName, result.Changes)
+	result.Changes = applied
+	if err != nil {
+		return
+	}
+
+	result.Status = "Success"
+	result.StatusDescription = fmt.Sprintf("%d changes applied", len(applied))
+	return
+}
+
+func containsRecordType(recordType RecordType, recordTypes []RecordType) bool {
+	for _, candidate := range recordTypes {
+		if candidate == recordType {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,113 @@
+package cloudns
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func newCopyHandler(requests *[]string) func(req *http.Request) string {
+	return func(req *http.Request) string {
+		var params map[string]interface{}
+		_ = json.NewDecoder(req.Body).Decode(&params)
+		*requests = append(*requests, req.URL.Path)
+
+		if req.URL.Path == recordListURL && params["domain-name"] == "source.example" {
+			return `{
+				"1":{"id":"1","host":"","record":"ns1.source.example","type":"NS","ttl":"3600","status":1},
+				"2":{"id":"2","host":"www","record":"192.0.2.1","type":"A","ttl":"3600","status":1},
+				"3":{"id":"3","host":"mail","record":"192.0.2.2","type":"A","ttl":"3600","status":1},
+				"4":{"id":"4","host":"","record":"v=spf1 -all","type":"TXT","ttl":"3600","status":1}
+			}`
+		} else if req.URL.Path == recordListURL {
+			return `{"7":{"id":"7","host":"www","record":"192.0.2.99","type":"A","ttl":"3600","status":1}}`
+		}
+		return `{"status":"Success","statusDescription":"OK"}`
+	}
+}
+
+func TestRecordService_CopyFromZoneWithOptions_DryRun(t *testing.T) {
+	// given
+	var requests []string
+	stubClient := newStubClient(t, newCopyHandler(&requests))
+	options := CopyOptions{RecordTypes: []RecordType{RecordTypeA, RecordTypeNS}, SkipNS: true, Overwrite: true, DryRun: true}
+
+	// when
+	result, err := stubClient.Records.CopyFromZoneWithOptions(context.Background(), testDomain, "source.example", options)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []string{recordListURL, recordListURL}, requests, "dry run should not modify target zone")
+	assert.Equal(t, []RecordChange{
+		{Type: RecordChangeCreate, Record: NewRecordA("mail", "192.0.2.2", testTTL)},
+		{Type: RecordChangeUpdate, ID: 7, Record: NewRecordA("www", "192.0.2.1", testTTL)},
+	}, result.Changes)
+	assert.Len(t, result.Skipped, 2, "ns and txt records should be skipped")
+}
+
+func TestRecordService_CopyFromZoneWithOptions_SkipConflicts(t *testing.T) {
+	// given
+	var requests []string
+	stubClient := newStubClient(t, newCopyHandler(&requests))
+
+	// when
+	result, err := stubClient.Records.CopyFromZoneWithOptions(context.Background(), testDomain, "source.example", CopyOptions{SkipNS: true})
+
+	// then
+	assert.NoError(t, err)
+	assert.Len(t, result.Changes, 2, "conflicting www record should be skipped")
+	assert.Equal(t, []string{recordListURL, recordListURL, recordCreateURL, recordCreateURL}, requests)
+}
+
+func TestRecordService_CopyFromZoneWithOptions_ServerSide(t *testing.T) {
+	var requests []string
+	stubClient := newStubClient(t, newCopyHandler(&requests))
+
+	_, err := stubClient.Records.CopyFromZoneWithOptions(context.Background(), testDomain, "source.example", CopyOptions{Overwrite: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{recordCopyFromZoneURL}, requests, "unfiltered copy should be performed by ClouDNS")
+}
+
+func TestRecordService_CopyFromZoneWithOptions_DryRunServerSide(t *testing.T) {
+	// given
+	var requests []string
+	stubClient := newStubClient(t, newCopyHandler(&requests))
+
+	// when
+	result, err := stubClient.Records.CopyFromZoneWithOptions(context.Background(), testDomain, "source.example",
+		CopyOptions{Overwrite: true, DryRun: true})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []string{recordListURL, recordListURL}, requests, "dry run should not modify target zone")
+
+	changeTypes := make(map[RecordChangeType]int)
+	for _, change := range result.Changes {
+		changeTypes[change.Type]++
+	}
+	assert.Equal(t, map[RecordChangeType]int{RecordChangeUpdate: 1, RecordChangeCreate: 3}, changeTypes,
+		"dry run should replace the whole target zone like the server-side copy")
+}
+
+func TestRecordService_CopyFromZoneWithOptions_DryRunServerSideCNAME(t *testing.T) {
+	// given
+	var requests []string
+	copyHandler := newCopyHandler(&requests)
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		body := copyHandler(req)
+		if len(requests) == 2 {
+			return `{"8":{"id":"8","host":"mail","record":"mail.example.net","type":"CNAME","ttl":"3600","status":1}}`
+		}
+		return body
+	})
+
+	// when
+	result, err := stubClient.Records.CopyFromZoneWithOptions(context.Background(), testDomain, "source.example",
+		CopyOptions{Overwrite: true, DryRun: true})
+
+	// then
+	assert.NoError(t, err, "records replaced by the server-side copy should not conflict")
+	assert.Len(t, result.Changes, 5)
+}
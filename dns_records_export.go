@@ -0,0 +1,186 @@
+package cloudns
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/miekg/dns"
+)
+
+// Export renders all records of the given zone in the requested format for backups and interchange with other
+// systems, complementing the server-side BIND export of RecordService.Export. Records are sorted by their ID.
+//
+// JSON exports contain a versioned ZoneRecordsDocument, while CSV exports contain one row per record including the
+// type-specific parameters as JSON object. BIND and TinyDNS exports contain standard resource records, which means
+// that web redirects and ALIAS records are only included as comments, as are inactive records.
+func (rm RecordMap) Export(zoneName string, format RecordFormat) (string, error) {
+	records := rm.SortedSlice()
+
+	switch format {
+	case RecordFormatJSON:
+		return exportJSON(zoneName, records)
+	case RecordFormatCSV:
+		return exportCSV(records)
+	case RecordFormatBIND:
+		return exportBIND(zoneName, records)
+	case RecordFormatTinyDNS:
+		return exportTinyDNS(zoneName, records)
+	}
+
+	return "", ErrIllegalArgument.wrap(errors.New("invalid record format"))
+}
+
+func exportJSON(zoneName string, records []Record) (string, error) {
+	data := ZoneRecordsDocument{Zone: normalizeHostname(zoneName), Records: make([]RecordDocument, 0, len(records))}
+	for _, record := range records {
+		data.Records = append(data.Records, record.Document())
+	}
+
+	content, err := json.MarshalIndent(Document{Kind: "zone-records", Version: DocumentVersion, Data: data}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(content) + "\n", nil
+}
+
+func exportCSV(records []Record) (string, error) {
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	_ = writer.Write([]string{"id", "host", "type", "value", "ttl", "active", "geodns_location", "params"})
+
+	for _, record := range records {
+		document := record.Document()
+
+		var params string
+		if len(document.Params) != 0 {
+			encoded, err := json.Marshal(document.Params)
+			if err != nil {
+				return "", err
+			}
+			params = string(encoded)
+		}
+
+		_ = writer.Write([]string{
+			strconv.Itoa(document.ID),
+			document.Host,
+			string(document.Type),
+			document.Value,
+			strconv.Itoa(document.TTL),
+			strconv.FormatBool(document.Active),
+			strconv.Itoa(document.GeoDNSLocationID),
+			params,
+		})
+	}
+
+	writer.Flush()
+	return buffer.String(), writer.Error()
+}
+
+func exportBIND(zoneName string, records []Record) (string, error) {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "$ORIGIN %s\n", dns.Fqdn(normalizeHostname(zoneName)))
+
+	for _, record := range records {
+		rr, err := record.ToRR(zoneName)
+		if errors.Is(err, ErrUnsupportedRecord) {
+			fmt.Fprintf(&builder, "; %s\t%d\t%s\t%s\n", recordFQDN(record.Host, zoneName), record.TTL,
+				record.RecordType, record.Record)
+			continue
+		} else if err != nil {
+			return "", err
+		}
+
+		if !record.IsActive {
+			builder.WriteString("; ")
+		}
+		builder.WriteString(rr.String())
+		builder.WriteByte('\n')
+	}
+
+	return builder.String(), nil
+}
+
+func exportTinyDNS(zoneName string, records []Record) (string, error) {
+	var builder strings.Builder
+	for _, record := range records {
+		rr, err := record.ToRR(zoneName)
+		if errors.Is(err, ErrUnsupportedRecord) {
+			fmt.Fprintf(&builder, "# %s:%s:%s:%d\n", trimDot(recordFQDN(record.Host, zoneName)), record.RecordType,
+				record.Record, record.TTL)
+			continue
+		} else if err != nil {
+			return "", err
+		}
+
+		line, err := tinyDNSLine(record, rr)
+		if err != nil {
+			return "", err
+		}
+		if !record.IsActive {
+			builder.WriteString("# ")
+		}
+		builder.WriteString(line)
+		builder.WriteByte('\n')
+	}
+
+	return builder.String(), nil
+}
+
+// tinyDNSLine returns the TinyDNS data line of the given record and its resource record. Types without a dedicated line
+// prefix are emitted as generic records containing their wire format.
+func tinyDNSLine(record Record, rr dns.RR) (string, error) {
+	header := rr.Header()
+	name, ttl := tinyDNSEscape(trimDot(header.Name)), header.Ttl
+
+	switch v := rr.(type) {
+	case *dns.A:
+		return fmt.Sprintf("+%s:%s:%d", name, v.A, ttl), nil
+	case *dns.CNAME:
+		return fmt.Sprintf("C%s:%s:%d", name, tinyDNSEscape(trimDot(v.Target)), ttl), nil
+	case *dns.NS:
+		return fmt.Sprintf("&%s::%s:%d", name, tinyDNSEscape(trimDot(v.Ns)), ttl), nil
+	case *dns.PTR:
+		return fmt.Sprintf("^%s:%s:%d", name, tinyDNSEscape(trimDot(v.Ptr)), ttl), nil
+	case *dns.MX:
+		return fmt.Sprintf("@%s::%s:%d:%d", name, tinyDNSEscape(trimDot(v.Mx)), v.Preference, ttl), nil
+	case *dns.TXT:
+		// The character strings of TXT resource records are kept in their escaped presentation format
+		return fmt.Sprintf("'%s:%s:%d", name, tinyDNSEscape(record.Record), ttl), nil
+	}
+
+	buffer := make([]byte, dns.Len(rr))
+	end, err := dns.PackRR(rr, buffer, 0, nil, false)
+	if err != nil {
+		return "", ErrIllegalArgument.wrap(err)
+	}
+	nameLength, err := dns.PackDomainName(header.Name, make([]byte, 256), 0, nil, false)
+	if err != nil {
+		return "", ErrIllegalArgument.wrap(err)
+	}
+
+	// The fixed part of the header consists of type, class, ttl and length of the resource data
+	rdata := buffer[nameLength+10 : end]
+	return fmt.Sprintf(":%s:%d:%s:%d", name, header.Rrtype, tinyDNSEscape(string(rdata)), ttl), nil
+}
+
+// tinyDNSEscape escapes all characters which are either non-printable or have a special meaning within TinyDNS data
+// lines as octal sequences
+func tinyDNSEscape(value string) string {
+	var builder strings.Builder
+	for i := 0; i < len(value); i++ {
+		char := value[i]
+		if char < 0x20 || char > 0x7e || char == ':' || char == '\\' {
+			fmt.Fprintf(&builder, "\\%03o", char)
+		} else {
+			builder.WriteByte(char)
+		}
+	}
+
+	return builder.String()
+}
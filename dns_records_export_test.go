@@ -0,0 +1,129 @@
+package cloudns
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func newTestExportRecords() RecordMap {
+	records := []Record{
+		NewRecordA("", "192.0.2.1", 3600),
+		NewRecordAAAA("www", "2001:db8::1", 300),
+		NewRecordMX("", 10, "mail.api-example.com", 3600),
+		NewRecordTXT("", `v=spf1 include:"spf.local" -all`, 3600),
+		NewRecordSRV("_sip._tls", 10, 20, 5061, "sip.local", 3600),
+		NewRecordCAA("", 0, "issue", "ca.local", 3600),
+		NewRecordWebRedirect("go", "https://example.com", WebRedirect{RedirectType: 301}, 3600),
+		NewRecordCNAME("old", "www.api-example.com", 3600),
+	}
+
+	recordMap := make(RecordMap)
+	for i, record := range records {
+		record.ID = i + 1
+		recordMap[record.ID] = record
+	}
+
+	old := recordMap[8]
+	old.IsActive = false
+	recordMap[8] = old
+
+	return recordMap
+}
+
+func TestRecordMap_Export_BIND(t *testing.T) {
+	// when
+	content, err := newTestExportRecords().Export(testDomain, RecordFormatBIND)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "$ORIGIN api-example.com.\n"+
+		"api-example.com.\t3600\tIN\tA\t192.0.2.1\n"+
+		"www.api-example.com.\t300\tIN\tAAAA\t2001:db8::1\n"+
+		"api-example.com.\t3600\tIN\tMX\t10 mail.api-example.com.\n"+
+		"api-example.com.\t3600\tIN\tTXT\t\"v=spf1 include:\\\"spf.local\\\" -all\"\n"+
+		"_sip._tls.api-example.com.\t3600\tIN\tSRV\t10 20 5061 sip.local.\n"+
+		"api-example.com.\t3600\tIN\tCAA\t0 issue \"ca.local\"\n"+
+		"; go.api-example.com.\t3600\tWR\thttps://example.com\n"+
+		"; old.api-example.com.\t3600\tIN\tCNAME\twww.api-example.com.\n", content)
+}
+
+func TestRecordMap_Export_BIND_RoundTrip(t *testing.T) {
+	// given
+	recordMap := newTestExportRecords()
+	delete(recordMap, 7)
+	delete(recordMap, 8)
+	txt := recordMap[4]
+	txt.Record = "v=spf1 -all"
+	recordMap[4] = txt
+
+	// when
+	content, err := recordMap.Export(testDomain, RecordFormatBIND)
+	assert.NoError(t, err)
+	records, err := ParseBIND(testDomain, content)
+
+	// then
+	assert.NoError(t, err)
+	expected := recordMap.SortedSlice()
+	for i := range expected {
+		expected[i].ID = 0
+	}
+	assert.Equal(t, expected, records)
+}
+
+func TestRecordMap_Export_TinyDNS(t *testing.T) {
+	// when
+	content, err := newTestExportRecords().Export(testDomain, RecordFormatTinyDNS)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "+api-example.com:192.0.2.1:3600\n"+
+		":www.api-example.com:28: \\001\\015\\270\\000\\000\\000\\000\\000\\000\\000\\000\\000\\000\\000\\001:300\n"+
+		"@api-example.com::mail.api-example.com:10:3600\n"+
+		"'api-example.com:v=spf1 include\\072\"spf.local\" -all:3600\n"+
+		":_sip._tls.api-example.com:33:\\000\\012\\000\\024\\023\\305\\003sip\\005local\\000:3600\n"+
+		":api-example.com:257:\\000\\005issueca.local:3600\n"+
+		"# go.api-example.com:WR:https://example.com:3600\n"+
+		"# Cold.api-example.com:www.api-example.com:3600\n", content)
+}
+
+func TestRecordMap_Export_JSON(t *testing.T) {
+	// when
+	content, err := newTestExportRecords().Export(testDomain, RecordFormatJSON)
+
+	// then
+	assert.NoError(t, err)
+
+	var document struct {
+		Kind    string
+		Version int
+		Data    ZoneRecordsDocument
+	}
+	assert.NoError(t, json.Unmarshal([]byte(content), &document))
+	assert.Equal(t, "zone-records", document.Kind)
+	assert.Equal(t, DocumentVersion, document.Version)
+	assert.Equal(t, testDomain, document.Data.Zone)
+	assert.Len(t, document.Data.Records, 8)
+	assert.Equal(t, "www", document.Data.Records[1].Host)
+}
+
+func TestRecordMap_Export_CSV(t *testing.T) {
+	// when
+	content, err := newTestExportRecords().Export(testDomain, RecordFormatCSV)
+
+	// then
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(content), "\n")
+	assert.Len(t, lines, 9)
+	assert.Equal(t, "id,host,type,value,ttl,active,geodns_location,params", lines[0])
+	assert.Equal(t, "1,,A,192.0.2.1,3600,true,0,", lines[1])
+	assert.Equal(t, `3,,MX,mail.api-example.com,3600,true,0,"{""priority"":10}"`, lines[3])
+	assert.Equal(t, `4,,TXT,"v=spf1 include:""spf.local"" -all",3600,true,0,`, lines[4])
+	assert.Equal(t, "8,old,CNAME,www.api-example.com,3600,false,0,", lines[8])
+}
+
+func TestRecordMap_Export_InvalidFormat(t *testing.T) {
+	_, err := newTestExportRecords().Export(testDomain, RecordFormat(-1))
+	assert.ErrorIs(t, err, ErrIllegalArgument)
+}
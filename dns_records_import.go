@@ -0,0 +1,466 @@
+package cloudns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+)
+
+// ImportConflictPolicy is an enumeration of client-side strategies for handling imported records which conflict with
+// existing records, which are records sharing the same host and record type
+type ImportConflictPolicy int
+
+// Enumeration values for ImportConflictPolicy
+const (
+	// ImportConflictServerSide passes the import to ClouDNS, which either keeps or deletes all existing records
+	ImportConflictServerSide ImportConflictPolicy = iota
+	// ImportConflictSkip keeps existing records and skips all conflicting imported records
+	ImportConflictSkip
+	// ImportConflictOverwrite replaces existing records with the conflicting imported records
+	ImportConflictOverwrite
+	// ImportConflictFail aborts the import without performing any changes if any conflict was found
+	ImportConflictFail
+)
+
+// ImportOptions specifies how records are imported into a zone
+type ImportOptions struct {
+	// Overwrite causes ClouDNS to delete all existing records before importing. It can only be used together with
+	// ImportConflictServerSide.
+	Overwrite bool
+	// ConflictPolicy enables a client-side import which parses the records, compares them against the existing records
+	// and applies the differences with single record operations. This is only supported for the BIND format.
+	ConflictPolicy ImportConflictPolicy
+	// CountRecords determines the amount of added and removed records for imports handled by ClouDNS, by comparing the
+	// record count of the zone before and after the import. This requires two additional API requests.
+	CountRecords bool
+	// ConfirmOverwrite is called with all records which are about to be deleted by an import with Overwrite, which only
+	// proceeds if true has been returned. Otherwise, the import is aborted with ErrImportNotConfirmed.
+	ConfirmOverwrite func(preflight ImportPreflight) bool
+	// RecordLimit is the maximum amount of records allowed within the zone by the plan of the account. If set, the
+	// amount of records after the import is computed upfront and the import fails with ErrRecordQuotaExceeded without
+	// performing any changes if it would exceed the limit. For server-side imports, this requires an additional API
+	// request unless Overwrite is set.
+	RecordLimit int
+}
+
+// ImportPreflight contains all existing records which would be deleted by an import with overwrite
+type ImportPreflight struct {
+	Records []Record
+}
+
+// ImportResult represents the result of a record import. The amount of records is always available for client-side
+// imports and only available for server-side imports if ImportOptions.CountRecords has been specified.
+type ImportResult struct {
+	StatusResult
+
+	Added   int
+	Updated int
+	Removed int
+	Skipped int
+	// Pending is the amount of changes of a client-side import which have not been applied, because the import failed
+	// or its context was cancelled midway
+	Pending int
+	// Preflight contains the records which have been deleted by an import with overwrite, only available if
+	// ImportOptions.ConfirmOverwrite has been specified
+	Preflight *ImportPreflight
+}
+
+// importPlan contains all record operations required for a client-side import
+type importPlan struct {
+	create  []Record
+	update  map[int]Record
+	delete  []Record
+	skipped []Record
+	// surplus contains existing records of imported record sets which neither match an imported record nor are
+	// updated or deleted by the plan
+	surplus []Record
+}
+
+// ImportWithOptions imports records with a specific format into the zone, handling conflicts with existing records as
+// specified by the options. Client-side conflict policies never delete records which do not conflict with imported ones.
+// If a client-side import fails or gets cancelled midway, the result contains the changes which have been applied until
+// then as well as the amount of pending changes. Cancelling a server-side import only stops waiting for its completion,
+// as ClouDNS continues processing the import.
+// Official Docs: https://www.cloudns.net/wiki/article/156/
+func (svc *RecordService) ImportWithOptions(ctx context.Context, zoneName string, format RecordFormat, content string, options ImportOptions) (result ImportResult, err error) {
+	if options.ConflictPolicy == ImportConflictServerSide {
+		return svc.importServerSide(ctx, zoneName, format, content, options)
+	}
+
+	if options.Overwrite {
+		return result, ErrIllegalArgument.wrap(errors.New("overwrite can not be combined with client-side conflict policy"))
+	}
+	if format != RecordFormatBIND {
+		return result, ErrIllegalArgument.wrap(errors.New("client-side conflict policies require bind format"))
+	}
+
+	records, err := ParseBIND(zoneName, content)
+	if err != nil {
+		return
+	}
+
+	existing, err := svc.List(ctx, zoneName)
+	if err != nil {
+		return
+	}
+
+	plan, err := planImport(existing, records, options.ConflictPolicy)
+	if err != nil {
+		return
+	}
+	if err = checkRecordLimit(zoneName, options.RecordLimit, len(existing)+len(plan.create)-len(plan.delete)); err != nil {
+		return
+	}
+
+	result.Skipped = len(plan.skipped)
+	changes := plan.changes()
+	applied, err := svc.ApplyChanges(ctx, zoneName, changes)
+	result.Pending = len(CoalesceChanges(changes)) - len(applied)
+	for _, change := range applied {
+		switch change.Type {
+		case RecordChangeCreate:
+			result.Added++
+		case RecordChangeUpdate:
+			result.Updated++
+		case RecordChangeDelete:
+			result.Removed++
+		}
+	}
+	if err != nil {
+		return
+	}
+
+	result.Status = "Success"
+	result.StatusDescription = fmt.Sprintf("%d records added, %d updated, %d removed, %d skipped",
+		result.Added, result.Updated, result.Removed, result.Skipped)
+	return
+}
+
+func (svc *RecordService) importServerSide(ctx context.Context, zoneName string, format RecordFormat, content string, options ImportOptions) (result ImportResult, err error) {
+	params := HTTPParams{"domain-name": zoneName, "content": content}
+
+	switch format {
+	case RecordFormatBIND:
+		params["format"] = "bind"
+	case RecordFormatTinyDNS:
+		params["format"] = "tinydns"
+	default:
+		return result, ErrIllegalArgument.wrap(errors.New("invalid record format"))
+	}
+
+	if options.Overwrite {
+		params["delete-existing-records"] = 1
+	} else {
+		params["delete-existing-records"] = 0
+	}
+
+	var countBefore, countAfter int
+	if options.CountRecords || (options.RecordLimit > 0 && !options.Overwrite) {
+		if countBefore, err = svc.api.Zones.GetRecordCount(ctx, zoneName); err != nil {
+			return
+		}
+	}
+
+	if options.RecordLimit > 0 {
+		var imported int
+		if imported, err = countImportedRecords(zoneName, format, content); err != nil {
+			return
+		}
+
+		projected := countBefore + imported
+		if options.Overwrite {
+			projected = imported
+		}
+		if err = checkRecordLimit(zoneName, options.RecordLimit, projected); err != nil {
+			return
+		}
+	}
+
+	if options.Overwrite && options.ConfirmOverwrite != nil {
+		var existing RecordMap
+		if existing, err = svc.List(ctx, zoneName); err != nil {
+			return
+		}
+
+		preflight := ImportPreflight{Records: existing.SortedSlice()}
+		if !options.ConfirmOverwrite(preflight) {
+			return result, ErrImportNotConfirmed.wrap(fmt.Errorf("import would delete %s", preflight))
+		}
+		result.Preflight = &preflight
+	}
+
+	if err = svc.api.request(ctx, "POST", recordImportURL, params, nil, &result.StatusResult); err != nil {
+		return
+	}
+
+	if options.CountRecords {
+		if countAfter, err = svc.api.Zones.GetRecordCount(ctx, zoneName); err != nil {
+			return
+		}
+
+		if options.Overwrite {
+			result.Removed = countBefore
+			result.Added = countAfter
+		} else if countAfter > countBefore {
+			result.Added = countAfter - countBefore
+		}
+	}
+
+	return
+}
+
+// countImportedRecords returns the amount of records contained by the import. TinyDNS imports are not parsed, so the
+// amount is approximated by counting all lines which are neither empty nor comments.
+func countImportedRecords(zoneName string, format RecordFormat, content string) (int, error) {
+	if format == RecordFormatBIND {
+		records, err := ParseBIND(zoneName, content)
+		return len(records), err
+	}
+
+	count := 0
+	for _, line := range strings.Split(content, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// checkRecordLimit fails with ErrRecordQuotaExceeded if the projected amount of records exceeds the given limit, which
+// is not enforced if zero
+func checkRecordLimit(zoneName string, limit, projected int) error {
+	if limit > 0 && projected > limit {
+		return ErrRecordQuotaExceeded.wrap(fmt.Errorf("zone %s would contain %d records, exceeding the limit of %d",
+			zoneName, projected, limit))
+	}
+
+	return nil
+}
+
+// Summary returns the amount of records which would be deleted per record type
+func (preflight ImportPreflight) Summary() map[RecordType]int {
+	summary := make(map[RecordType]int)
+	for _, record := range preflight.Records {
+		summary[record.RecordType]++
+	}
+
+	return summary
+}
+
+// String returns a human-readable summary of the records which would be deleted, e.g. "3 records (2 A, 1 MX)"
+func (preflight ImportPreflight) String() string {
+	summary := preflight.Summary()
+	recordTypes := make([]string, 0, len(summary))
+	for recordType := range summary {
+		recordTypes = append(recordTypes, string(recordType))
+	}
+	sort.Strings(recordTypes)
+
+	parts := make([]string, 0, len(recordTypes))
+	for _, recordType := range recordTypes {
+		parts = append(parts, fmt.Sprintf("%d %s", summary[RecordType(recordType)], recordType))
+	}
+
+	if len(parts) == 0 {
+		return fmt.Sprintf("%d records", len(preflight.Records))
+	}
+	return fmt.Sprintf("%d records (%s)", len(preflight.Records), strings.Join(parts, ", "))
+}
+
+// changes returns the record changes required for applying the import plan
+func (plan importPlan) changes() []RecordChange {
+	var changes []RecordChange
+	for _, record := range plan.create {
+		changes = append(changes, RecordChange{Type: RecordChangeCreate, Record: record})
+	}
+	for _, id := range sortedRecordIDs(plan.update) {
+		changes = append(changes, RecordChange{Type: RecordChangeUpdate, ID: id, Record: plan.update[id]})
+	}
+	for _, record := range plan.delete {
+		changes = append(changes, RecordChange{Type: RecordChangeDelete, ID: record.ID, Record: record})
+	}
+
+	return changes
+}
+
+// planImport compares the existing records of a zone with the imported records and determines the required operations
+// according to the given conflict policy. Imported records which are identical to an existing record are always skipped.
+// Plans which would place a CNAME record next to other records of the same host fail with ErrImportConflict regardless
+// of the policy, as ClouDNS would reject them halfway through applying the changes.
+func planImport(existing RecordMap, imported []Record, policy ImportConflictPolicy) (plan importPlan, err error) {
+	if plan, err = diffRecords(existing, imported, policy); err != nil {
+		return
+	}
+
+	err = plan.checkCNAMEConflicts(existing)
+	return
+}
+
+// diffRecords determines the operations of an import plan like planImport, but without checking the resulting zone for
+// CNAME conflicts, which allows callers to amend the plan before checking it
+func diffRecords(existing RecordMap, imported []Record, policy ImportConflictPolicy) (plan importPlan, err error) {
+	plan.update = make(map[int]Record)
+
+	existingGroups := make(map[string][]Record)
+	for _, record := range existing.SortedSlice() {
+		key := recordSetKey(record.Host, record.RecordType)
+		existingGroups[key] = append(existingGroups[key], record)
+	}
+
+	importedGroups := make(map[string][]Record)
+	var importedKeys []string
+	for _, record := range imported {
+		key := recordSetKey(record.Host, record.RecordType)
+		if _, ok := importedGroups[key]; !ok {
+			importedKeys = append(importedKeys, key)
+		}
+		importedGroups[key] = append(importedGroups[key], record)
+	}
+
+	for _, key := range importedKeys {
+		// Skip all imported records which are already present in identical form
+		var remaining, conflicting []Record
+		unmatched := append([]Record(nil), existingGroups[key]...)
+		for _, record := range importedGroups[key] {
+			if index := indexOfEquivalentRecord(unmatched, record); index >= 0 {
+				unmatched = append(unmatched[:index], unmatched[index+1:]...)
+				plan.skipped = append(plan.skipped, record)
+			} else {
+				remaining = append(remaining, record)
+			}
+		}
+		conflicting = unmatched
+
+		if len(remaining) == 0 {
+			plan.surplus = append(plan.surplus, conflicting...)
+			continue
+		}
+		if len(conflicting) == 0 {
+			plan.create = append(plan.create, remaining...)
+			continue
+		}
+
+		switch policy {
+		case ImportConflictSkip:
+			plan.skipped = append(plan.skipped, remaining...)
+			plan.surplus = append(plan.surplus, conflicting...)
+		case ImportConflictOverwrite:
+			for index, record := range remaining {
+				if index < len(conflicting) {
+					plan.update[conflicting[index].ID] = record
+				} else {
+					plan.create = append(plan.create, record)
+				}
+			}
+			if len(conflicting) > len(remaining) {
+				plan.delete = append(plan.delete, conflicting[len(remaining):]...)
+			}
+		case ImportConflictFail:
+			return plan, ErrImportConflict.wrap(fmt.Errorf("%s record for host [%s] already exists",
+				remaining[0].RecordType, remaining[0].Host))
+		default:
+			return plan, ErrIllegalArgument.wrap(fmt.Errorf("unknown conflict policy: %d", policy))
+		}
+	}
+
+	return plan, nil
+}
+
+// checkCNAMEConflicts fails with ErrImportConflict if applying the plan to the existing records would result in a host
+// with a CNAME record next to any other record. Only hosts with created or updated records are checked.
+func (plan importPlan) checkCNAMEConflicts(existing RecordMap) error {
+	removed := make(map[int]bool)
+	for _, record := range plan.delete {
+		removed[record.ID] = true
+	}
+	for id := range plan.update {
+		removed[id] = true
+	}
+
+	planned := append([]Record(nil), plan.create...)
+	for _, id := range sortedRecordIDs(plan.update) {
+		planned = append(planned, plan.update[id])
+	}
+
+	hosts := make(map[string][]Record)
+	for _, record := range existing.SortedSlice() {
+		if !removed[record.ID] {
+			host := normalizeRecordHost(record.Host)
+			hosts[host] = append(hosts[host], record)
+		}
+	}
+	for _, record := range planned {
+		host := normalizeRecordHost(record.Host)
+		hosts[host] = append(hosts[host], record)
+	}
+
+	for _, record := range planned {
+		records := hosts[normalizeRecordHost(record.Host)]
+		cnames := 0
+		for _, candidate := range records {
+			if candidate.RecordType == RecordTypeCNAME {
+				cnames++
+			}
+		}
+
+		if cnames > 0 && len(records) > 1 {
+			return ErrImportConflict.wrap(fmt.Errorf("CNAME record for host [%s] can not coexist with other records",
+				record.Host))
+		}
+	}
+
+	return nil
+}
+
+// indexOfEquivalentRecord returns the index of the first record within the slice which is equivalent to the given
+// record, ignoring ID and status, or -1 if no such record exists
+func indexOfEquivalentRecord(records []Record, record Record) int {
+	for index, candidate := range records {
+		if recordsEquivalent(candidate, record) {
+			return index
+		}
+	}
+
+	return -1
+}
+
+// recordsEquivalent compares two records while ignoring their ID and status as well as cosmetic differences in the
+// representation of hostnames and IP addresses
+func recordsEquivalent(a, b Record) bool {
+	normalize := func(record Record) Record {
+		record.ID = 0
+		record.IsActive = true
+		record.Host = normalizeRecordHost(record.Host)
+		record.Record = normalizeRecordValue(record)
+
+		return record
+	}
+
+	return normalize(a) == normalize(b)
+}
+
+// normalizeRecordValue returns the value of a record without cosmetic differences in the representation of hostnames and
+// IP addresses
+func normalizeRecordValue(record Record) string {
+	if ip := net.ParseIP(record.Record); ip != nil {
+		return ip.String()
+	} else if record.RecordType != RecordTypeTXT {
+		return strings.TrimSuffix(record.Record, ".")
+	}
+
+	return record.Record
+}
+
+func sortedRecordIDs(records map[int]Record) []int {
+	ids := make([]int, 0, len(records))
+	for id := range records {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	return ids
+}
@@ -0,0 +1,257 @@
+package cloudns
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestPlanImport(t *testing.T) {
+	// given
+	existing := buildRecordMap(
+		NewRecordA("", "192.0.2.1", testTTL),
+		NewRecordA("www", "192.0.2.2", testTTL),
+		NewRecordMX("", 10, "mx1.local", testTTL),
+		NewRecordMX("", 20, "mx2.local", testTTL),
+		NewRecordTXT("manual", "hand-maintained", testTTL),
+	)
+	imported := []Record{
+		NewRecordA("", "192.0.2.1", testTTL),
+		NewRecordA("www", "192.0.2.3", testTTL),
+		NewRecordMX("", 30, "mx3.local", testTTL),
+		NewRecordCNAME("blog", "www.api-example.com", testTTL),
+	}
+
+	// when
+	skipPlan, skipErr := planImport(existing, imported, ImportConflictSkip)
+	overwritePlan, overwriteErr := planImport(existing, imported, ImportConflictOverwrite)
+	_, failErr := planImport(existing, imported, ImportConflictFail)
+
+	// then
+	assert.NoError(t, skipErr)
+	assert.Equal(t, []Record{imported[3]}, skipPlan.create)
+	assert.Empty(t, skipPlan.update)
+	assert.Empty(t, skipPlan.delete)
+	assert.Len(t, skipPlan.skipped, 3)
+
+	assert.NoError(t, overwriteErr)
+	assert.Equal(t, []Record{imported[3]}, overwritePlan.create)
+	assert.Equal(t, map[int]Record{2: imported[1], 3: imported[2]}, overwritePlan.update)
+	assert.Equal(t, []Record{existing[4]}, overwritePlan.delete, "surplus conflicting records should be removed")
+	assert.Len(t, overwritePlan.skipped, 1)
+
+	assert.True(t, errors.Is(failErr, ErrImportConflict), "conflicting import should fail")
+}
+
+func TestPlanImport_NoConflict(t *testing.T) {
+	existing := buildRecordMap(NewRecordA("", "192.0.2.1", testTTL))
+	imported := []Record{NewRecordA("", "192.0.2.1", testTTL), NewRecordA("www", "192.0.2.1", testTTL)}
+
+	plan, err := planImport(existing, imported, ImportConflictFail)
+	assert.NoError(t, err, "identical records should not be treated as conflict")
+	assert.Equal(t, []Record{imported[1]}, plan.create)
+}
+
+func TestPlanImport_CNAMEConflict(t *testing.T) {
+	// given
+	existing := buildRecordMap(
+		NewRecordA("www", "192.0.2.1", testTTL),
+		NewRecordTXT("www", "hand-maintained", testTTL),
+	)
+	imported := []Record{NewRecordCNAME("www.", "target.example.net", testTTL)}
+
+	// when
+	_, skipErr := planImport(existing, imported, ImportConflictSkip)
+	_, overwriteErr := planImport(existing, imported, ImportConflictOverwrite)
+	_, importedErr := planImport(RecordMap{}, []Record{
+		NewRecordCNAME("blog", "www.api-example.com", testTTL),
+		NewRecordA("blog", "192.0.2.1", testTTL),
+	}, ImportConflictFail)
+
+	// then
+	assert.ErrorIs(t, skipErr, ErrImportConflict, "cname next to existing records should be rejected")
+	assert.ErrorIs(t, overwriteErr, ErrImportConflict, "cname next to existing records should be rejected")
+	assert.ErrorIs(t, importedErr, ErrImportConflict, "cname next to imported records should be rejected")
+}
+
+func TestRecordService_ImportWithOptions_Invalid(t *testing.T) {
+	client, err := New()
+	assert.NoError(t, err)
+
+	test := func(format RecordFormat, options ImportOptions) {
+		_, err := client.Records.ImportWithOptions(context.Background(), testDomain, format, "", options)
+		assert.True(t, errors.Is(err, ErrIllegalArgument), "import with %+v should be rejected", options)
+	}
+
+	test(RecordFormatBIND, ImportOptions{Overwrite: true, ConflictPolicy: ImportConflictOverwrite})
+	test(RecordFormatTinyDNS, ImportOptions{ConflictPolicy: ImportConflictSkip})
+}
+
+func TestRecordService_ImportWithOptions_Overwrite(t *testing.T) {
+	// given
+	var requests []string
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		requests = append(requests, req.URL.Path)
+		if req.URL.Path == recordListURL {
+			return `{"7":{"id":"7","host":"www","record":"192.0.2.1","type":"A","ttl":"3600","status":1}}`
+		}
+		return `{"status":"Success","statusDescription":"OK"}`
+	})
+
+	// when
+	content := "www 3600 IN A 192.0.2.2\nblog 3600 IN A 192.0.2.2"
+	options := ImportOptions{ConflictPolicy: ImportConflictOverwrite}
+	result, err := stubClient.Records.ImportWithOptions(context.Background(), testDomain, RecordFormatBIND, content, options)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "Success", result.Status)
+	assert.Equal(t, 1, result.Added)
+	assert.Equal(t, 1, result.Updated)
+	assert.Equal(t, []string{recordListURL, recordCreateURL, recordUpdateURL}, requests)
+}
+
+func TestRecordService_ImportWithOptions_CountRecords(t *testing.T) {
+	// given
+	counts := []string{"10", "3"}
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		if req.URL.Path == zoneRecordCountURL {
+			count := counts[0]
+			counts = counts[1:]
+			return count
+		}
+		return `{"status":"Success","statusDescription":"The records were added successfully."}`
+	})
+
+	// when
+	options := ImportOptions{Overwrite: true, CountRecords: true}
+	result, err := stubClient.Records.ImportWithOptions(context.Background(), testDomain, RecordFormatTinyDNS, "=:1.2.3.4", options)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "Success", result.Status)
+	assert.Equal(t, 10, result.Removed, "all previous records should be counted as removed")
+	assert.Equal(t, 3, result.Added, "all current records should be counted as added")
+}
+
+func TestRecordService_ImportWithOptions_ConfirmOverwrite(t *testing.T) {
+	// given
+	var requests []string
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		requests = append(requests, req.URL.Path)
+		if req.URL.Path == recordListURL {
+			return `{
+				"1":{"id":"1","host":"www","record":"192.0.2.1","type":"A","ttl":"3600","status":1},
+				"2":{"id":"2","host":"","record":"mail.api-example.com","type":"MX","priority":"10","ttl":"3600","status":1}
+			}`
+		}
+		return `{"status":"Success","statusDescription":"The records were added successfully."}`
+	})
+	var summary string
+	options := ImportOptions{Overwrite: true, ConfirmOverwrite: func(preflight ImportPreflight) bool {
+		summary = preflight.String()
+		return false
+	}}
+
+	// when
+	_, err := stubClient.Records.ImportWithOptions(context.Background(), testDomain, RecordFormatTinyDNS, "=:1.2.3.4", options)
+
+	// then
+	assert.ErrorIs(t, err, ErrImportNotConfirmed)
+	assert.Equal(t, "2 records (1 A, 1 MX)", summary)
+	assert.Equal(t, []string{recordListURL}, requests, "import should not be performed without confirmation")
+
+	// when
+	options.ConfirmOverwrite = func(preflight ImportPreflight) bool { return true }
+	result, err := stubClient.Records.ImportWithOptions(context.Background(), testDomain, RecordFormatTinyDNS, "=:1.2.3.4", options)
+
+	// then
+	assert.NoError(t, err)
+	if assert.NotNil(t, result.Preflight) {
+		assert.Len(t, result.Preflight.Records, 2)
+	}
+	assert.Equal(t, recordImportURL, requests[len(requests)-1])
+}
+
+func TestRecordService_ImportWithOptions_Cancelled(t *testing.T) {
+	// given
+	cancelCtx, cancel := context.WithCancel(context.Background())
+	var creates int
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		switch req.URL.Path {
+		case recordListURL:
+			return `[]`
+		case recordCreateURL:
+			creates++
+			if creates == 2 {
+				cancel()
+			}
+		}
+		return `{"status":"Success","statusDescription":"OK"}`
+	})
+
+	// when
+	content := "a 3600 IN A 192.0.2.1\nb 3600 IN A 192.0.2.2\nc 3600 IN A 192.0.2.3\nd 3600 IN A 192.0.2.4"
+	options := ImportOptions{ConflictPolicy: ImportConflictSkip}
+	result, err := stubClient.Records.ImportWithOptions(cancelCtx, testDomain, RecordFormatBIND, content, options)
+
+	// then
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 2, creates, "no further records should be created after cancellation")
+	assert.Equal(t, 2, result.Added)
+	assert.Equal(t, 2, result.Pending)
+}
+
+func TestRecordService_ImportWithOptions_RecordLimit(t *testing.T) {
+	// given
+	var requests []string
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		requests = append(requests, req.URL.Path)
+		if req.URL.Path == zoneRecordCountURL {
+			return "9"
+		}
+		return `{"status":"Success","statusDescription":"The records were added successfully."}`
+	})
+	content := "=www:192.0.2.1\n# comment\n\n=api:192.0.2.2"
+
+	// when
+	_, err := stubClient.Records.ImportWithOptions(context.Background(), testDomain, RecordFormatTinyDNS, content,
+		ImportOptions{RecordLimit: 10})
+
+	// then
+	assert.ErrorIs(t, err, ErrRecordQuotaExceeded)
+	assert.Contains(t, err.Error(), "would contain 11 records, exceeding the limit of 10")
+	assert.Equal(t, []string{zoneRecordCountURL}, requests, "import should not be performed")
+
+	// when
+	requests = nil
+	_, err = stubClient.Records.ImportWithOptions(context.Background(), testDomain, RecordFormatTinyDNS, content,
+		ImportOptions{RecordLimit: 10, Overwrite: true})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []string{recordImportURL}, requests, "existing records should not be counted for overwrite")
+}
+
+func TestRecordService_ImportWithOptions_RecordLimit_ClientSide(t *testing.T) {
+	// given
+	var creates int
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		if req.URL.Path == recordListURL {
+			return `{"1":{"id":"1","host":"www","record":"192.0.2.1","type":"A","ttl":"3600","status":1}}`
+		}
+		creates++
+		return `{"status":"Success","statusDescription":"OK"}`
+	})
+	content := "www 3600 IN A 192.0.2.1\napi 3600 IN A 192.0.2.2\nmail 3600 IN A 192.0.2.3"
+
+	// when
+	_, err := stubClient.Records.ImportWithOptions(context.Background(), testDomain, RecordFormatBIND, content,
+		ImportOptions{ConflictPolicy: ImportConflictSkip, RecordLimit: 2})
+
+	// then
+	assert.ErrorIs(t, err, ErrRecordQuotaExceeded)
+	assert.Equal(t, 0, creates, "no records should be created")
+}
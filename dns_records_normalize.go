@@ -0,0 +1,71 @@
+package cloudns
+
+import (
+	"net"
+	"strings"
+)
+
+// RecordNormalizer returns a normalized copy of the given record, e.g. for avoiding spurious changes caused by cosmetic
+// differences in how ClouDNS echoes values back. Normalizers are registered with the NormalizeRecords option and must
+// be idempotent, as records are normalized both when being read and before being written.
+type RecordNormalizer func(record Record) Record
+
+// hostnameRecordTypes contains all record types whose record value is a hostname
+var hostnameRecordTypes = []RecordType{
+	RecordTypeALIAS, RecordTypeCNAME, RecordTypeMX, RecordTypeNS, RecordTypePTR, RecordTypeSRV,
+}
+
+// NormalizeHostCase lowercases the host of the record, as well as the record value if it is a hostname, e.g. the
+// target of a CNAME record
+func NormalizeHostCase(record Record) Record {
+	record.Host = strings.ToLower(record.Host)
+	for _, recordType := range hostnameRecordTypes {
+		if record.RecordType == recordType {
+			record.Record = strings.ToLower(record.Record)
+			break
+		}
+	}
+
+	return record
+}
+
+// NormalizeWhitespace trims leading and trailing whitespace from the host and record value of the record
+func NormalizeWhitespace(record Record) Record {
+	record.Host = strings.TrimSpace(record.Host)
+	record.Record = strings.TrimSpace(record.Record)
+	return record
+}
+
+// NormalizeIPv6 converts the address of AAAA records into its canonical textual form according to RFC 5952, e.g.
+// `2001:0db8:0000::0001` becomes `2001:db8::1`. Invalid addresses are left as-is.
+func NormalizeIPv6(record Record) Record {
+	if record.RecordType != RecordTypeAAAA {
+		return record
+	}
+
+	if ip := net.ParseIP(strings.TrimSpace(record.Record)); ip != nil && ip.To4() == nil {
+		record.Record = ip.String()
+	}
+
+	return record
+}
+
+// normalizeRecord applies all record normalizers of the client to the given record in order of registration
+func (c *Client) normalizeRecord(record Record) Record {
+	for _, normalizer := range c.recordNormalizers {
+		record = normalizer(record)
+	}
+
+	return record
+}
+
+// normalizeRecords applies all record normalizers of the client to the given records in-place
+func (c *Client) normalizeRecords(records RecordMap) {
+	if len(c.recordNormalizers) == 0 {
+		return
+	}
+
+	for id, record := range records {
+		records[id] = c.normalizeRecord(record)
+	}
+}
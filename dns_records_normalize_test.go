@@ -0,0 +1,73 @@
+package cloudns
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestNormalizers(t *testing.T) {
+	assert.Equal(t, NewRecordCNAME("www", "web.api-example.com", testTTL),
+		NormalizeHostCase(NewRecordCNAME("WWW", "Web.API-Example.com", testTTL)))
+	assert.Equal(t, NewRecordTXT("txt", "Hello World", testTTL),
+		NormalizeHostCase(NewRecordTXT("TXT", "Hello World", testTTL)), "txt values should keep their case")
+	assert.Equal(t, NewRecordA("www", "192.0.2.1", testTTL),
+		NormalizeWhitespace(NewRecordA(" www ", "192.0.2.1\n", testTTL)))
+	assert.Equal(t, NewRecordAAAA("www", "2001:db8::1", testTTL),
+		NormalizeIPv6(NewRecordAAAA("www", "2001:0DB8:0000:0000::0001", testTTL)))
+	assert.Equal(t, NewRecordAAAA("www", "invalid", testTTL),
+		NormalizeIPv6(NewRecordAAAA("www", "invalid", testTTL)))
+}
+
+func TestNormalizeRecords(t *testing.T) {
+	// given
+	var written []interface{}
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		if req.URL.Path == recordListURL {
+			return `{"1":{"id":"1","type":"AAAA","host":"WWW","record":"2001:0db8::0001","ttl":"3600","status":1}}`
+		}
+
+		var params map[string]interface{}
+		_ = json.NewDecoder(req.Body).Decode(&params)
+		written = append(written, params["host"], params["record"])
+		return `{"status":"Success"}`
+	}, NormalizeRecords(NormalizeHostCase, NormalizeIPv6))
+
+	// when
+	records, listErr := stubClient.Records.List(context.Background(), testDomain)
+	_, createErr := stubClient.Records.Create(context.Background(), testDomain,
+		NewRecordAAAA("API", "2001:db8:0:0::2", testTTL))
+
+	// then
+	assert.NoError(t, listErr)
+	assert.NoError(t, createErr)
+	assert.Equal(t, "www", records[1].Host)
+	assert.Equal(t, "2001:db8::1", records[1].Record)
+	assert.Equal(t, []interface{}{"api", "2001:db8::2"}, written)
+}
+
+func TestNormalizeRecords_Sync(t *testing.T) {
+	// given
+	var requests []string
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		requests = append(requests, req.URL.Path)
+		return `{"1":{"id":"1","type":"AAAA","host":"www","record":"2001:db8::1","ttl":"3600","status":1}}`
+	}, NormalizeRecords(NormalizeIPv6))
+
+	// when
+	plan, err := stubClient.Records.Sync(context.Background(), testDomain, []Record{
+		NewRecordAAAA("www", "2001:0db8::0001", 3600),
+	}, SyncOptions{})
+
+	// then
+	assert.NoError(t, err)
+	assert.Empty(t, plan.Changes, "cosmetic differences should not produce changes")
+	assert.Equal(t, []string{recordListURL}, requests)
+}
+
+func TestNormalizeRecords_Nil(t *testing.T) {
+	_, err := New(NormalizeRecords(nil))
+	assert.ErrorIs(t, err, ErrInvalidOptions)
+}
@@ -0,0 +1,95 @@
+package cloudns
+
+import (
+	"context"
+	"fmt"
+)
+
+// recordDefaultPageSize is the amount of records fetched per page if no page size has been specified
+const recordDefaultPageSize = 100
+
+// RecordPageOptions specifies how records are listed page by page
+type RecordPageOptions struct {
+	// Host and RecordType optionally restrict the listing like RecordService.Search
+	Host       string
+	RecordType RecordType
+	// PageSize is the amount of records fetched per request, defaulting to 100
+	PageSize int
+	// MaxAttempts is the maximum amount of complete listings attempted if the zone changes while being listed,
+	// defaulting to 3
+	MaxAttempts int
+}
+
+// ListPaged lists the records of the given zone page by page, which keeps the size of single responses bounded for
+// large zones. The result is equivalent to RecordService.Search and should be iterated with RecordMap.SortedSlice for a
+// stable order.
+//
+// ClouDNS paginates by offset, so a zone which changes during the listing could cause records to be skipped or listed
+// twice. To provide a consistent snapshot, the SOA serial is compared before and after the listing, which ClouDNS
+// increments on every change. If the serial changed, the listing is repeated up to MaxAttempts times before failing with
+// ErrInconsistentListing. Records listed on multiple pages are deduplicated by their ID in any case.
+func (svc *RecordService) ListPaged(ctx context.Context, zoneName string, options RecordPageOptions) (RecordMap, error) {
+	if options.PageSize <= 0 {
+		options.PageSize = recordDefaultPageSize
+	}
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = 3
+	}
+
+	for attempt := 1; attempt <= options.MaxAttempts; attempt++ {
+		before, err := svc.GetSOA(ctx, zoneName)
+		if err != nil {
+			return nil, err
+		}
+
+		records, err := svc.listPages(ctx, zoneName, options)
+		if err != nil {
+			return nil, err
+		}
+
+		after, err := svc.GetSOA(ctx, zoneName)
+		if err != nil {
+			return nil, err
+		}
+		if before.Serial == after.Serial {
+			return records, nil
+		}
+	}
+
+	return nil, ErrInconsistentListing.wrap(fmt.Errorf("zone %s changed during %d attempts", zoneName, options.MaxAttempts))
+}
+
+func (svc *RecordService) listPages(ctx context.Context, zoneName string, options RecordPageOptions) (RecordMap, error) {
+	params := HTTPParams{"domain-name": zoneName, "rows-per-page": options.PageSize}
+	if options.Host != "" {
+		params["host"] = options.Host
+	}
+	if options.RecordType != "" {
+		params["type"] = options.RecordType
+	}
+
+	results := make(RecordMap)
+	for page := 1; ; page++ {
+		params["page"] = page
+
+		var records RecordMap
+		if err := svc.api.request(ctx, "POST", recordListURL, params, nil, &records); err != nil {
+			return nil, err
+		}
+		svc.api.normalizeRecords(records)
+
+		added := 0
+		for id, record := range records {
+			if _, ok := results[id]; !ok {
+				results[id] = record
+				added++
+			}
+		}
+
+		// A page without new records indicates the end of the listing, as it might only repeat records of the
+		// previous page which were shifted by deletions
+		if len(records) < options.PageSize || added == 0 {
+			return results, nil
+		}
+	}
+}
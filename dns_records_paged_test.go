@@ -0,0 +1,80 @@
+package cloudns
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// pagedRecordsHandler serves the given records with offset-based pagination and returns the given SOA serials in order
+func pagedRecordsHandler(records []Record, serials ...int) func(req *http.Request) string {
+	return func(req *http.Request) string {
+		if req.URL.Path == recordSOAGetURL {
+			serial := serials[0]
+			if len(serials) > 1 {
+				serials = serials[1:]
+			}
+			return fmt.Sprintf(`{"serialNumber":"%d"}`, serial)
+		}
+
+		var params struct {
+			Page        int `json:"page"`
+			RowsPerPage int `json:"rows-per-page"`
+		}
+		_ = json.NewDecoder(req.Body).Decode(&params)
+
+		var entries []string
+		for index := (params.Page - 1) * params.RowsPerPage; index < params.Page*params.RowsPerPage && index < len(records); index++ {
+			record := records[index]
+			entries = append(entries, fmt.Sprintf(`"%d":{"id":"%d","host":"%s","record":"%s","type":"A","ttl":"3600","status":1}`,
+				record.ID, record.ID, record.Host, record.Record))
+		}
+		if len(entries) == 0 {
+			return `[]`
+		}
+		return "{" + strings.Join(entries, ",") + "}"
+	}
+}
+
+func TestRecordService_ListPaged(t *testing.T) {
+	// given
+	records := buildRecordMap(
+		NewRecordA("a", "192.0.2.1", testTTL),
+		NewRecordA("b", "192.0.2.2", testTTL),
+		NewRecordA("c", "192.0.2.3", testTTL),
+		NewRecordA("d", "192.0.2.4", testTTL),
+		NewRecordA("e", "192.0.2.5", testTTL),
+	).SortedSlice()
+	stubClient := newStubClient(t, pagedRecordsHandler(records, 1))
+
+	// when
+	result, err := stubClient.Records.ListPaged(context.Background(), testDomain, RecordPageOptions{PageSize: 2})
+
+	// then
+	assert.NoError(t, err)
+	assert.Len(t, result, 5)
+	assert.Equal(t, "e", result.SortedSlice()[4].Host)
+}
+
+func TestRecordService_ListPaged_Changed(t *testing.T) {
+	records := buildRecordMap(NewRecordA("a", "192.0.2.1", testTTL)).SortedSlice()
+
+	t.Run("retried", func(t *testing.T) {
+		stubClient := newStubClient(t, pagedRecordsHandler(records, 1, 2, 2, 2))
+
+		result, err := stubClient.Records.ListPaged(context.Background(), testDomain, RecordPageOptions{})
+		assert.NoError(t, err)
+		assert.Len(t, result, 1)
+	})
+
+	t.Run("inconsistent", func(t *testing.T) {
+		stubClient := newStubClient(t, pagedRecordsHandler(records, 1, 2, 3, 4))
+
+		_, err := stubClient.Records.ListPaged(context.Background(), testDomain, RecordPageOptions{MaxAttempts: 2})
+		assert.ErrorIs(t, err, ErrInconsistentListing)
+	})
+}
@@ -0,0 +1,37 @@
+package cloudns
+
+import (
+	"context"
+)
+
+// RecordStats represents the amount of records within a zone, grouped by record type
+type RecordStats struct {
+	Total int
+	// Inactive is the amount of records which are disabled, but still count towards the record limit of the zone
+	Inactive int
+	ByType   map[RecordType]int
+}
+
+// Stats returns the amount of records within the given zone grouped by record type, which is computed client-side
+// based on a single listing of the zone
+func (svc *RecordService) Stats(ctx context.Context, zoneName string) (RecordStats, error) {
+	records, err := svc.List(ctx, zoneName)
+	if err != nil {
+		return RecordStats{}, err
+	}
+
+	return records.Stats(), nil
+}
+
+// Stats returns the amount of records within the record map grouped by record type
+func (rm RecordMap) Stats() RecordStats {
+	stats := RecordStats{Total: len(rm), ByType: make(map[RecordType]int)}
+	for _, record := range rm {
+		stats.ByType[record.RecordType]++
+		if !record.IsActive {
+			stats.Inactive++
+		}
+	}
+
+	return stats
+}
@@ -0,0 +1,37 @@
+package cloudns
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestRecordService_Stats(t *testing.T) {
+	// given
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		assert.Equal(t, recordListURL, req.URL.Path)
+		return `{
+			"1":{"id":"1","type":"A","host":"www","record":"192.0.2.1","ttl":"3600","status":1},
+			"2":{"id":"2","type":"A","host":"api","record":"192.0.2.2","ttl":"3600","status":0},
+			"3":{"id":"3","type":"TXT","host":"","record":"v=spf1 -all","ttl":"3600","status":1}
+		}`
+	})
+
+	// when
+	stats, err := stubClient.Records.Stats(context.Background(), testDomain)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, RecordStats{
+		Total:    3,
+		Inactive: 1,
+		ByType:   map[RecordType]int{RecordTypeA: 2, RecordTypeTXT: 1},
+	}, stats)
+}
+
+func TestRecordMap_Stats_Empty(t *testing.T) {
+	stats := RecordMap{}.Stats()
+	assert.Equal(t, 0, stats.Total)
+	assert.NotNil(t, stats.ByType)
+}
@@ -1,9 +1,13 @@
 package cloudns
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/stretchr/testify/assert"
 	"math/rand"
+	"net/http"
 	"net/url"
 	"strings"
 	"testing"
@@ -92,6 +96,162 @@ func TestRecordService_List(t *testing.T) {
 	}
 }
 
+func TestRecordService_Get(t *testing.T) {
+	// given
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `{"1":{"id":"1","type":"A","host":"www","record":"192.0.2.1","ttl":"3600","status":1}}`
+	})
+
+	// when
+	record, err := stubClient.Records.Get(context.Background(), testDomain, 1)
+	_, missingErr := stubClient.Records.Get(context.Background(), testDomain, 2)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "www", record.Host)
+	assert.Equal(t, 1, record.ID)
+	assert.ErrorIs(t, missingErr, ErrRecordNotFound)
+	assert.Contains(t, missingErr.Error(), "record 2 does not exist in zone "+testDomain)
+}
+
+func TestRecordService_DeleteMatching(t *testing.T) {
+	// given
+	var deletedIDs []string
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		if req.URL.Path == recordListURL {
+			return `{
+				"1":{"id":"1","type":"TXT","host":"_acme-challenge","record":"old","ttl":"60","status":1},
+				"2":{"id":"2","type":"TXT","host":"_acme-challenge.www","record":"other","ttl":"60","status":1},
+				"3":{"id":"3","type":"TXT","host":"_acme-challenge","record":"older","ttl":"60","status":1}
+			}`
+		}
+		var params map[string]interface{}
+		_ = json.NewDecoder(req.Body).Decode(&params)
+		deletedIDs = append(deletedIDs, fmt.Sprint(params["record-id"]))
+		return `{"status":"Success","statusDescription":"The record was deleted successfully."}`
+	})
+
+	// when
+	deleted, err := stubClient.Records.DeleteMatching(context.Background(), testDomain, "_acme-challenge", RecordTypeTXT)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 3}, deleted, "only exact host matches should be deleted")
+	assert.Equal(t, []string{"1", "3"}, deletedIDs)
+}
+
+func TestRecord_ExtendedTypes(t *testing.T) {
+	// given
+	var records RecordMap
+	listing := `{
+		"1":{"id":"1","type":"DS","host":"sub","record":"ABCDEF","ttl":"3600","status":1,
+			"key_tag":"12345","ds_algorithm":"13","digest_type":"2"},
+		"2":{"id":"2","type":"SSHFP","host":"ssh","record":"123456","ttl":"3600","status":1,
+			"algorithm":"4","fp_type":"2"},
+		"3":{"id":"3","type":"HINFO","host":"","record":"","ttl":"3600","status":1,"cpu":"amd64","os":"linux"}
+	}`
+
+	// when
+	err := json.Unmarshal([]byte(listing), &records)
+	params := NewRecordLOC("", LOC{LatitudeDegrees: 52, LatitudeDirection: "N", Altitude: -2.5}, 3600).AsParams()
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, DS{KeyTag: 12345, Algorithm: 13, DigestType: 2}, records[1].DS)
+	assert.Equal(t, SSHFP{Algorithm: 4, Type: 2}, records[2].SSHFP, "ds fields should not shadow sshfp fields")
+	assert.Equal(t, HINFO{CPU: "amd64", OS: "linux"}, records[3].HINFO)
+	assert.Equal(t, uint8(52), params["lat-deg"])
+	assert.Equal(t, "N", params["lat-dir"])
+	assert.Equal(t, -2.5, params["altitude"])
+	assert.Equal(t, uint16(12345), NewRecordDS("sub", 12345, 13, 2, "ABCDEF", 3600).AsParams()["key-tag"])
+}
+
+func TestRecord_ExtendedTypes_Listing(t *testing.T) {
+	// given
+	listings := map[RecordType]struct {
+		listing  string
+		expected Record
+	}{
+		RecordTypeDNAME: {
+			`{"id":"1","type":"DNAME","host":"legacy","record":"example.net","failover":"0","ttl":"3600","status":1}`,
+			NewRecordDNAME("legacy", "example.net", 3600),
+		},
+		RecordTypeOPENPGPKEY: {
+			`{"id":"1","type":"OPENPGPKEY","host":"_openpgpkey","record":"bWVtYmVy","failover":"0","ttl":"3600","status":1}`,
+			NewRecordOPENPGPKEY("_openpgpkey", "bWVtYmVy", 3600),
+		},
+		RecordTypeHINFO: {
+			`{"id":"1","type":"HINFO","host":"","record":"","cpu":"amd64","os":"linux","failover":"0","ttl":"3600","status":1}`,
+			NewRecordHINFO("", "amd64", "linux", 3600),
+		},
+		RecordTypeDS: {
+			`{"id":"1","type":"DS","host":"sub","record":"ABCDEF","key_tag":"12345","ds_algorithm":"13",
+				"digest_type":"2","failover":"0","ttl":"3600","status":1}`,
+			NewRecordDS("sub", 12345, 13, 2, "ABCDEF", 3600),
+		},
+		RecordTypeCERT: {
+			`{"id":"1","type":"CERT","host":"cert","record":"Y2VydA==","cert_type":"1","cert_key_tag":"12345",
+				"cert_algorithm":"8","failover":"0","ttl":"3600","status":1}`,
+			NewRecordCERT("cert", 1, 12345, 8, "Y2VydA==", 3600),
+		},
+		RecordTypeSMIMEA: {
+			`{"id":"1","type":"SMIMEA","host":"_smimecert","record":"ABCDEF","smimea_usage":"3","smimea_selector":"1",
+				"smimea_matching_type":"1","failover":"0","ttl":"3600","status":1}`,
+			NewRecordSMIMEA("_smimecert", 3, 1, 1, "ABCDEF", 3600),
+		},
+		RecordTypeLOC: {
+			`{"id":"1","type":"LOC","host":"office","record":"","lat_deg":"52","lat_min":"22","lat_sec":"23.5",
+				"lat_dir":"N","long_deg":"4","long_min":"53","long_sec":"32.25","long_dir":"E","altitude":"-2.5",
+				"size":"1","h_precision":"10000","v_precision":"10","failover":"0","ttl":"3600","status":1}`,
+			NewRecordLOC("office", LOC{
+				LatitudeDegrees: 52, LatitudeMinutes: 22, LatitudeSeconds: 23.5, LatitudeDirection: "N",
+				LongitudeDegrees: 4, LongitudeMinutes: 53, LongitudeSeconds: 32.25, LongitudeDirection: "E",
+				Altitude: -2.5, Size: 1, HorizontalPrecision: 10000, VerticalPrecision: 10,
+			}, 3600),
+		},
+	}
+
+	for recordType, fixture := range listings {
+		t.Run(string(recordType), func(t *testing.T) {
+			// when
+			var records RecordMap
+			err := json.Unmarshal([]byte(`{"1":`+fixture.listing+`}`), &records)
+
+			// then
+			expected := fixture.expected
+			expected.ID = 1
+			assert.NoError(t, err)
+			assert.Equal(t, expected, records[1])
+		})
+	}
+}
+
+func TestRecord_WebRedirect(t *testing.T) {
+	// given
+	var records RecordMap
+	listing := `{"1":{"id":"1","type":"WR","host":"www","record":"https://example.net","ttl":"3600","status":1,
+		"geodns-location":2,"mobile_meta":1,"save_path":1,"redirect_type":"302","frame":1,
+		"frame_title":"Title","frame_keywords":"a,b","frame_description":"Description"}}`
+	expected := NewRecordWebRedirect("www", "https://example.net", WebRedirect{
+		MobileMeta: true, SavePath: true, RedirectType: 302,
+		IsFrame: true, FrameTitle: "Title", FrameKeywords: "a,b", FrameDescription: "Description",
+	}, 3600)
+	expected.ID = 1
+	expected.GeoDNSLocationID = 2
+
+	// when
+	err := json.Unmarshal([]byte(listing), &records)
+	params := expected.AsParams()
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, expected, records[1])
+	assert.Equal(t, APIBool(true), params["mobile-meta"])
+	assert.Equal(t, APIBool(true), params["save-path"])
+	assert.Equal(t, "1", params["frame"])
+	assert.Equal(t, 2, params["geodns-location"])
+}
+
 func TestRecordService_Create(t *testing.T) {
 	teardown := setup(t)
 	defer teardown()
@@ -428,3 +588,97 @@ func TestRecordService_RecordTypes(t *testing.T) {
 		NewRecordTLSA("_443._tcp.", 2, 0, 1, "078a656e3670499c991bb0274682058af7bdc05fc462c605f0f8958179816cd7", 0),
 	)
 }
+
+func TestRecord_Validate_SRV(t *testing.T) {
+	validRecord := NewRecordSRV("_sip._tls", 10, 20, 5061, "sip.local", testTTL)
+	assert.NoError(t, validRecord.Validate(), "SRV record with hostname target should be valid")
+
+	for _, target := range []string{"192.0.2.1", "2001:db8::1", "192.0.2.1."} {
+		invalidRecord := NewRecordSRV("_sip._tls", 10, 20, 5061, target, testTTL)
+		err := invalidRecord.Validate()
+		assert.True(t, errors.Is(err, ErrIllegalArgument), "SRV record with target [%s] should be invalid", target)
+	}
+}
+
+func TestRecordService_Create_InvalidSRV(t *testing.T) {
+	client, err := New()
+	assert.NoError(t, err)
+
+	record := NewRecordSRV("_sip._tls", 10, 20, 5061, "192.0.2.1", testTTL)
+	_, err = client.Records.Create(context.Background(), testDomain, record)
+	assert.True(t, errors.Is(err, ErrIllegalArgument), "creating SRV record with ip target should fail early")
+}
+
+func TestRecordMap_FilterSRV(t *testing.T) {
+	records := RecordMap{
+		1: NewRecordSRV("_sip._tls", 10, 20, 5061, "sip1.local", testTTL),
+		2: NewRecordSRV("_SIP._TLS.voice", 10, 20, 5061, "sip2.local", testTTL),
+		3: NewRecordSRV("_sip._udp", 10, 20, 5060, "sip1.local", testTTL),
+		4: NewRecordTXT("_sip._tls", "not a srv record", testTTL),
+	}
+	for id, record := range records {
+		record.ID = id
+		records[id] = record
+	}
+
+	results := records.FilterSRV("sip", "_tls")
+	assert.Len(t, results, 2, "should return both _sip._tls records")
+	assert.Equal(t, 1, results[0].ID)
+	assert.Equal(t, 2, results[1].ID)
+}
+
+func TestRecordMap_SRVConflicts(t *testing.T) {
+	records := RecordMap{
+		1: NewRecordSRV("_sip._tls", 10, 20, 5061, "sip1.local", testTTL),
+		2: NewRecordSRV("_sip._tls", 20, 10, 5061, "SIP1.local.", testTTL),
+		3: NewRecordSRV("_sip._tls", 10, 20, 5061, "sip2.local", testTTL),
+		4: NewRecordSRV("_sip._udp", 10, 20, 5061, "sip1.local", testTTL),
+	}
+	for id, record := range records {
+		record.ID = id
+		records[id] = record
+	}
+
+	conflicts := records.SRVConflicts()
+	assert.Len(t, conflicts, 1, "should report a single conflicting host")
+	assert.Len(t, conflicts["_sip._tls"], 2, "should report both duplicate records")
+}
+
+func TestRecordMap_All(t *testing.T) {
+	records := buildRecordMap(
+		NewRecordA("www", "192.0.2.1", testTTL),
+		NewRecordTXT("", "v=spf1 -all", testTTL),
+		NewRecordA("mail", "192.0.2.2", testTTL),
+	)
+
+	var ids []int
+	for record := range records.All() {
+		ids = append(ids, record.ID)
+	}
+	assert.ElementsMatch(t, []int{1, 2, 3}, ids)
+
+	count := 0
+	for range records.All() {
+		count++
+		break
+	}
+	assert.Equal(t, 1, count, "iteration should stop on break")
+}
+
+func TestRecordMap_ByType(t *testing.T) {
+	records := buildRecordMap(
+		NewRecordA("www", "192.0.2.1", testTTL),
+		NewRecordTXT("", "v=spf1 -all", testTTL),
+		NewRecordA("mail", "192.0.2.2", testTTL),
+	)
+
+	var hosts []string
+	for record := range records.ByType(RecordTypeA) {
+		hosts = append(hosts, record.Host)
+	}
+	assert.ElementsMatch(t, []string{"www", "mail"}, hosts)
+
+	for range records.ByType(RecordTypeMX) {
+		t.Error("no MX records should be yielded")
+	}
+}
@@ -0,0 +1,106 @@
+package cloudns
+
+import (
+	"context"
+)
+
+// UpsertAction is an enumeration of all actions which might be taken by RecordService.Upsert
+type UpsertAction int
+
+// Enumeration values for UpsertAction
+const (
+	UpsertUnchanged UpsertAction = iota
+	UpsertCreated
+	UpsertUpdated
+)
+
+// UpsertResult represents the result of an upsert. Record contains the desired record, including the ID of the existing
+// record if it has been updated or left unchanged. The ID of created records is not returned by ClouDNS.
+type UpsertResult struct {
+	StatusResult
+
+	Action UpsertAction
+	Record Record
+}
+
+// singleValueRecordTypes contains all record types which only allow a single record per host
+var singleValueRecordTypes = []RecordType{
+	RecordTypeCNAME,
+	RecordTypeALIAS,
+	RecordTypeWebRedirect,
+}
+
+// String returns a human-readable name of the upsert action
+func (action UpsertAction) String() string {
+	switch action {
+	case UpsertUnchanged:
+		return "unchanged"
+	case UpsertCreated:
+		return "created"
+	case UpsertUpdated:
+		return "updated"
+	}
+
+	return "unknown"
+}
+
+// Upsert idempotently ensures that the given record exists within the zone. Existing records are matched by host, type
+// and value, so that e.g. changing the TTL of one of several A records updates the matching record instead of creating
+// another one. Records of types which only allow a single record per host, like CNAME, are matched by host and type.
+// No API request besides the search is performed if an equivalent record already exists.
+func (svc *RecordService) Upsert(ctx context.Context, zoneName string, record Record) (result UpsertResult, err error) {
+	record = svc.api.normalizeRecord(record)
+	if err = record.Validate(); err != nil {
+		return
+	}
+
+	existing, err := svc.Search(ctx, zoneName, record.Host, record.RecordType)
+	if err != nil {
+		return
+	}
+
+	var candidates []Record
+	key := recordSetKey(record.Host, record.RecordType)
+	for _, candidate := range existing.SortedSlice() {
+		if recordSetKey(candidate.Host, candidate.RecordType) == key {
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	if index := indexOfEquivalentRecord(candidates, record); index >= 0 {
+		result.Action = UpsertUnchanged
+		result.Record = candidates[index]
+		return
+	}
+
+	match, ok := matchUpsertCandidate(candidates, record)
+	if !ok {
+		record.ID = 0
+		result.Action = UpsertCreated
+		result.Record = record
+		result.StatusResult, err = svc.Create(ctx, zoneName, record)
+		return
+	}
+
+	record.ID = match.ID
+	result.Action = UpsertUpdated
+	result.Record = record
+	result.StatusResult, err = svc.Update(ctx, zoneName, match.ID, record)
+	return
+}
+
+// matchUpsertCandidate returns the existing record which should be updated to match the desired record, if any
+func matchUpsertCandidate(candidates []Record, record Record) (Record, bool) {
+	if len(candidates) == 1 && containsRecordType(record.RecordType, singleValueRecordTypes) {
+		return candidates[0], true
+	}
+
+	value := normalizeRecordValue(record)
+	for _, candidate := range candidates {
+		if normalizeRecordValue(candidate) == value {
+			return candidate, true
+		}
+	}
+
+	return Record{}, false
+}
@@ -0,0 +1,78 @@
+package cloudns
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func newUpsertStubClient(t *testing.T, existing string, requests *[]map[string]interface{}) *Client {
+	return newStubClient(t, func(req *http.Request) string {
+		if req.URL.Path == recordListURL {
+			return existing
+		}
+
+		var params map[string]interface{}
+		_ = json.NewDecoder(req.Body).Decode(&params)
+		params["path"] = req.URL.Path
+		*requests = append(*requests, params)
+		return `{"status":"Success","statusDescription":"OK"}`
+	})
+}
+
+func TestRecordService_Upsert(t *testing.T) {
+	existing := `{
+		"1":{"id":"1","host":"www","record":"192.0.2.1","type":"A","ttl":"3600","status":1},
+		"2":{"id":"2","host":"www","record":"192.0.2.2","type":"A","ttl":"3600","status":1},
+		"3":{"id":"3","host":"blog","record":"www.api-example.com","type":"CNAME","ttl":"3600","status":1}
+	}`
+
+	t.Run("unchanged", func(t *testing.T) {
+		var requests []map[string]interface{}
+		stubClient := newUpsertStubClient(t, existing, &requests)
+
+		result, err := stubClient.Records.Upsert(context.Background(), testDomain, NewRecordA("www", "192.0.2.2", testTTL))
+		assert.NoError(t, err)
+		assert.Equal(t, UpsertUnchanged, result.Action)
+		assert.Equal(t, 2, result.Record.ID)
+		assert.Empty(t, requests)
+	})
+
+	t.Run("updated by value", func(t *testing.T) {
+		var requests []map[string]interface{}
+		stubClient := newUpsertStubClient(t, existing, &requests)
+
+		result, err := stubClient.Records.Upsert(context.Background(), testDomain, NewRecordA("www", "192.0.2.2", 300))
+		assert.NoError(t, err)
+		assert.Equal(t, UpsertUpdated, result.Action)
+		assert.Equal(t, 2, result.Record.ID)
+		if assert.Len(t, requests, 1) {
+			assert.Equal(t, recordUpdateURL, requests[0]["path"])
+			assert.EqualValues(t, 2, requests[0]["record-id"])
+		}
+	})
+
+	t.Run("updated single value type", func(t *testing.T) {
+		var requests []map[string]interface{}
+		stubClient := newUpsertStubClient(t, existing, &requests)
+
+		result, err := stubClient.Records.Upsert(context.Background(), testDomain, NewRecordCNAME("blog", "blog.example.net", testTTL))
+		assert.NoError(t, err)
+		assert.Equal(t, UpsertUpdated, result.Action)
+		assert.Equal(t, 3, result.Record.ID)
+	})
+
+	t.Run("created", func(t *testing.T) {
+		var requests []map[string]interface{}
+		stubClient := newUpsertStubClient(t, existing, &requests)
+
+		result, err := stubClient.Records.Upsert(context.Background(), testDomain, NewRecordA("www", "192.0.2.3", testTTL))
+		assert.NoError(t, err)
+		assert.Equal(t, UpsertCreated, result.Action)
+		if assert.Len(t, requests, 1) {
+			assert.Equal(t, recordCreateURL, requests[0]["path"])
+		}
+	})
+}
@@ -0,0 +1,372 @@
+package cloudns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// ParseBIND parses a BIND zone file for the given zone into a list of records, e.g. for comparing exported zones with
+// their desired state. The SOA record is skipped, as it is managed separately by ClouDNS. Records of types which can
+// not be represented by cloudns-go cause an error.
+func ParseBIND(zoneName, content string) ([]Record, error) {
+	origin := dns.Fqdn(zoneName)
+	parser := dns.NewZoneParser(strings.NewReader(content), origin, "")
+	parser.SetDefaultTTL(3600)
+
+	var records []Record
+	for rr, ok := parser.Next(); ok; rr, ok = parser.Next() {
+		if rr.Header().Rrtype == dns.TypeSOA {
+			continue
+		}
+
+		record, err := FromRR(rr, zoneName)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	if err := parser.Err(); err != nil {
+		return nil, ErrIllegalArgument.wrap(err)
+	}
+
+	return records, nil
+}
+
+// PreviewTransfer performs a zone transfer (AXFR) of the given zone from the given authoritative nameserver and returns
+// the contained records, without importing them. This allows reviewing and validating the records which would be
+// imported by ImportTransfer beforehand. The server may be specified with or without port, defaulting to port 53.
+func (svc *RecordService) PreviewTransfer(ctx context.Context, zoneName, server string) ([]Record, error) {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+
+	msg := new(dns.Msg)
+	msg.SetAxfr(dns.Fqdn(zoneName))
+
+	transfer := new(dns.Transfer)
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout := time.Until(deadline)
+		transfer.DialTimeout, transfer.ReadTimeout = timeout, timeout
+	}
+
+	envelopes, err := transfer.In(msg, server)
+	if err != nil {
+		return nil, ErrDNSQuery.wrap(err)
+	}
+
+	var records []Record
+	for {
+		select {
+		case <-ctx.Done():
+			go func() {
+				for range envelopes {
+				}
+			}()
+			return nil, ctx.Err()
+		case envelope, ok := <-envelopes:
+			if !ok {
+				return records, nil
+			}
+			if envelope.Error != nil {
+				return nil, ErrDNSQuery.wrap(envelope.Error)
+			}
+
+			for _, rr := range envelope.RR {
+				if rr.Header().Rrtype == dns.TypeSOA {
+					continue
+				}
+
+				record, err := FromRR(rr, zoneName)
+				if err != nil {
+					return nil, err
+				}
+				records = append(records, record)
+			}
+		}
+	}
+}
+
+// Parse converts the exported zone file into a list of records, see ParseBIND. The name of the zone is taken from the
+// owner of the contained SOA record, which fails with ErrIllegalArgument if the export does not contain one.
+func (export RecordsExport) Parse() ([]Record, error) {
+	parser := dns.NewZoneParser(strings.NewReader(export.Zone), ".", "")
+	for rr, ok := parser.Next(); ok; rr, ok = parser.Next() {
+		if rr.Header().Rrtype == dns.TypeSOA {
+			return ParseBIND(strings.TrimSuffix(rr.Header().Name, "."), export.Zone)
+		}
+	}
+	if err := parser.Err(); err != nil {
+		return nil, ErrIllegalArgument.wrap(err)
+	}
+
+	return nil, ErrIllegalArgument.wrap(errors.New("exported zone does not contain a soa record"))
+}
+
+// Normalize converts the exported zone file into a normalized, stably ordered form which is suitable for storage in a
+// version control system. All names are fully qualified and lowercased, one record is printed per line with consistent
+// whitespace and records are sorted hierarchically by name, then by type and value, with the SOA record coming first.
+func (export RecordsExport) Normalize() (string, error) {
+	parser := dns.NewZoneParser(strings.NewReader(export.Zone), ".", "")
+
+	var rrs []dns.RR
+	for rr, ok := parser.Next(); ok; rr, ok = parser.Next() {
+		rr.Header().Name = dns.CanonicalName(rr.Header().Name)
+		rrs = append(rrs, rr)
+	}
+	if err := parser.Err(); err != nil {
+		return "", ErrIllegalArgument.wrap(err)
+	}
+
+	sort.SliceStable(rrs, func(i, j int) bool {
+		a, b := rrs[i].Header(), rrs[j].Header()
+		if (a.Rrtype == dns.TypeSOA) != (b.Rrtype == dns.TypeSOA) {
+			return a.Rrtype == dns.TypeSOA
+		}
+		if a.Name != b.Name {
+			return compareNamesHierarchically(a.Name, b.Name) < 0
+		}
+		if a.Rrtype != b.Rrtype {
+			return a.Rrtype < b.Rrtype
+		}
+		return rrs[i].String() < rrs[j].String()
+	})
+
+	var builder strings.Builder
+	for _, rr := range rrs {
+		builder.WriteString(rr.String())
+		builder.WriteByte('\n')
+	}
+
+	return builder.String(), nil
+}
+
+// compareNamesHierarchically compares two domain names label by label starting from the root, so that names within
+// the same subtree are grouped together and parents are sorted before their children
+func compareNamesHierarchically(a, b string) int {
+	labelsA, labelsB := dns.SplitDomainName(a), dns.SplitDomainName(b)
+	for i, j := len(labelsA)-1, len(labelsB)-1; i >= 0 && j >= 0; i, j = i-1, j-1 {
+		if cmp := strings.Compare(labelsA[i], labelsB[j]); cmp != 0 {
+			return cmp
+		}
+	}
+
+	return len(labelsA) - len(labelsB)
+}
+
+// FromRR converts a resource record of miekg/dns into a record for the given zone, which is the inverse of Record.ToRR.
+// Hostnames are made relative to the zone and stripped of their trailing dot, matching the representation used by the
+// ClouDNS API. Resource records outside of the zone or of types which can not be represented fail with
+// ErrIllegalArgument.
+func FromRR(rr dns.RR, zoneName string) (Record, error) {
+	header := rr.Header()
+	host, err := relativeHost(header.Name, zoneName)
+	if err != nil {
+		return Record{}, err
+	}
+
+	ttl := int(header.Ttl)
+	switch v := rr.(type) {
+	case *dns.A:
+		return NewRecordA(host, v.A.String(), ttl), nil
+	case *dns.AAAA:
+		return NewRecordAAAA(host, v.AAAA.String(), ttl), nil
+	case *dns.CNAME:
+		return NewRecordCNAME(host, trimDot(v.Target), ttl), nil
+	case *dns.NS:
+		return NewRecordNS(host, trimDot(v.Ns), ttl), nil
+	case *dns.PTR:
+		return NewRecordPTR(host, trimDot(v.Ptr), ttl), nil
+	case *dns.TXT:
+		return NewRecordTXT(host, strings.Join(v.Txt, ""), ttl), nil
+	case *dns.MX:
+		return NewRecordMX(host, v.Preference, trimDot(v.Mx), ttl), nil
+	case *dns.SRV:
+		return NewRecordSRV(host, v.Priority, v.Weight, v.Port, trimDot(v.Target), ttl), nil
+	case *dns.RP:
+		return NewRecordRP(host, trimDot(v.Mbox), trimDot(v.Txt), ttl), nil
+	case *dns.SSHFP:
+		return NewRecordSSHFP(host, v.Algorithm, v.Type, v.FingerPrint, ttl), nil
+	case *dns.CAA:
+		return NewRecordCAA(host, v.Flag, v.Tag, v.Value, ttl), nil
+	case *dns.NAPTR:
+		replacement := trimDot(v.Replacement)
+		if replacement == "." {
+			replacement = ""
+		}
+		return NewRecordNAPTR(host, v.Order, v.Preference, v.Flags, v.Service, v.Regexp, replacement, ttl), nil
+	case *dns.TLSA:
+		return NewRecordTLSA(host, v.Usage, v.Selector, v.MatchingType, v.Certificate, ttl), nil
+	case *dns.SMIMEA:
+		return NewRecordSMIMEA(host, v.Usage, v.Selector, v.MatchingType, v.Certificate, ttl), nil
+	case *dns.DNAME:
+		return NewRecordDNAME(host, trimDot(v.Target), ttl), nil
+	case *dns.DS:
+		return NewRecordDS(host, v.KeyTag, v.Algorithm, v.DigestType, v.Digest, ttl), nil
+	case *dns.CERT:
+		return NewRecordCERT(host, v.Type, v.KeyTag, v.Algorithm, v.Certificate, ttl), nil
+	case *dns.HINFO:
+		return NewRecordHINFO(host, v.Cpu, v.Os, ttl), nil
+	case *dns.OPENPGPKEY:
+		return NewRecordOPENPGPKEY(host, v.PublicKey, ttl), nil
+	case *dns.LOC:
+		return NewRecordLOC(host, locFromRR(v), ttl), nil
+	}
+
+	return Record{}, ErrIllegalArgument.wrap(fmt.Errorf("unsupported record type: %s", dns.TypeToString[header.Rrtype]))
+}
+
+// ToRR converts the record of the given zone into a resource record of miekg/dns, e.g. for serving or comparing records
+// with live DNS queries. Record types specific to ClouDNS like web redirects and ALIAS records fail with
+// ErrUnsupportedRecord, while invalid values fail with ErrIllegalArgument.
+func (rec Record) ToRR(zoneName string) (dns.RR, error) {
+	var rdata string
+	switch rec.RecordType {
+	case RecordTypeA, RecordTypeAAAA, RecordTypeOPENPGPKEY:
+		rdata = rec.Record
+	case RecordTypeCNAME, RecordTypeDNAME, RecordTypeNS, RecordTypePTR:
+		rdata = dns.Fqdn(rec.Record)
+	case RecordTypeMX:
+		rdata = fmt.Sprintf("%d %s", rec.Priority, dns.Fqdn(rec.Record))
+	case RecordTypeSRV:
+		rdata = fmt.Sprintf("%d %d %d %s", rec.Priority, rec.SRV.Weight, rec.SRV.Port, dns.Fqdn(rec.Record))
+	case RecordTypeTXT:
+		rdata = quoteCharacterStrings(rec.Record)
+	case RecordTypeRP:
+		rdata = fmt.Sprintf("%s %s", dns.Fqdn(rec.RP.Mail), dns.Fqdn(rec.RP.TXT))
+	case RecordTypeSSHFP:
+		rdata = fmt.Sprintf("%d %d %s", rec.SSHFP.Algorithm, rec.SSHFP.Type, rec.Record)
+	case RecordTypeCAA:
+		rdata = fmt.Sprintf("%d %s %s", rec.CAA.Flag, rec.CAA.Type, quoteCharacterStrings(rec.CAA.Value))
+	case RecordTypeNAPTR:
+		replacement := "."
+		if rec.NAPTR.Replacement != "" {
+			replacement = dns.Fqdn(rec.NAPTR.Replacement)
+		}
+		rdata = fmt.Sprintf("%d %d %s %s %s %s", rec.NAPTR.Order, rec.NAPTR.Preference,
+			quoteCharacterStrings(rec.NAPTR.Flags), quoteCharacterStrings(rec.NAPTR.Service),
+			quoteCharacterStrings(rec.NAPTR.Regexp), replacement)
+	case RecordTypeTLSA:
+		rdata = fmt.Sprintf("%d %d %d %s", rec.TLSA.Usage, rec.TLSA.Selector, rec.TLSA.MatchingType,
+			rec.Record)
+	case RecordTypeSMIMEA:
+		rdata = fmt.Sprintf("%d %d %d %s", rec.SMIMEA.Usage, rec.SMIMEA.Selector, rec.SMIMEA.MatchingType,
+			rec.Record)
+	case RecordTypeDS:
+		rdata = fmt.Sprintf("%d %d %d %s", rec.DS.KeyTag, rec.DS.Algorithm, rec.DS.DigestType, rec.Record)
+	case RecordTypeCERT:
+		rdata = fmt.Sprintf("%d %d %d %s", rec.CERT.Type, rec.CERT.KeyTag, rec.CERT.Algorithm, rec.Record)
+	case RecordTypeHINFO:
+		rdata = fmt.Sprintf("%s %s", quoteCharacterStrings(rec.HINFO.CPU), quoteCharacterStrings(rec.HINFO.OS))
+	case RecordTypeLOC:
+		loc := rec.LOC
+		rdata = fmt.Sprintf("%d %d %.3f %s %d %d %.3f %s %.2fm %.2fm %.2fm %.2fm",
+			loc.LatitudeDegrees, loc.LatitudeMinutes, loc.LatitudeSeconds, loc.LatitudeDirection,
+			loc.LongitudeDegrees, loc.LongitudeMinutes, loc.LongitudeSeconds, loc.LongitudeDirection,
+			loc.Altitude, loc.Size, loc.HorizontalPrecision, loc.VerticalPrecision)
+	default:
+		return nil, ErrUnsupportedRecord.wrap(fmt.Errorf("record type %s has no resource record", rec.RecordType))
+	}
+
+	rr, err := dns.NewRR(fmt.Sprintf("%s %d IN %s %s", recordFQDN(rec.Host, zoneName), rec.TTL,
+		rec.RecordType, rdata))
+	if err != nil {
+		return nil, ErrIllegalArgument.wrap(fmt.Errorf("invalid %s record %s: %w", rec.RecordType, rec.Host, err))
+	}
+
+	return rr, nil
+}
+
+// recordFQDN returns the fully qualified owner name of a host within the given zone
+func recordFQDN(host, zoneName string) string {
+	zoneName = normalizeHostname(zoneName)
+	if host == "" || host == "@" {
+		return dns.Fqdn(zoneName)
+	}
+
+	return dns.Fqdn(host + "." + zoneName)
+}
+
+// quoteCharacterStrings quotes the given value as one or more character strings of at most 255 bytes each
+func quoteCharacterStrings(value string) string {
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	chunks := make([]string, 0, len(value)/255+1)
+	for {
+		chunk := value
+		if len(chunk) > 255 {
+			chunk = chunk[:255]
+		}
+		value = value[len(chunk):]
+
+		chunks = append(chunks, `"`+escaper.Replace(chunk)+`"`)
+		if value == "" {
+			return strings.Join(chunks, " ")
+		}
+	}
+}
+
+// locFromRR decodes the wire format of a LOC resource record according to RFC1876, which stores coordinates in
+// thousandths of arc seconds offset by 2^31, the altitude in centimeters offset by 100000 meters and the size as well as
+// the precisions as powers of ten in centimeters
+func locFromRR(rr *dns.LOC) LOC {
+	var location LOC
+	location.LatitudeDegrees, location.LatitudeMinutes, location.LatitudeSeconds, location.LatitudeDirection =
+		decodeLOCCoordinate(rr.Latitude, "N", "S")
+	location.LongitudeDegrees, location.LongitudeMinutes, location.LongitudeSeconds, location.LongitudeDirection =
+		decodeLOCCoordinate(rr.Longitude, "E", "W")
+
+	location.Altitude = float64(rr.Altitude)/100 - 100000
+	location.Size = decodeLOCPrecision(rr.Size)
+	location.HorizontalPrecision = decodeLOCPrecision(rr.HorizPre)
+	location.VerticalPrecision = decodeLOCPrecision(rr.VertPre)
+
+	return location
+}
+
+func decodeLOCCoordinate(value uint32, positive, negative string) (degrees, minutes uint8, seconds float64, direction string) {
+	offset, direction := int64(value)-dns.LOC_EQUATOR, positive
+	if offset < 0 {
+		offset, direction = -offset, negative
+	}
+
+	degrees = uint8(offset / dns.LOC_DEGREES)
+	minutes = uint8(offset % dns.LOC_DEGREES / dns.LOC_HOURS)
+	seconds = float64(offset%dns.LOC_HOURS) / 1000
+	return
+}
+
+func decodeLOCPrecision(value uint8) float64 {
+	return float64(value>>4) * math.Pow10(int(value&0x0f)) / 100
+}
+
+// relativeHost converts a fully qualified name into a host relative to the given zone, using an empty string for the
+// zone apex. Names outside of the zone cause an error.
+func relativeHost(name, zoneName string) (string, error) {
+	name = normalizeHostname(name)
+	zoneName = normalizeHostname(zoneName)
+
+	switch {
+	case name == zoneName:
+		return "", nil
+	case strings.HasSuffix(name, "."+zoneName):
+		return strings.TrimSuffix(name, "."+zoneName), nil
+	}
+
+	return "", ErrIllegalArgument.wrap(fmt.Errorf("name %s is outside of zone %s", name, zoneName))
+}
+
+func trimDot(name string) string {
+	if name == "." {
+		return name
+	}
+
+	return strings.TrimSuffix(name, ".")
+}
@@ -0,0 +1,205 @@
+package cloudns
+
+import (
+	"context"
+	"errors"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseBIND(t *testing.T) {
+	// given
+	content := `$TTL 300
+@	IN	SOA	ns1.cloudns.net. support.cloudns.net. 2022122401 7200 1800 1209600 3600
+@	IN	NS	ns1.cloudns.net.
+@	3600	IN	A	192.0.2.1
+www	IN	CNAME	api-example.com.
+mail.api-example.com.	IN	MX	10 mx1.local.
+_sip._tls	IN	SRV	10 20 5061 sip.local.
+@	IN	TXT	"Hello " "World"
+@	IN	CAA	0 issue "ca.local"
+`
+
+	// when
+	records, err := ParseBIND(testDomain, content)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []Record{
+		NewRecordNS("", "ns1.cloudns.net", 300),
+		NewRecordA("", "192.0.2.1", 3600),
+		NewRecordCNAME("www", "api-example.com", 300),
+		NewRecordMX("mail", 10, "mx1.local", 300),
+		NewRecordSRV("_sip._tls", 10, 20, 5061, "sip.local", 300),
+		NewRecordTXT("", "Hello World", 300),
+		NewRecordCAA("", 0, "issue", "ca.local", 300),
+	}, records)
+}
+
+func TestParseBIND_Invalid(t *testing.T) {
+	test := func(content string) {
+		_, err := ParseBIND(testDomain, content)
+		assert.True(t, errors.Is(err, ErrIllegalArgument), "parsing [%s] should fail", content)
+	}
+
+	test("@ IN A not-an-ip")
+	test("other.local. IN A 192.0.2.1")
+	test("@ IN AFSDB 1 afs.local.")
+}
+
+func TestParseBIND_ExtendedTypes(t *testing.T) {
+	content := strings.Join([]string{
+		`@ 300 IN HINFO "amd64" "linux"`,
+		"old 300 IN DNAME new.api-example.com.",
+		"sub 300 IN DS 12345 13 2 0123456789ABCDEF",
+		"cert 300 IN CERT 1 12345 8 TUlJQkNnPT0=",
+	}, "\n")
+
+	records, err := ParseBIND(testDomain, content)
+	assert.NoError(t, err)
+	assert.Equal(t, []Record{
+		NewRecordHINFO("", "amd64", "linux", 300),
+		NewRecordDNAME("old", "new.api-example.com", 300),
+		NewRecordDS("sub", 12345, 13, 2, "0123456789ABCDEF", 300),
+		NewRecordCERT("cert", 1, 12345, 8, "TUlJQkNnPT0=", 300),
+	}, records)
+}
+
+func TestRecord_ToRR_FromRR(t *testing.T) {
+	records := []Record{
+		NewRecordA("www", "192.0.2.1", 3600),
+		NewRecordMX("", 10, "mail.api-example.com", 300),
+		NewRecordSRV("_sip._tls", 10, 20, 5061, "sip.local", 3600),
+		NewRecordCAA("", 128, "issue", "ca.local", 3600),
+		NewRecordNAPTR("", 100, 10, "S", "SIP+D2U", "", "_sip._udp.api-example.com", 3600),
+		NewRecordHINFO("host", "INTEL-386", "UNIX", 3600),
+		NewRecordDS("sub", 2371, 13, 2, "1F987CC6583E92DF0890718C42", 3600),
+		NewRecordLOC("office", LOC{
+			LatitudeDegrees: 52, LatitudeMinutes: 22, LatitudeSeconds: 23, LatitudeDirection: "N",
+			LongitudeDegrees: 4, LongitudeMinutes: 53, LongitudeSeconds: 32.5, LongitudeDirection: "W",
+			Altitude: -2, Size: 1, HorizontalPrecision: 10000, VerticalPrecision: 10,
+		}, 3600),
+	}
+
+	for _, record := range records {
+		rr, err := record.ToRR(testDomain)
+		if !assert.NoError(t, err, "%s record should be converted", record.RecordType) {
+			continue
+		}
+
+		converted, err := FromRR(rr, testDomain)
+		assert.NoError(t, err)
+		assert.Equal(t, record, converted, "%s record should survive round trip", record.RecordType)
+	}
+}
+
+func TestRecord_ToRR_Unsupported(t *testing.T) {
+	_, err := NewRecordALIAS("", "example.com", 3600).ToRR(testDomain)
+	assert.ErrorIs(t, err, ErrUnsupportedRecord)
+
+	_, err = NewRecordA("", "not-an-ip", 3600).ToRR(testDomain)
+	assert.ErrorIs(t, err, ErrIllegalArgument)
+}
+
+func TestRecordsExport_Parse(t *testing.T) {
+	// given
+	export := RecordsExport{Zone: "$ORIGIN api-example.com.\n" +
+		"@\t3600\tIN\tSOA\tns1.api-example.com. admin.api-example.com. 2022122491 7200 1800 1209600 3600\n" +
+		"@\t3600\tIN\tNS\tdns1.cloudns.net.\n" +
+		"WWW 3600 IN A 192.0.2.2\n"}
+
+	// when
+	records, err := export.Parse()
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []Record{
+		NewRecordNS("", "dns1.cloudns.net", 3600),
+		NewRecordA("www", "192.0.2.2", 3600),
+	}, records)
+}
+
+func TestRecordsExport_Parse_MissingSOA(t *testing.T) {
+	_, err := RecordsExport{Zone: "$ORIGIN api-example.com.\n@ 3600 IN A 192.0.2.1\n"}.Parse()
+	assert.ErrorIs(t, err, ErrIllegalArgument)
+}
+
+func TestRecordsExport_Normalize(t *testing.T) {
+	// given
+	export := RecordsExport{Zone: "$ORIGIN api-example.com.\n" +
+		"www  3600 IN A 192.0.2.2\n" +
+		"@\t3600\tIN\tNS\tdns2.cloudns.net.\n" +
+		"@\t3600\tIN\tSOA\tns1.api-example.com. admin.api-example.com. 2022122491 7200 1800 1209600 3600\n" +
+		"a.WWW 3600 IN A 192.0.2.3\n" +
+		"@\t3600\tIN\tNS\tdns1.cloudns.net.\n" +
+		"@ 3600 IN A 192.0.2.1\n"}
+
+	// when
+	normalized, err := export.Normalize()
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "api-example.com.\t3600\tIN\tSOA\tns1.api-example.com. admin.api-example.com. 2022122491 7200 1800 1209600 3600\n"+
+		"api-example.com.\t3600\tIN\tA\t192.0.2.1\n"+
+		"api-example.com.\t3600\tIN\tNS\tdns1.cloudns.net.\n"+
+		"api-example.com.\t3600\tIN\tNS\tdns2.cloudns.net.\n"+
+		"www.api-example.com.\t3600\tIN\tA\t192.0.2.2\n"+
+		"a.www.api-example.com.\t3600\tIN\tA\t192.0.2.3\n", normalized)
+}
+
+// startTestDNSServer starts a local DNS server on a random TCP port serving the given handler and returns its address
+func startTestDNSServer(t *testing.T, handler dns.HandlerFunc) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not listen for test dns server: %v", err)
+	}
+
+	server := &dns.Server{Listener: listener, Handler: handler}
+	go func() {
+		_ = server.ActivateAndServe()
+	}()
+	t.Cleanup(func() {
+		_ = server.Shutdown()
+	})
+
+	return listener.Addr().String()
+}
+
+func TestRecordService_PreviewTransfer(t *testing.T) {
+	// given
+	address := startTestDNSServer(t, func(w dns.ResponseWriter, req *dns.Msg) {
+		soa, _ := dns.NewRR("api-example.com. 3600 IN SOA ns1.api-example.com. admin.api-example.com. 1 7200 1800 1209600 3600")
+		a, _ := dns.NewRR("www.api-example.com. 300 IN A 192.0.2.1")
+		mx, _ := dns.NewRR("api-example.com. 300 IN MX 10 mx1.local.")
+
+		var wg sync.WaitGroup
+		envelopes := make(chan *dns.Envelope)
+		transfer := new(dns.Transfer)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = transfer.Out(w, req, envelopes)
+		}()
+		envelopes <- &dns.Envelope{RR: []dns.RR{soa, a, mx, soa}}
+		close(envelopes)
+		wg.Wait()
+		w.Hijack()
+	})
+
+	client, err := New()
+	assert.NoError(t, err)
+	transferCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// when
+	records, err := client.Records.PreviewTransfer(transferCtx, testDomain, address)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []Record{NewRecordA("www", "192.0.2.1", 300), NewRecordMX("", 10, "mx1.local", 300)}, records)
+}
@@ -0,0 +1,94 @@
+package cloudns
+
+import (
+	"context"
+)
+
+// SecondaryFreshness represents the synchronization state of a slave zone, comparing the SOA serial served by the
+// ClouDNS nameservers with the SOA serial served by the configured master servers
+type SecondaryFreshness struct {
+	Zone string
+	// MasterSerial contains the most recent SOA serial served by any of the master servers
+	MasterSerial uint32
+	// Serials contains the SOA serial served by each ClouDNS nameserver, indexed by the nameserver name
+	Serials map[string]uint32
+	// Stale contains the names of all ClouDNS nameservers serving an older SOA serial than the master servers
+	Stale []string
+	// Skipped contains the names of all ClouDNS nameservers which were not queried as they have no address
+	Skipped []string
+	// Errors contains all errors which occurred while querying master servers or nameservers, indexed by server
+	Errors map[string]error
+}
+
+// CheckSecondaryFreshness compares the SOA serial of the given slave zones served by the ClouDNS nameservers with the
+// serial served by their master servers, which reveals slave zones that have fallen behind their masters. The SOA
+// serials are queried directly via DNS, so master servers must allow SOA queries from the host running cloudns-go.
+func (svc *ZoneService) CheckSecondaryFreshness(ctx context.Context, zoneNames ...string) ([]SecondaryFreshness, error) {
+	results := make([]SecondaryFreshness, 0, len(zoneNames))
+	for _, zoneName := range zoneNames {
+		masters, err := svc.MasterServers(ctx, zoneName)
+		if err != nil {
+			return results, err
+		}
+
+		nameservers, err := svc.GetUpdateStatus(ctx, zoneName)
+		if err != nil {
+			return results, err
+		}
+
+		results = append(results, svc.checkSecondaryFreshness(ctx, zoneName, masters, nameservers))
+	}
+
+	return results, nil
+}
+
+func (svc *ZoneService) checkSecondaryFreshness(ctx context.Context, zoneName string, masters []MasterServer, nameservers []ZoneUpdateStatus) SecondaryFreshness {
+	result := SecondaryFreshness{
+		Zone:    zoneName,
+		Serials: make(map[string]uint32),
+		Errors:  make(map[string]error),
+	}
+
+	hasMasterSerial := false
+	for _, master := range masters {
+		serial, err := svc.api.querySOASerial(ctx, master.IP.String(), zoneName)
+		if err != nil {
+			result.Errors[master.IP.String()] = err
+			continue
+		}
+
+		if !hasMasterSerial || serialIsBehind(result.MasterSerial, serial) {
+			result.MasterSerial = serial
+			hasMasterSerial = true
+		}
+	}
+
+	for _, nameserver := range nameservers {
+		address := nameserver.IPv4
+		if address == "" {
+			address = nameserver.IPv6
+		}
+		if address == "" {
+			result.Skipped = append(result.Skipped, nameserver.Server)
+			continue
+		}
+
+		serial, err := svc.api.querySOASerial(ctx, address, zoneName)
+		if err != nil {
+			result.Errors[nameserver.Server] = err
+			continue
+		}
+
+		result.Serials[nameserver.Server] = serial
+		if hasMasterSerial && serialIsBehind(serial, result.MasterSerial) {
+			result.Stale = append(result.Stale, nameserver.Server)
+		}
+	}
+
+	return result
+}
+
+// IsStale returns true if any ClouDNS nameserver serves an older SOA serial than the master servers
+func (freshness SecondaryFreshness) IsStale() bool {
+	return len(freshness.Stale) > 0
+}
@@ -0,0 +1,36 @@
+package cloudns
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"testing"
+)
+
+func TestCheckSecondaryFreshness(t *testing.T) {
+	// given
+	port := startTestUDPDNSServers(t, newTestSOAHandler(map[string]uint32{
+		"127.0.0.1": 2022122402,
+		"127.0.0.2": 2022122402,
+		"127.0.0.3": 2022122401,
+	}), "127.0.0.1", "127.0.0.2", "127.0.0.3")
+	client, _ := New(DNSPort(port))
+
+	masters := []MasterServer{{ID: 1, IP: net.ParseIP("127.0.0.1")}}
+	nameservers := []ZoneUpdateStatus{
+		{Server: "ns1.cloudns.net", IPv4: "127.0.0.2"},
+		{Server: "ns2.cloudns.net", IPv4: "127.0.0.3"},
+		{Server: "ns3.cloudns.net"},
+	}
+
+	// when
+	result := client.Zones.checkSecondaryFreshness(context.Background(), testDomain, masters, nameservers)
+
+	// then
+	assert.Empty(t, result.Errors)
+	assert.True(t, result.IsStale(), "zone should be stale")
+	assert.Equal(t, uint32(2022122402), result.MasterSerial)
+	assert.Equal(t, map[string]uint32{"ns1.cloudns.net": 2022122402, "ns2.cloudns.net": 2022122401}, result.Serials)
+	assert.Equal(t, []string{"ns2.cloudns.net"}, result.Stale)
+	assert.Equal(t, []string{"ns3.cloudns.net"}, result.Skipped, "nameservers without address should be skipped")
+}
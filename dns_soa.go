@@ -0,0 +1,60 @@
+package cloudns
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AdminMailToDNSName converts the admin mail of a SOA record from email style, e.g. `hostmaster@example.com`, into DNS
+// style as used within zone files, e.g. `hostmaster.example.com.`. Dots within the local part are escaped.
+func AdminMailToDNSName(mail string) (string, error) {
+	index := strings.LastIndex(mail, "@")
+	if index <= 0 || index == len(mail)-1 || strings.Count(mail, "@") > 1 {
+		return "", ErrIllegalArgument.wrap(fmt.Errorf("invalid admin mail: %s", mail))
+	}
+
+	local := strings.ReplaceAll(mail[:index], ".", `\.`)
+	domain := strings.TrimSuffix(mail[index+1:], ".")
+	if domain == "" || strings.Contains(domain, "..") {
+		return "", ErrIllegalArgument.wrap(fmt.Errorf("invalid admin mail: %s", mail))
+	}
+
+	return local + "." + domain + ".", nil
+}
+
+// AdminMailFromDNSName converts the admin mail of a SOA record from DNS style as used within zone files, e.g.
+// `hostmaster.example.com.`, into email style, e.g. `hostmaster@example.com`. The first unescaped dot separates the
+// local part from the domain.
+func AdminMailFromDNSName(name string) (string, error) {
+	name = strings.TrimSuffix(name, ".")
+
+	for index := 0; index < len(name); index++ {
+		switch name[index] {
+		case '\\':
+			index++
+		case '.':
+			local := strings.ReplaceAll(name[:index], `\.`, ".")
+			domain := name[index+1:]
+			if local == "" || domain == "" || strings.Contains(domain, "..") {
+				return "", ErrIllegalArgument.wrap(fmt.Errorf("invalid admin mail: %s", name))
+			}
+			return local + "@" + domain, nil
+		}
+	}
+
+	return "", ErrIllegalArgument.wrap(fmt.Errorf("invalid admin mail: %s", name))
+}
+
+// NormalizeAdminMail converts the admin mail of a SOA record in either email or DNS style into email style as expected
+// by the ClouDNS API
+func NormalizeAdminMail(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if strings.Contains(value, "@") {
+		if _, err := AdminMailToDNSName(value); err != nil {
+			return "", err
+		}
+		return strings.TrimSuffix(value, "."), nil
+	}
+
+	return AdminMailFromDNSName(value)
+}
@@ -0,0 +1,47 @@
+package cloudns
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAdminMailToDNSName(t *testing.T) {
+	name, err := AdminMailToDNSName("john.doe@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, `john\.doe.example.com.`, name)
+
+	for _, mail := range []string{"", "example.com", "@example.com", "hostmaster@", "a@b@example.com", "a@example..com"} {
+		_, err = AdminMailToDNSName(mail)
+		assert.ErrorIs(t, err, ErrIllegalArgument, "%q should be invalid", mail)
+	}
+}
+
+func TestAdminMailFromDNSName(t *testing.T) {
+	mail, err := AdminMailFromDNSName(`john\.doe.example.com.`)
+	assert.NoError(t, err)
+	assert.Equal(t, "john.doe@example.com", mail)
+
+	mail, err = AdminMailFromDNSName("hostmaster.example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "hostmaster@example.com", mail)
+
+	for _, name := range []string{"", "hostmaster", ".example.com", `hostmaster\.example`} {
+		_, err = AdminMailFromDNSName(name)
+		assert.ErrorIs(t, err, ErrIllegalArgument, "%q should be invalid", name)
+	}
+}
+
+func TestNormalizeAdminMail(t *testing.T) {
+	tests := map[string]string{
+		"hostmaster@example.com":   "hostmaster@example.com",
+		" hostmaster@example.com.": "hostmaster@example.com",
+		"hostmaster.example.com.":  "hostmaster@example.com",
+		`john\.doe.example.com.`:   "john.doe@example.com",
+	}
+
+	for value, expected := range tests {
+		mail, err := NormalizeAdminMail(value)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, mail, "unexpected result for %q", value)
+	}
+}
@@ -0,0 +1,60 @@
+package cloudns
+
+import (
+	"context"
+)
+
+// SplitHorizonView describes a single view of a split-horizon setup, e.g. the internal or external view of a domain
+type SplitHorizonView struct {
+	// Zone is the name of the ClouDNS zone serving this view
+	Zone string
+	// Records is the record service used for managing the zone, which allows placing views in different accounts or
+	// sub-users. It defaults to the record service SyncSplitHorizon has been called on.
+	Records *RecordService
+	// Overrides replace all shared records with the same host and type within this view
+	Overrides []Record
+}
+
+// SplitHorizonPlan contains the sync plans of both views of a split-horizon setup
+type SplitHorizonPlan struct {
+	Internal SyncPlan
+	External SyncPlan
+}
+
+// SyncSplitHorizon maintains an internal and external view of the same domain as two ClouDNS zones. Both zones are
+// synced to contain the shared records, except for record sets which are overridden by the respective view. The
+// external view is only synced after the internal view has been synced successfully.
+func (svc *RecordService) SyncSplitHorizon(ctx context.Context, shared []Record, internal, external SplitHorizonView, options SyncOptions) (plan SplitHorizonPlan, err error) {
+	if plan.Internal, err = svc.syncView(ctx, shared, internal, options); err != nil {
+		return
+	}
+
+	plan.External, err = svc.syncView(ctx, shared, external, options)
+	return
+}
+
+func (svc *RecordService) syncView(ctx context.Context, shared []Record, view SplitHorizonView, options SyncOptions) (SyncPlan, error) {
+	records := view.Records
+	if records == nil {
+		records = svc
+	}
+
+	return records.Sync(ctx, view.Zone, mergeViewRecords(shared, view.Overrides), options)
+}
+
+// mergeViewRecords returns the shared records with all record sets replaced which are overridden by the view
+func mergeViewRecords(shared, overrides []Record) []Record {
+	overridden := make(map[string]bool)
+	for _, record := range overrides {
+		overridden[recordSetKey(record.Host, record.RecordType)] = true
+	}
+
+	var results []Record
+	for _, record := range shared {
+		if !overridden[recordSetKey(record.Host, record.RecordType)] {
+			results = append(results, record)
+		}
+	}
+
+	return append(results, overrides...)
+}
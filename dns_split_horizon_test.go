@@ -0,0 +1,53 @@
+package cloudns
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestMergeViewRecords(t *testing.T) {
+	shared := []Record{
+		NewRecordA("www", "198.51.100.1", testTTL),
+		NewRecordA("www", "198.51.100.2", testTTL),
+		NewRecordMX("", 10, "mail.api-example.com", testTTL),
+	}
+	overrides := []Record{NewRecordA("www", "10.0.0.1", testTTL)}
+
+	assert.Equal(t, []Record{
+		NewRecordMX("", 10, "mail.api-example.com", testTTL),
+		NewRecordA("www", "10.0.0.1", testTTL),
+	}, mergeViewRecords(shared, overrides))
+}
+
+func TestRecordService_SyncSplitHorizon(t *testing.T) {
+	// given
+	var creates []string
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		var params map[string]interface{}
+		_ = json.NewDecoder(req.Body).Decode(&params)
+
+		switch req.URL.Path {
+		case recordListURL:
+			return `[]`
+		case recordCreateURL:
+			creates = append(creates, fmt.Sprintf("%s %s", params["domain-name"], params["record"]))
+		}
+		return `{"status":"Success","statusDescription":"OK"}`
+	})
+	shared := []Record{NewRecordA("www", "198.51.100.1", testTTL)}
+	internal := SplitHorizonView{Zone: "internal.example", Overrides: []Record{NewRecordA("www", "10.0.0.1", testTTL)}}
+	external := SplitHorizonView{Zone: "external.example"}
+
+	// when
+	plan, err := stubClient.Records.SyncSplitHorizon(context.Background(), shared, internal, external, SyncOptions{})
+
+	// then
+	assert.NoError(t, err)
+	assert.Len(t, plan.Internal.Applied, 1)
+	assert.Len(t, plan.External.Applied, 1)
+	assert.Equal(t, []string{"internal.example 10.0.0.1", "external.example 198.51.100.1"}, creates)
+}
@@ -0,0 +1,120 @@
+package cloudns
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const zoneStatisticsHourlyURL = "/dns/statistics-hourly.json"
+const zoneStatisticsDailyURL = "/dns/statistics-daily.json"
+const zoneStatisticsMonthlyURL = "/dns/statistics-monthly.json"
+const zoneStatisticsYearlyURL = "/dns/statistics-yearly.json"
+const zoneStatisticsLast30DaysURL = "/dns/statistics-last-30-days.json"
+
+// StatisticsPeriod is an enumeration of all supported periods for zone query statistics
+type StatisticsPeriod int
+
+// Enumeration values for StatisticsPeriod
+const (
+	// StatisticsHourly returns the queries per hour for the day of the given time
+	StatisticsHourly StatisticsPeriod = iota
+	// StatisticsDaily returns the queries per day for the month of the given time
+	StatisticsDaily
+	// StatisticsMonthly returns the queries per month for the year of the given time
+	StatisticsMonthly
+	// StatisticsYearly returns the queries per year, ignoring the given time
+	StatisticsYearly
+	// StatisticsLast30Days returns the queries per day for the last 30 days, ignoring the given time
+	StatisticsLast30Days
+)
+
+// StatisticsEntry represents the amount of queries for a zone within a single time slot, which starts at the given
+// time and spans an hour, day, month or year depending on the requested period
+type StatisticsEntry struct {
+	Time    time.Time
+	Queries int
+}
+
+// Statistics returns the amount of DNS queries for the given zone within the requested period, which is determined by
+// the given time in UTC. The entries are sorted by time in ascending order.
+func (svc *ZoneService) Statistics(ctx context.Context, zoneName string, period StatisticsPeriod, at time.Time) ([]StatisticsEntry, error) {
+	at = at.UTC()
+	params := HTTPParams{"domain-name": zoneName}
+
+	var endpoint string
+	switch period {
+	case StatisticsHourly:
+		endpoint = zoneStatisticsHourlyURL
+		params["year"], params["month"], params["day"] = at.Year(), int(at.Month()), at.Day()
+	case StatisticsDaily:
+		endpoint = zoneStatisticsDailyURL
+		params["year"], params["month"] = at.Year(), int(at.Month())
+	case StatisticsMonthly:
+		endpoint = zoneStatisticsMonthlyURL
+		params["year"] = at.Year()
+	case StatisticsYearly:
+		endpoint = zoneStatisticsYearlyURL
+	case StatisticsLast30Days:
+		endpoint = zoneStatisticsLast30DaysURL
+	default:
+		return nil, ErrIllegalArgument.wrap(errors.New("invalid statistics period"))
+	}
+
+	var result map[string]json.Number
+	if err := svc.api.request(ctx, "POST", endpoint, params, nil, &result); err != nil {
+		return nil, err
+	}
+
+	return parseStatistics(result, period, at)
+}
+
+// parseStatistics converts the statistics returned by ClouDNS, which are indexed by the hour, day, month, year or date
+// depending on the period, into entries sorted by time
+func parseStatistics(result map[string]json.Number, period StatisticsPeriod, at time.Time) ([]StatisticsEntry, error) {
+	entries := make([]StatisticsEntry, 0, len(result))
+	for key, value := range result {
+		queries, err := strconv.Atoi(value.String())
+		if err != nil {
+			return nil, ErrHTTPRequest.wrap(fmt.Errorf("invalid query count for %s: %w", key, err))
+		}
+
+		var slot time.Time
+		if period == StatisticsLast30Days {
+			slot, err = time.Parse("2006-01-02", key)
+		} else {
+			var index int
+			if index, err = strconv.Atoi(key); err == nil {
+				slot = statisticsSlot(period, at, index)
+			}
+		}
+		if err != nil {
+			return nil, ErrHTTPRequest.wrap(fmt.Errorf("invalid statistics key %s: %w", key, err))
+		}
+
+		entries = append(entries, StatisticsEntry{Time: slot, Queries: queries})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Time.Before(entries[j].Time)
+	})
+
+	return entries, nil
+}
+
+func statisticsSlot(period StatisticsPeriod, at time.Time, index int) time.Time {
+	switch period {
+	case StatisticsHourly:
+		return time.Date(at.Year(), at.Month(), at.Day(), index, 0, 0, 0, time.UTC)
+	case StatisticsDaily:
+		return time.Date(at.Year(), at.Month(), index, 0, 0, 0, 0, time.UTC)
+	case StatisticsMonthly:
+		return time.Date(at.Year(), time.Month(index), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Date(index, time.January, 1, 0, 0, 0, 0, time.UTC)
+	}
+}
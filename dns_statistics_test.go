@@ -0,0 +1,61 @@
+package cloudns
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestZoneService_Statistics(t *testing.T) {
+	// given
+	var params map[string]interface{}
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		_ = json.NewDecoder(req.Body).Decode(&params)
+		return `{"1":"10","0":5,"23":"0"}`
+	})
+	at := time.Date(2022, 12, 24, 15, 0, 0, 0, time.UTC)
+
+	// when
+	entries, err := stubClient.Zones.Statistics(context.Background(), testDomain, StatisticsHourly, at)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, float64(2022), params["year"])
+	assert.Equal(t, float64(12), params["month"])
+	assert.Equal(t, float64(24), params["day"])
+	assert.Equal(t, []StatisticsEntry{
+		{Time: time.Date(2022, 12, 24, 0, 0, 0, 0, time.UTC), Queries: 5},
+		{Time: time.Date(2022, 12, 24, 1, 0, 0, 0, time.UTC), Queries: 10},
+		{Time: time.Date(2022, 12, 24, 23, 0, 0, 0, time.UTC), Queries: 0},
+	}, entries)
+}
+
+func TestParseStatistics(t *testing.T) {
+	at := time.Date(2022, 12, 24, 0, 0, 0, 0, time.UTC)
+
+	monthly, err := parseStatistics(map[string]json.Number{"2": "7"}, StatisticsMonthly, at)
+	assert.NoError(t, err)
+	assert.Equal(t, []StatisticsEntry{{Time: time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC), Queries: 7}}, monthly)
+
+	yearly, err := parseStatistics(map[string]json.Number{"2021": "7"}, StatisticsYearly, at)
+	assert.NoError(t, err)
+	assert.Equal(t, 2021, yearly[0].Time.Year())
+
+	last30Days, err := parseStatistics(map[string]json.Number{"2022-12-01": "3"}, StatisticsLast30Days, at)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC), last30Days[0].Time)
+
+	_, err = parseStatistics(map[string]json.Number{"invalid": "3"}, StatisticsDaily, at)
+	assert.ErrorIs(t, err, ErrHTTPRequest)
+}
+
+func TestZoneService_Statistics_InvalidPeriod(t *testing.T) {
+	client, err := New()
+	assert.NoError(t, err)
+
+	_, err = client.Zones.Statistics(context.Background(), testDomain, StatisticsPeriod(42), time.Now())
+	assert.ErrorIs(t, err, ErrIllegalArgument)
+}
@@ -0,0 +1,97 @@
+package cloudns
+
+import (
+	"context"
+)
+
+// SyncOptions specifies how RecordService.Sync reconciles a zone with the desired records
+type SyncOptions struct {
+	// DryRun only computes the plan without applying it
+	DryRun bool
+	// ManageApexNS includes the apex NS records in the reconciliation, which are ignored by default as they are usually
+	// provided by ClouDNS and removing them breaks the delegation of the zone
+	ManageApexNS bool
+	// Ignore excludes existing and desired records from the reconciliation if it returns true, e.g. for protecting
+	// records which are managed by other tools
+	Ignore func(record Record) bool
+}
+
+// SyncPlan represents the changes required for reconciling a zone with the desired records
+type SyncPlan struct {
+	// Changes contains all changes of the plan in the order they are applied
+	Changes []RecordChange
+	// Applied contains all changes which have been applied successfully, which is empty for a dry run
+	Applied []RecordChange
+}
+
+// Sync reconciles the records of a zone with the given desired records, so that the zone contains exactly the desired
+// records afterwards, apart from ignored ones. Existing records are reused and updated where possible, and all changes
+// are applied in the order of CoalesceChanges. The returned plan contains all changes even if applying them failed.
+func (svc *RecordService) Sync(ctx context.Context, zoneName string, desired []Record, options SyncOptions) (plan SyncPlan, err error) {
+	existing, err := svc.List(ctx, zoneName)
+	if err != nil {
+		return
+	}
+
+	if len(svc.api.recordNormalizers) > 0 {
+		normalized := make([]Record, len(desired))
+		for i, record := range desired {
+			normalized[i] = svc.api.normalizeRecord(record)
+		}
+		desired = normalized
+	}
+
+	if plan.Changes, err = PlanSync(existing, desired, options); err != nil || options.DryRun {
+		return
+	}
+
+	plan.Applied, err = svc.ApplyChanges(ctx, zoneName, plan.Changes)
+	return
+}
+
+// PlanSync computes the changes required for turning the existing records into the desired records, without contacting
+// the API, which allows consumers to render or review plans before applying them.
+func PlanSync(existing RecordMap, desired []Record, options SyncOptions) ([]RecordChange, error) {
+	isIgnored := func(record Record) bool {
+		if !options.ManageApexNS && record.RecordType == RecordTypeNS && normalizeRecordHost(record.Host) == "" {
+			return true
+		}
+		return options.Ignore != nil && options.Ignore(record)
+	}
+
+	managed := make(RecordMap)
+	for id, record := range existing {
+		if !isIgnored(record) {
+			managed[id] = record
+		}
+	}
+
+	var managedDesired []Record
+	desiredSets := make(map[string]bool)
+	for _, record := range desired {
+		if isIgnored(record) {
+			continue
+		}
+		managedDesired = append(managedDesired, record)
+		desiredSets[recordSetKey(record.Host, record.RecordType)] = true
+	}
+
+	plan, err := diffRecords(managed, managedDesired, ImportConflictOverwrite)
+	if err != nil {
+		return nil, err
+	}
+
+	// Remove all surplus records of desired record sets as well as all records of record sets which are not desired at
+	// all, as the import plan never deletes records which do not conflict with imported ones
+	plan.delete = append(plan.delete, plan.surplus...)
+	for _, record := range managed.SortedSlice() {
+		if !desiredSets[recordSetKey(record.Host, record.RecordType)] {
+			plan.delete = append(plan.delete, record)
+		}
+	}
+	if err := plan.checkCNAMEConflicts(existing); err != nil {
+		return nil, err
+	}
+
+	return CoalesceChanges(plan.changes()), nil
+}
@@ -0,0 +1,105 @@
+package cloudns
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestPlanSync(t *testing.T) {
+	// given
+	existing := buildRecordMap(
+		NewRecordNS("", "ns1.cloudns.net", testTTL),
+		NewRecordA("www", "192.0.2.1", testTTL),
+		NewRecordA("www", "192.0.2.2", testTTL),
+		NewRecordA("old", "192.0.2.3", testTTL),
+		NewRecordTXT("manual", "keep me", testTTL),
+	)
+	desired := []Record{
+		NewRecordA("www", "192.0.2.1", testTTL),
+		NewRecordA("www", "192.0.2.20", testTTL),
+		NewRecordCNAME("blog", "www.api-example.com", testTTL),
+	}
+	options := SyncOptions{Ignore: func(record Record) bool {
+		return record.Host == "manual"
+	}}
+
+	// when
+	changes, err := PlanSync(existing, desired, options)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []RecordChange{
+		{Type: RecordChangeCreate, Record: NewRecordCNAME("blog", "www.api-example.com", testTTL)},
+		{Type: RecordChangeUpdate, ID: 3, Record: NewRecordA("www", "192.0.2.20", testTTL)},
+		{Type: RecordChangeDelete, ID: 4, Record: existing[4]},
+	}, changes, "apex ns and ignored records should be kept")
+}
+
+func TestPlanSync_InSync(t *testing.T) {
+	existing := buildRecordMap(NewRecordA("www", "192.0.2.1", testTTL))
+
+	changes, err := PlanSync(existing, []Record{NewRecordA("www.", "192.0.2.1", testTTL)}, SyncOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestPlanSync_Surplus(t *testing.T) {
+	// given
+	existing := buildRecordMap(
+		NewRecordA("www", "192.0.2.1", testTTL),
+		NewRecordA("www", "192.0.2.2", testTTL),
+	)
+
+	// when
+	changes, err := PlanSync(existing, []Record{NewRecordA("www", "192.0.2.1", testTTL)}, SyncOptions{})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []RecordChange{
+		{Type: RecordChangeDelete, ID: 2, Record: existing[2]},
+	}, changes, "surplus records of desired record sets should be deleted")
+}
+
+func TestPlanSync_ReplaceWithCNAME(t *testing.T) {
+	// given
+	existing := buildRecordMap(NewRecordA("www", "192.0.2.1", testTTL))
+
+	// when
+	changes, err := PlanSync(existing, []Record{NewRecordCNAME("www", "target.example.net", testTTL)}, SyncOptions{})
+	_, conflictErr := PlanSync(existing, []Record{
+		NewRecordA("www", "192.0.2.1", testTTL),
+		NewRecordCNAME("www", "target.example.net", testTTL),
+	}, SyncOptions{})
+
+	// then
+	assert.NoError(t, err, "records removed by the sync should not conflict")
+	assert.Len(t, changes, 2)
+	assert.ErrorIs(t, conflictErr, ErrImportConflict)
+}
+
+func TestRecordService_Sync(t *testing.T) {
+	// given
+	var requests []string
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		requests = append(requests, req.URL.Path)
+		if req.URL.Path == recordListURL {
+			return `{"1":{"id":"1","host":"old","record":"192.0.2.1","type":"A","ttl":"3600","status":1}}`
+		}
+		return `{"status":"Success","statusDescription":"OK"}`
+	})
+	desired := []Record{NewRecordA("new", "192.0.2.1", testTTL)}
+
+	// when
+	dryPlan, dryErr := stubClient.Records.Sync(context.Background(), testDomain, desired, SyncOptions{DryRun: true})
+	plan, err := stubClient.Records.Sync(context.Background(), testDomain, desired, SyncOptions{})
+
+	// then
+	assert.NoError(t, dryErr)
+	assert.Empty(t, dryPlan.Applied)
+	assert.NoError(t, err)
+	assert.Equal(t, dryPlan.Changes, plan.Changes)
+	assert.Equal(t, plan.Changes, plan.Applied)
+	assert.Equal(t, []string{recordListURL, recordListURL, recordCreateURL, recordDeleteURL}, requests, "create should happen before delete")
+}
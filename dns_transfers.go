@@ -0,0 +1,51 @@
+package cloudns
+
+import (
+	"context"
+	"net"
+	"sort"
+)
+
+const transferListURL = "/dns/axfr-list.json"
+const transferAddURL = "/dns/axfr-add.json"
+const transferRemoveURL = "/dns/axfr-remove.json"
+
+// TransferServer represents an IP address which is allowed to transfer a zone using AXFR
+type TransferServer struct {
+	ID     int    `json:"id,string"`
+	Server net.IP `json:"server"`
+}
+
+// TransferServers returns all IP addresses which are allowed to transfer the given zone using AXFR, sorted by their ID
+func (svc *ZoneService) TransferServers(ctx context.Context, zoneName string) ([]TransferServer, error) {
+	var result map[string]TransferServer
+
+	params := HTTPParams{"domain-name": zoneName}
+	if err := svc.api.request(ctx, "POST", transferListURL, params, nil, &result); err != nil {
+		return nil, err
+	}
+
+	servers := make([]TransferServer, 0, len(result))
+	for _, server := range result {
+		servers = append(servers, server)
+	}
+	sort.Slice(servers, func(i, j int) bool {
+		return servers[i].ID < servers[j].ID
+	})
+
+	return servers, nil
+}
+
+// AddTransferServer allows the given IP address to transfer the zone using AXFR
+func (svc *ZoneService) AddTransferServer(ctx context.Context, zoneName string, ip net.IP) (result StatusResult, err error) {
+	params := HTTPParams{"domain-name": zoneName, "ip": ip.String()}
+	err = svc.api.request(ctx, "POST", transferAddURL, params, nil, &result)
+	return
+}
+
+// RemoveTransferServer revokes the permission of the transfer server with the given ID to transfer the zone using AXFR
+func (svc *ZoneService) RemoveTransferServer(ctx context.Context, zoneName string, serverID int) (result StatusResult, err error) {
+	params := HTTPParams{"domain-name": zoneName, "id": serverID}
+	err = svc.api.request(ctx, "POST", transferRemoveURL, params, nil, &result)
+	return
+}
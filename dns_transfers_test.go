@@ -0,0 +1,53 @@
+package cloudns
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"net/http"
+	"testing"
+)
+
+func TestZoneService_TransferServers(t *testing.T) {
+	// given
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `{"12":{"id":"12","server":"2001:db8::53"},"3":{"id":"3","server":"192.0.2.53"}}`
+	})
+
+	// when
+	servers, err := stubClient.Zones.TransferServers(context.Background(), testDomain)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []TransferServer{
+		{ID: 3, Server: net.ParseIP("192.0.2.53")},
+		{ID: 12, Server: net.ParseIP("2001:db8::53")},
+	}, servers)
+}
+
+func TestZoneService_TransferServers_Empty(t *testing.T) {
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `[]`
+	})
+
+	servers, err := stubClient.Zones.TransferServers(context.Background(), testDomain)
+	assert.NoError(t, err)
+	assert.Empty(t, servers)
+}
+
+func TestZoneService_AddTransferServer(t *testing.T) {
+	// given
+	var params map[string]interface{}
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		_ = json.NewDecoder(req.Body).Decode(&params)
+		return `{"status":"Success","statusDescription":"The IP was added successfully."}`
+	})
+
+	// when
+	_, err := stubClient.Zones.AddTransferServer(context.Background(), testDomain, net.ParseIP("192.0.2.53"))
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "192.0.2.53", params["ip"])
+}
@@ -2,8 +2,14 @@ package cloudns
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"iter"
 	"net"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 )
 
 const zoneAvailableNameserversURL = "/dns/available-name-servers.json"
@@ -15,8 +21,15 @@ const zoneIsUpdatedURL = "/dns/is-updated.json"
 const zoneSetActiveURL = "/dns/change-status.json"
 const zoneUsageURL = "/dns/get-zones-stats.json"
 const zonePageCountURL = "/dns/get-pages-count.json"
+const zoneRecordCountURL = "/dns/get-records-count.json"
+const zoneMasterServersURL = "/dns/master-servers.json"
+const zoneRegisterURL = "/dns/register.json"
+const zoneDeleteURL = "/dns/delete.json"
 const zoneRowsPerPage = 100
 
+// DefaultZoneSearchConcurrency is the amount of zone pages fetched in parallel by ZoneService.Search
+const DefaultZoneSearchConcurrency = 4
+
 // ZoneType is an enumeration of all supported zone types
 type ZoneType int
 
@@ -53,6 +66,32 @@ type Zone struct {
 	IsActive APIBool  `json:"status"`
 }
 
+// MasterServer represents a master server of a slave zone
+type MasterServer struct {
+	ID int    `json:"id,string"`
+	IP net.IP `json:"ip"`
+}
+
+// ZoneSearchOptions customizes the behavior of ZoneService.SearchWithOptions
+type ZoneSearchOptions struct {
+	// PageRetries is the amount of additional attempts for fetching a single page before considering it as failed
+	PageRetries int
+	// AllowPartial returns the zones of all successfully fetched pages together with a *PartialResultError instead of
+	// aborting the whole search once a page has failed
+	AllowPartial bool
+	// Concurrency is the maximum amount of pages fetched in parallel, defaulting to DefaultZoneSearchConcurrency. Use 1
+	// for fetching pages sequentially. All requests are still subject to the rate limiter of the client, if any.
+	Concurrency int
+}
+
+// ZoneCreateOptions contains optional settings for creating a new zone
+type ZoneCreateOptions struct {
+	// Nameservers are added as apex NS records to new master zones instead of the default ClouDNS nameservers
+	Nameservers []string
+	// MasterIP is the address of the master server, which is required for slave zones
+	MasterIP net.IP
+}
+
 // ZoneUsage represents the current zone usage for a ClouDNS account
 type ZoneUsage struct {
 	Current int `json:"count,string"`
@@ -78,18 +117,41 @@ type ZoneUpdateStatus struct {
 	IsUpdated APIBool `json:"updated"`
 }
 
+// NameserverReport represents the result of comparing the nameservers of a zone as seen by the registrar, the apex NS
+// records within the zone and the nameservers provided by ClouDNS. All hostnames are lowercased without trailing dot.
+type NameserverReport struct {
+	Registrar []string
+	Zone      []string
+
+	// MissingAtRegistrar contains nameservers which are listed as NS records, but not delegated to by the registrar
+	MissingAtRegistrar []string
+	// MissingInZone contains nameservers which are delegated to by the registrar, but not listed as NS records
+	MissingInZone []string
+	// Foreign contains nameservers delegated to by the registrar or listed as NS records which are not provided by
+	// ClouDNS for the current account and are therefore unlikely to serve the zone
+	Foreign []string
+}
+
 // List returns all zones
 // Official Docs: https://www.cloudns.net/wiki/article/50/
 func (svc *ZoneService) List(ctx context.Context) ([]Zone, error) {
 	return svc.Search(ctx, "", 0)
 }
 
-// Search returns all zones matching a given name and/or group ID
+// Search returns all zones matching a given name and/or group ID, fetching up to DefaultZoneSearchConcurrency pages in
+// parallel after retrieving the page count
 // Official Docs: https://www.cloudns.net/wiki/article/50/
 func (svc *ZoneService) Search(ctx context.Context, search string, groupID int) ([]Zone, error) {
+	return svc.SearchWithOptions(ctx, search, groupID, ZoneSearchOptions{})
+}
+
+// SearchWithOptions returns all zones matching a given name and/or group ID, allowing to customize how failed page
+// requests are handled. If AllowPartial is set and some pages could not be fetched, the zones of all other pages are
+// returned together with a *PartialResultError.
+// Official Docs: https://www.cloudns.net/wiki/article/50/
+func (svc *ZoneService) SearchWithOptions(ctx context.Context, search string, groupID int, options ZoneSearchOptions) ([]Zone, error) {
 	var err error
 	var pageCount int
-	var pageResults []Zone
 
 	// Build search parameters for zone querying
 	params := HTTPParams{"rows-per-page": zoneRowsPerPage}
@@ -106,22 +168,152 @@ func (svc *ZoneService) Search(ctx context.Context, search string, groupID int)
 		return nil, err
 	}
 
-	// Fetch all pages iteratively and gather the results together
+	// Fetch all pages, possibly concurrently, and gather the results together in order of their page index
+	pages, pageErrors, err := svc.fetchPages(ctx, params, pageCount, options)
+	if err != nil {
+		return nil, err
+	}
+
+	var partialErr *PartialResultError
 	results := make([]Zone, 0, pageCount*zoneRowsPerPage)
 	for pageIndex := 1; pageIndex <= pageCount; pageIndex++ {
-		params["page"] = pageIndex
-		err = svc.api.request(ctx, "POST", zoneListURL, params, nil, &pageResults)
-		if err != nil {
-			return nil, err
+		if err := pageErrors[pageIndex-1]; err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+
+			if partialErr == nil {
+				partialErr = &PartialResultError{TotalPages: pageCount, PageErrors: make(map[int]error)}
+			}
+			partialErr.FailedPages = append(partialErr.FailedPages, pageIndex)
+			partialErr.PageErrors[pageIndex] = err
+			continue
 		}
 
-		results = append(results, pageResults...)
+		results = append(results, pages[pageIndex-1]...)
+	}
+
+	if partialErr != nil {
+		return results, partialErr
 	}
 
 	return results, nil
 }
 
-// Get returns a zone with a given name
+// Iterate returns an iterator over all zones matching a given name and/or group ID, which lazily fetches one page after
+// another while iterating, so that breaking out of the loop early saves the remaining requests. Iteration stops after
+// yielding the first error.
+func (svc *ZoneService) Iterate(ctx context.Context, search string, groupID int) iter.Seq2[Zone, error] {
+	return func(yield func(Zone, error) bool) {
+		params := HTTPParams{"rows-per-page": zoneRowsPerPage}
+		if search != "" {
+			params["search"] = search
+		}
+		if groupID != 0 {
+			params["group-id"] = groupID
+		}
+
+		var pageCount int
+		if err := svc.api.request(ctx, "POST", zonePageCountURL, params, nil, &pageCount); err != nil {
+			yield(Zone{}, err)
+			return
+		}
+
+		for pageIndex := 1; pageIndex <= pageCount; pageIndex++ {
+			zones, err := svc.fetchPage(ctx, params, pageIndex, 0)
+			if err != nil {
+				yield(Zone{}, err)
+				return
+			}
+
+			for _, zone := range zones {
+				if !yield(zone, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// fetchPages fetches the given amount of zone pages using up to options.Concurrency parallel requests. Unless partial
+// results are allowed, all outstanding requests are cancelled as soon as a single page has failed and the error of that
+// page is returned.
+func (svc *ZoneService) fetchPages(ctx context.Context, params HTTPParams, pageCount int, options ZoneSearchOptions) ([][]Zone, []error, error) {
+	pages := make([][]Zone, pageCount)
+	pageErrors := make([]error, pageCount)
+
+	concurrency := options.Concurrency
+	if concurrency < 1 {
+		concurrency = DefaultZoneSearchConcurrency
+	}
+	if concurrency > pageCount {
+		concurrency = pageCount
+	}
+
+	fetchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var failOnce sync.Once
+	var failErr error
+
+	pageIndices := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < concurrency; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for pageIndex := range pageIndices {
+				pages[pageIndex-1], pageErrors[pageIndex-1] = svc.fetchPage(fetchCtx, params, pageIndex, options.PageRetries)
+				if err := pageErrors[pageIndex-1]; err != nil && !options.AllowPartial {
+					failOnce.Do(func() {
+						failErr = err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+	for pageIndex := 1; pageIndex <= pageCount; pageIndex++ {
+		if fetchCtx.Err() != nil {
+			pageErrors[pageIndex-1] = fetchCtx.Err()
+			continue
+		}
+		pageIndices <- pageIndex
+	}
+	close(pageIndices)
+	wg.Wait()
+
+	if failErr == nil && !options.AllowPartial {
+		for _, err := range pageErrors {
+			if err != nil {
+				failErr = err
+				break
+			}
+		}
+	}
+
+	return pages, pageErrors, failErr
+}
+
+// fetchPage fetches a single page of zones, retrying up to the given amount of times if the request fails
+func (svc *ZoneService) fetchPage(ctx context.Context, searchParams HTTPParams, pageIndex, retries int) (results []Zone, err error) {
+	params := make(HTTPParams, len(searchParams)+1)
+	copyParams(params, searchParams)
+	params["page"] = pageIndex
+
+	for attempt := 0; attempt <= retries; attempt++ {
+		results = nil
+		if err = svc.api.request(ctx, "POST", zoneListURL, params, nil, &results); err == nil || ctx.Err() != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// Get returns a zone with a given name. The zone information only consists of name, type, kind and status, use
+// Records.GetSOA, GetRecordCount or MasterServers to retrieve the serial, record count or master servers of a zone.
 // Official Docs: https://www.cloudns.net/wiki/article/134/
 func (svc *ZoneService) Get(ctx context.Context, zoneName string) (result Zone, err error) {
 	params := HTTPParams{"domain-name": zoneName}
@@ -129,11 +321,92 @@ func (svc *ZoneService) Get(ctx context.Context, zoneName string) (result Zone,
 	return
 }
 
-// TriggerUpdate triggers a manual update for a given zone
+// find returns the zone with the given name by searching for it, which unlike Get reliably distinguishes missing zones
+// from other failures, as the descriptions returned by ClouDNS for missing zones are ambiguous
+func (svc *ZoneService) find(ctx context.Context, zoneName string) (Zone, bool, error) {
+	zones, err := svc.Search(ctx, normalizeHostname(zoneName), 0)
+	if err != nil {
+		return Zone{}, false, err
+	}
+
+	for _, zone := range zones {
+		if normalizeHostname(zone.Name) == normalizeHostname(zoneName) {
+			return zone, true, nil
+		}
+	}
+
+	return Zone{}, false, nil
+}
+
+// GetRecordCount returns the amount of records within the given zone
+func (svc *ZoneService) GetRecordCount(ctx context.Context, zoneName string) (result int, err error) {
+	params := HTTPParams{"domain-name": zoneName}
+	err = svc.api.request(ctx, "POST", zoneRecordCountURL, params, nil, &result)
+	return
+}
+
+// MasterServers returns the master servers of the given slave zone
+func (svc *ZoneService) MasterServers(ctx context.Context, zoneName string) ([]MasterServer, error) {
+	var result map[string]MasterServer
+
+	params := HTTPParams{"domain-name": zoneName}
+	if err := svc.api.request(ctx, "POST", zoneMasterServersURL, params, nil, &result); err != nil {
+		return nil, err
+	}
+
+	servers := make([]MasterServer, 0, len(result))
+	for _, server := range result {
+		servers = append(servers, server)
+	}
+	sort.Slice(servers, func(i, j int) bool {
+		return servers[i].ID < servers[j].ID
+	})
+
+	return servers, nil
+}
+
+// Create registers a new zone with the given name and type
+// Official Docs: https://www.cloudns.net/wiki/article/48/
+func (svc *ZoneService) Create(ctx context.Context, zoneName string, zoneType ZoneType, options ZoneCreateOptions) (result StatusResult, err error) {
+	params := HTTPParams{"domain-name": zoneName}
+	switch zoneType {
+	case ZoneTypeMaster, ZoneTypeSlave, ZoneTypeParked, ZoneTypeGeoDNS:
+		params["zone-type"] = zoneType.String()
+	default:
+		return result, ErrIllegalArgument.wrap(errors.New("invalid zone type"))
+	}
+
+	if zoneType == ZoneTypeSlave {
+		if options.MasterIP == nil {
+			return result, ErrIllegalArgument.wrap(errors.New("slave zones require a master ip"))
+		}
+		params["master-ip"] = options.MasterIP.String()
+	}
+	if len(options.Nameservers) > 0 {
+		params["ns"] = options.Nameservers
+	}
+
+	err = svc.api.request(ctx, "POST", zoneRegisterURL, params, nil, &result)
+	return
+}
+
+// Delete deletes the zone with the given name including all of its records
+// Official Docs: https://www.cloudns.net/wiki/article/49/
+func (svc *ZoneService) Delete(ctx context.Context, zoneName string) (result StatusResult, err error) {
+	params := HTTPParams{"domain-name": zoneName}
+	err = svc.api.request(ctx, "POST", zoneDeleteURL, params, nil, &result)
+	return
+}
+
+// TriggerUpdate triggers a manual update for a given zone. If ClouDNS rejects the trigger because the previous one was
+// too recent, an UpdateThrottledError containing the earliest time for the next trigger is returned.
 // Official Docs: https://www.cloudns.net/wiki/article/135/
 func (svc *ZoneService) TriggerUpdate(ctx context.Context, zoneName string) (result StatusResult, err error) {
 	params := HTTPParams{"domain-name": zoneName}
 	err = svc.api.request(ctx, "POST", zoneTriggerUpdateURL, params, nil, &result)
+	if err != nil {
+		err = newUpdateThrottledError(zoneName, svc.api.clock.Now(), err)
+	}
 	return
 }
 
@@ -167,9 +440,19 @@ func (svc *ZoneService) GetUpdateStatus(ctx context.Context, zoneName string) (r
 	return
 }
 
-// AvailableNameservers returns all nameservers available for the current account
+// AvailableNameservers returns all nameservers available for the current account. The result is memoized if the
+// NameserverCacheTTL option has been specified, which can be bypassed by using a context created with WithForceRefresh.
 // Official Docs: https://www.cloudns.net/wiki/article/47/
 func (svc *ZoneService) AvailableNameservers(ctx context.Context) (result []Nameserver, err error) {
+	if svc.api.nameservers != nil {
+		result, err = svc.api.nameservers.get(ctx, svc.api.clock, svc.fetchAvailableNameservers)
+		return append([]Nameserver(nil), result...), err
+	}
+
+	return svc.fetchAvailableNameservers(ctx)
+}
+
+func (svc *ZoneService) fetchAvailableNameservers(ctx context.Context) (result []Nameserver, err error) {
 	err = svc.api.request(ctx, "POST", zoneAvailableNameserversURL, nil, nil, &result)
 	return
 }
@@ -181,6 +464,90 @@ func (svc *ZoneService) GetUsage(ctx context.Context) (result ZoneUsage, err err
 	return
 }
 
+// CheckNameservers compares the nameservers set at the registrar of a zone with the NS records at the zone apex and the
+// nameservers available to the current ClouDNS account. The registrar nameservers have to be provided by the caller,
+// e.g. from a WHOIS lookup or the API of the registrar. DomainService.CheckNameservers looks them up for domains which
+// are registered through ClouDNS.
+func (svc *ZoneService) CheckNameservers(ctx context.Context, zoneName string, registrarNameservers []string) (result NameserverReport, err error) {
+	records, err := svc.api.Records.Search(ctx, zoneName, "", RecordTypeNS)
+	if err != nil {
+		return
+	}
+
+	available, err := svc.AvailableNameservers(ctx)
+	if err != nil {
+		return
+	}
+
+	var zoneNameservers []string
+	for _, record := range records.SortedSlice() {
+		if record.Host == "" || record.Host == "@" {
+			zoneNameservers = append(zoneNameservers, record.Record)
+		}
+	}
+
+	return compareNameservers(registrarNameservers, zoneNameservers, available), nil
+}
+
+// IsConsistent returns true if the registrar and the zone agree on the same set of nameservers, all provided by ClouDNS
+func (report NameserverReport) IsConsistent() bool {
+	return len(report.MissingAtRegistrar) == 0 && len(report.MissingInZone) == 0 && len(report.Foreign) == 0
+}
+
+func compareNameservers(registrarNameservers, zoneNameservers []string, available []Nameserver) NameserverReport {
+	normalize := func(hostnames []string) []string {
+		results := make([]string, 0, len(hostnames))
+		for _, hostname := range hostnames {
+			hostname = normalizeHostname(hostname)
+			if hostname != "" && !containsString(hostname, results) {
+				results = append(results, hostname)
+			}
+		}
+
+		sort.Strings(results)
+		return results
+	}
+	difference := func(left, right []string) (results []string) {
+		for _, value := range left {
+			if !containsString(value, right) {
+				results = append(results, value)
+			}
+		}
+		return
+	}
+
+	availableNames := make([]string, 0, len(available))
+	for _, nameserver := range available {
+		availableNames = append(availableNames, nameserver.Name)
+	}
+
+	registrar := normalize(registrarNameservers)
+	zone := normalize(zoneNameservers)
+	return NameserverReport{
+		Registrar:          registrar,
+		Zone:               zone,
+		MissingAtRegistrar: difference(zone, registrar),
+		MissingInZone:      difference(registrar, zone),
+		Foreign:            difference(normalize(append(registrar, zone...)), normalize(availableNames)),
+	}
+}
+
+// String returns the name of the zone type as used by the ClouDNS API
+func (zt ZoneType) String() string {
+	switch zt {
+	case ZoneTypeMaster:
+		return "master"
+	case ZoneTypeSlave:
+		return "slave"
+	case ZoneTypeParked:
+		return "parked"
+	case ZoneTypeGeoDNS:
+		return "geodns"
+	}
+
+	return "unknown"
+}
+
 // UnmarshalJSON converts the ClouDNS zone type into the correct ZoneType enumeration value
 func (zt *ZoneType) UnmarshalJSON(data []byte) error {
 	switch strings.Trim(string(data), `"`) {
@@ -199,7 +566,77 @@ func (zt *ZoneType) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-// UnmarshalJSON converts the ClouDNS zone type into the correct ZoneType enumeration value
+// String returns the name of the zone kind as used by the ClouDNS API
+func (zk ZoneKind) String() string {
+	switch zk {
+	case ZoneKindDomain:
+		return "domain"
+	case ZoneKindIPv4:
+		return "ipv4"
+	case ZoneKindIPv6:
+		return "ipv6"
+	}
+
+	return "unknown"
+}
+
+// ZoneKindFromName infers the zone kind from the name of a zone. Names below `in-addr.arpa` and `ip6.arpa` are reverse
+// zones, all other names are domains. ZoneKindUnknown is returned for malformed reverse zone names, e.g. with octets
+// exceeding 255 or IPv6 labels consisting of more than a single nibble. Classless IPv4 delegations according to RFC2317
+// like `0/25.2.0.192.in-addr.arpa` are supported.
+func ZoneKindFromName(zoneName string) ZoneKind {
+	name := strings.ToLower(strings.TrimSuffix(zoneName, "."))
+
+	switch {
+	case name == "in-addr.arpa" || name == "ip6.arpa":
+		return ZoneKindUnknown
+	case strings.HasSuffix(name, ".in-addr.arpa"):
+		labels := strings.Split(strings.TrimSuffix(name, ".in-addr.arpa"), ".")
+		if len(labels) > 4 {
+			return ZoneKindUnknown
+		}
+		for index, label := range labels {
+			if index == 0 && strings.ContainsAny(label, "/-") {
+				continue
+			}
+			if octet, err := strconv.Atoi(label); err != nil || octet < 0 || octet > 255 {
+				return ZoneKindUnknown
+			}
+		}
+		return ZoneKindIPv4
+	case strings.HasSuffix(name, ".ip6.arpa"):
+		labels := strings.Split(strings.TrimSuffix(name, ".ip6.arpa"), ".")
+		if len(labels) > 32 {
+			return ZoneKindUnknown
+		}
+		for _, label := range labels {
+			if len(label) != 1 || !strings.Contains("0123456789abcdef", label) {
+				return ZoneKindUnknown
+			}
+		}
+		return ZoneKindIPv6
+	}
+
+	return ZoneKindDomain
+}
+
+// VerifyKind compares the kind of the given zone as returned by the API with the kind inferred from its name, which
+// catches e.g. reverse zones that have accidentally been created as domains. ErrZoneKindMismatch is returned if both
+// kinds differ.
+func (svc *ZoneService) VerifyKind(ctx context.Context, zoneName string) (Zone, error) {
+	zone, err := svc.Get(ctx, zoneName)
+	if err != nil {
+		return zone, err
+	}
+
+	if expected := ZoneKindFromName(zoneName); zone.Kind != expected {
+		return zone, ErrZoneKindMismatch.wrap(fmt.Errorf("zone %s is of kind %s instead of %s", zoneName, zone.Kind, expected))
+	}
+
+	return zone, nil
+}
+
+// UnmarshalJSON converts the ClouDNS zone kind into the correct ZoneKind enumeration value
 func (zk *ZoneKind) UnmarshalJSON(data []byte) error {
 	switch strings.Trim(string(data), `"`) {
 	case "domain":
@@ -0,0 +1,146 @@
+package cloudns
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ZoneBackup is a serializable snapshot of a master zone including its records and settings, see ZoneService.Backup
+type ZoneBackup struct {
+	// Zone uses the stable representation of Zone.Document, which keeps backups independent of the API wire format
+	Zone    ZoneDocument `json:"zone"`
+	TakenAt time.Time    `json:"takenAt"`
+	SOA     SOA          `json:"soa"`
+	Records []Record     `json:"records"`
+	// DNSSEC is true if DNSSEC was active for the zone
+	DNSSEC bool `json:"dnssec"`
+}
+
+// RestoreResult represents the outcome of restoring a zone from a backup
+type RestoreResult struct {
+	Zone string
+	// Created is true if the zone did not exist and has been created
+	Created bool
+	// Changes contains all record changes which have been applied to the zone
+	Changes []RecordChange
+	// Skipped contains all records of the backup which were not restored, either because they are managed by ClouDNS
+	// like the apex NS records, or because they conflicted with existing records
+	Skipped []Record
+	// DNSSECActivated is true if DNSSEC has been activated for the zone
+	DNSSECActivated bool
+}
+
+// Backup takes a snapshot of the records, SOA settings, status and DNSSEC status of a master zone for disaster
+// recovery, which can be serialized as JSON and replayed with ZoneService.Restore
+func (svc *ZoneService) Backup(ctx context.Context, zoneName string) (backup ZoneBackup, err error) {
+	zone, err := svc.Get(ctx, zoneName)
+	if err != nil {
+		return
+	}
+	if zone.Type != ZoneTypeMaster {
+		return backup, ErrIllegalArgument.wrap(fmt.Errorf("zone %s is of type %s instead of master", zoneName, zone.Type))
+	}
+	backup.Zone = zone.Document()
+
+	backup.TakenAt = svc.api.clock.Now()
+	records, err := svc.api.Records.List(ctx, zoneName)
+	if err != nil {
+		return
+	}
+	backup.Records = records.SortedSlice()
+
+	if backup.SOA, err = svc.api.Records.GetSOA(ctx, zoneName); err != nil {
+		return
+	}
+	backup.DNSSEC, err = isDNSSECActive(ctx, svc.api, zoneName)
+	return
+}
+
+// Restore replays a backup taken with ZoneService.Backup into the given zone, which is created if missing and may
+// differ from the zone the backup was taken of. With overwrite, the zone is synchronized to contain exactly the records
+// of the backup, see RecordService.Sync, otherwise records of the backup conflicting with existing records are skipped.
+// The SOA settings except for the primary nameserver and the zone status are restored as well, and DNSSEC is activated
+// if it was active before. DNSSEC is never deactivated, as doing so breaks the delegation of signed zones.
+func (svc *ZoneService) Restore(ctx context.Context, zoneName string, backup ZoneBackup, overwrite bool) (result RestoreResult, err error) {
+	result.Zone = zoneName
+
+	zone, exists, err := svc.find(ctx, zoneName)
+	switch {
+	case err != nil:
+		return
+	case !exists:
+		if _, err = svc.Create(ctx, zoneName, ZoneTypeMaster, ZoneCreateOptions{}); err != nil {
+			return
+		}
+		zone = Zone{Name: zoneName, Type: ZoneTypeMaster, IsActive: true}
+		result.Created = true
+	case zone.Type != ZoneTypeMaster:
+		return result, ErrIllegalArgument.wrap(fmt.Errorf("zone %s is of type %s instead of master", zoneName, zone.Type))
+	}
+
+	var records []Record
+	for _, record := range backup.Records {
+		if record.RecordType == RecordTypeNS && normalizeRecordHost(record.Host) == "" {
+			result.Skipped = append(result.Skipped, record)
+			continue
+		}
+
+		record.ID = 0
+		records = append(records, record)
+	}
+
+	if overwrite {
+		var plan SyncPlan
+		plan, err = svc.api.Records.Sync(ctx, zoneName, records, SyncOptions{})
+		if result.Changes = plan.Applied; err != nil {
+			return
+		}
+	} else {
+		var existing RecordMap
+		if existing, err = svc.api.Records.List(ctx, zoneName); err != nil {
+			return
+		}
+
+		var plan importPlan
+		if plan, err = planImport(existing, records, ImportConflictSkip); err != nil {
+			return
+		}
+		result.Skipped = append(result.Skipped, plan.skipped...)
+
+		if result.Changes, err = svc.api.Records.ApplyChanges(ctx, zoneName, plan.changes()); err != nil {
+			return
+		}
+	}
+
+	// Restore the SOA settings, keeping the primary nameserver which depends on the account
+	soa, err := svc.api.Records.GetSOA(ctx, zoneName)
+	if err != nil {
+		return
+	}
+	backup.SOA.PrimaryNS = soa.PrimaryNS
+	if _, err = svc.api.Records.UpdateSOA(ctx, zoneName, backup.SOA); err != nil {
+		return
+	}
+
+	if bool(zone.IsActive) != backup.Zone.Active {
+		if _, err = svc.SetActive(ctx, zoneName, backup.Zone.Active); err != nil {
+			return
+		}
+	}
+
+	if backup.DNSSEC {
+		var active bool
+		if active, err = isDNSSECActive(ctx, svc.api, zoneName); err != nil {
+			return
+		}
+		if !active {
+			if _, err = svc.api.DNSSEC.Activate(ctx, zoneName); err != nil {
+				return
+			}
+			result.DNSSECActivated = true
+		}
+	}
+
+	return result, nil
+}
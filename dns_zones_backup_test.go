@@ -0,0 +1,91 @@
+package cloudns
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func newTestZoneBackup(t *testing.T) ZoneBackup {
+	backup, err := newCloneSourceClient(t, true).Zones.Backup(context.Background(), testDomain)
+	if err != nil {
+		t.Fatalf("could not take backup: %v", err)
+	}
+
+	// Backups are expected to survive serialization
+	content, _ := json.Marshal(backup)
+	var restored ZoneBackup
+	if err := json.Unmarshal(content, &restored); err != nil {
+		t.Fatalf("could not deserialize backup: %v", err)
+	}
+
+	return restored
+}
+
+func TestZoneService_Backup(t *testing.T) {
+	// when
+	backup := newTestZoneBackup(t)
+
+	// then
+	assert.Equal(t, ZoneDocument{Name: testDomain, Type: "master", Kind: "domain", Active: true}, backup.Zone)
+	assert.Len(t, backup.Records, 3)
+	assert.Equal(t, 2022122401, backup.SOA.Serial)
+	assert.Equal(t, "admin@example.com", backup.SOA.AdminMail)
+	assert.True(t, backup.DNSSEC)
+}
+
+func TestZoneService_Backup_SlaveZone(t *testing.T) {
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `{"name":"api-example.com","type":"slave","zone":"domain","status":"1"}`
+	})
+
+	_, err := stubClient.Zones.Backup(context.Background(), testDomain)
+	assert.ErrorIs(t, err, ErrIllegalArgument)
+}
+
+func TestZoneService_Restore(t *testing.T) {
+	// given
+	backup := newTestZoneBackup(t)
+	fakeTarget := &fakeCloneTarget{}
+	target := newStubClient(t, fakeTarget.handle)
+
+	// when
+	result, err := target.Zones.Restore(context.Background(), testDomain, backup, false)
+
+	// then
+	assert.NoError(t, err)
+	assert.True(t, result.Created)
+	assert.True(t, result.DNSSECActivated)
+	assert.Len(t, result.Changes, 2)
+	assert.Len(t, result.Skipped, 1, "apex NS records should be skipped")
+
+	assert.Len(t, fakeTarget.records, 3)
+	assert.Equal(t, "ns1.target.example", fakeTarget.soa["primary-ns"], "primary nameserver of target should be kept")
+	assert.Equal(t, "admin@example.com", fakeTarget.soa["admin-mail"])
+}
+
+func TestZoneService_Restore_Overwrite(t *testing.T) {
+	// given
+	backup := newTestZoneBackup(t)
+	fakeTarget := &fakeCloneTarget{exists: true, dnssec: true, records: buildRecordMap(
+		NewRecordNS("", "ns1.target.example", 3600),
+		NewRecordA("www", "192.0.2.1", 3600),
+		NewRecordA("stale", "192.0.2.99", 3600),
+	)}
+	target := newStubClient(t, fakeTarget.handle)
+
+	// when
+	result, err := target.Zones.Restore(context.Background(), testDomain, backup, true)
+
+	// then
+	assert.NoError(t, err)
+	assert.False(t, result.Created)
+	assert.False(t, result.DNSSECActivated)
+	assert.Len(t, result.Changes, 2, "stale record should be replaced by missing record")
+	assert.Len(t, fakeTarget.records, 3)
+	for _, record := range fakeTarget.records {
+		assert.NotEqual(t, "stale", record.Host)
+	}
+}
@@ -0,0 +1,175 @@
+package cloudns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// CloneOptions specifies how CloneZone recreates a zone within another account
+type CloneOptions struct {
+	// ConflictPolicy handles cloned records conflicting with records of an already existing target zone, see
+	// ImportConflictPolicy. ImportConflictServerSide is treated like ImportConflictSkip.
+	ConflictPolicy ImportConflictPolicy
+	// FailIfExists aborts the clone before performing any changes if the zone already exists within the target account
+	FailIfExists bool
+	// SkipSOA keeps the SOA settings of the target zone instead of copying them from the source zone
+	SkipSOA bool
+	// SkipDNSSEC does not activate DNSSEC for the target zone, even if it is active for the source zone
+	SkipDNSSEC bool
+}
+
+// CloneResult represents the outcome of cloning a zone into another account
+type CloneResult struct {
+	Zone string
+	// Created is true if the zone did not exist within the target account and has been created
+	Created bool
+	// Changes contains all record changes which have been applied to the target zone
+	Changes []RecordChange
+	// Skipped contains all source records which were not cloned, either because they are managed by ClouDNS like the
+	// apex NS records, or because they conflicted with existing records
+	Skipped []Record
+	// DNSSECActivated is true if DNSSEC has been activated for the target zone
+	DNSSECActivated bool
+	// Missing contains all cloned records which could not be found within the target zone during verification
+	Missing []Record
+}
+
+// CloneZone reads a master zone including its records, SOA settings and DNSSEC status using the source client and
+// recreates it using the target client, e.g. for migrating customers between ClouDNS accounts. The zone is created
+// within the target account if missing. Afterwards, the records of the target zone are verified against the source
+// records and the clone fails with ErrCloneIncomplete if any record is missing. The source zone is never modified.
+func CloneZone(ctx context.Context, source, target *Client, zoneName string, options CloneOptions) (result CloneResult, err error) {
+	result.Zone = zoneName
+
+	zone, err := source.Zones.Get(ctx, zoneName)
+	if err != nil {
+		return
+	}
+	if zone.Type != ZoneTypeMaster {
+		return result, ErrIllegalArgument.wrap(fmt.Errorf("zone %s is of type %s instead of master", zoneName, zone.Type))
+	}
+
+	sourceRecords, err := source.Records.List(ctx, zoneName)
+	if err != nil {
+		return
+	}
+	soa, err := source.Records.GetSOA(ctx, zoneName)
+	if err != nil {
+		return
+	}
+	dnssecActive, err := isDNSSECActive(ctx, source, zoneName)
+	if err != nil {
+		return
+	}
+
+	// Create the zone within the target account unless it already exists
+	_, exists, err := target.Zones.find(ctx, zoneName)
+	switch {
+	case err != nil:
+		return
+	case exists && options.FailIfExists:
+		return result, ErrIllegalArgument.wrap(fmt.Errorf("zone %s already exists within target account", zoneName))
+	case !exists:
+		if _, err = target.Zones.Create(ctx, zoneName, ZoneTypeMaster, ZoneCreateOptions{}); err != nil {
+			return
+		}
+		result.Created = true
+	}
+
+	// Apply all records except for those provided by ClouDNS
+	var cloned []Record
+	for _, record := range sourceRecords.SortedSlice() {
+		if record.RecordType == RecordTypeNS && normalizeRecordHost(record.Host) == "" {
+			result.Skipped = append(result.Skipped, record)
+			continue
+		}
+
+		record.ID = 0
+		cloned = append(cloned, record)
+	}
+
+	existing, err := target.Records.List(ctx, zoneName)
+	if err != nil {
+		return
+	}
+
+	policy := options.ConflictPolicy
+	if policy == ImportConflictServerSide {
+		policy = ImportConflictSkip
+	}
+	plan, err := planImport(existing, cloned, policy)
+	if err != nil {
+		return
+	}
+	result.Skipped = append(result.Skipped, plan.skipped...)
+
+	if result.Changes, err = target.Records.ApplyChanges(ctx, zoneName, plan.changes()); err != nil {
+		return
+	}
+
+	// Copy the SOA settings, keeping the primary nameserver which depends on the target account
+	if !options.SkipSOA {
+		var targetSOA SOA
+		if targetSOA, err = target.Records.GetSOA(ctx, zoneName); err != nil {
+			return
+		}
+
+		soa.PrimaryNS = targetSOA.PrimaryNS
+		if _, err = target.Records.UpdateSOA(ctx, zoneName, soa); err != nil {
+			return
+		}
+	}
+
+	if dnssecActive && !options.SkipDNSSEC {
+		var targetActive bool
+		if targetActive, err = isDNSSECActive(ctx, target, zoneName); err != nil {
+			return
+		}
+		if !targetActive {
+			if _, err = target.DNSSEC.Activate(ctx, zoneName); err != nil {
+				return
+			}
+			result.DNSSECActivated = true
+		}
+	}
+
+	result.Missing, err = verifyClone(ctx, target, zoneName, cloned, plan.skipped)
+	return
+}
+
+// verifyClone returns all cloned records which are missing within the target zone, ignoring records which have been
+// skipped due to conflicts
+func verifyClone(ctx context.Context, target *Client, zoneName string, cloned, skipped []Record) ([]Record, error) {
+	records, err := target.Records.List(ctx, zoneName)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := records.SortedSlice()
+	var missing []Record
+	for _, record := range cloned {
+		if index := indexOfEquivalentRecord(remaining, record); index >= 0 {
+			remaining = append(remaining[:index], remaining[index+1:]...)
+		} else if indexOfEquivalentRecord(skipped, record) < 0 {
+			missing = append(missing, record)
+		}
+	}
+
+	if len(missing) > 0 {
+		return missing, ErrCloneIncomplete.wrap(fmt.Errorf("%d records of zone %s are missing", len(missing), zoneName))
+	}
+
+	return nil, nil
+}
+
+// isDNSSECActive determines whether DNSSEC is active for the zone by checking for DS records, as ClouDNS rejects the
+// request for zones without DNSSEC. All other failures, e.g. throttled requests, are returned as error.
+func isDNSSECActive(ctx context.Context, client *Client, zoneName string) (bool, error) {
+	records, err := client.DNSSEC.GetDSRecords(ctx, zoneName)
+	if errors.Is(err, ErrDNSSECNotActive) {
+		return false, nil
+	}
+
+	return len(records) > 0, err
+}
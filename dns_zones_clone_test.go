@@ -0,0 +1,183 @@
+package cloudns
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+const testCloneSOA = `{"serialNumber":"2022122401","primaryNS":"ns1.source.example","adminMail":"admin@example.com","refresh":"7200","retry":"1800","expire":"1209600","defaultTTL":"3600"}`
+
+func newCloneSourceClient(t *testing.T, dnssec bool) *Client {
+	return newStubClient(t, func(req *http.Request) string {
+		switch req.URL.Path {
+		case zoneGetURL:
+			return `{"name":"api-example.com","type":"master","zone":"domain","status":"1"}`
+		case recordListURL:
+			records, _ := json.Marshal(buildRecordMap(
+				NewRecordNS("", "ns1.source.example", 3600),
+				NewRecordA("www", "192.0.2.1", 3600),
+				NewRecordMX("", 10, "mail.example.com", 3600),
+			))
+			return string(records)
+		case recordSOAGetURL:
+			return testCloneSOA
+		case dnssecDSRecordsURL:
+			if dnssec {
+				return `{"ds":["2371 13 2 1F987CC6583E92DF0890718C42"]}`
+			}
+			return `{"status":"Failed","statusDescription":"DNSSEC is not active for this zone."}`
+		}
+
+		t.Errorf("unexpected request to source: %s", req.URL.Path)
+		return `{"status":"Failed"}`
+	})
+}
+
+// fakeCloneTarget simulates the zone of a target account by applying created records
+type fakeCloneTarget struct {
+	exists  bool
+	records RecordMap
+	soa     map[string]interface{}
+	dnssec  bool
+}
+
+func (target *fakeCloneTarget) handle(req *http.Request) string {
+	var params map[string]interface{}
+	_ = json.NewDecoder(req.Body).Decode(&params)
+
+	switch req.URL.Path {
+	case zonePageCountURL:
+		if !target.exists {
+			return `0`
+		}
+		return `1`
+	case zoneListURL:
+		return `[{"name":"api-example.com","type":"master","zone":"domain","status":"1"}]`
+	case zoneRegisterURL:
+		target.exists = true
+		target.records = buildRecordMap(NewRecordNS("", "ns1.target.example", 3600))
+		return `{"status":"Success"}`
+	case recordListURL:
+		records, _ := json.Marshal(target.records)
+		return string(records)
+	case recordCreateURL:
+		ttl, _ := strconv.Atoi(formatParam(params["ttl"]))
+		id := len(target.records) + 1
+		target.records[id] = Record{
+			ID: id, Host: params["host"].(string), Record: params["record"].(string),
+			RecordType: RecordType(params["record-type"].(string)), TTL: ttl, IsActive: true,
+		}
+		if priority, ok := params["priority"].(float64); ok {
+			record := target.records[id]
+			record.Priority = uint16(priority)
+			target.records[id] = record
+		}
+		return `{"status":"Success"}`
+	case recordDeleteURL:
+		id, _ := strconv.Atoi(formatParam(params["record-id"]))
+		delete(target.records, id)
+		return `{"status":"Success"}`
+	case recordSOAGetURL:
+		return `{"serialNumber":"1","primaryNS":"ns1.target.example","adminMail":"other@example.com","refresh":"1","retry":"1","expire":"1","defaultTTL":"1"}`
+	case recordSOAUpdateURL:
+		target.soa = params
+		return `{"status":"Success"}`
+	case dnssecDSRecordsURL:
+		if target.dnssec {
+			return `{"ds":["2371 13 2 1F987CC6583E92DF0890718C42"]}`
+		}
+		return `{"status":"Failed","statusDescription":"DNSSEC is not active for this zone."}`
+	case dnssecActivateURL:
+		target.dnssec = true
+		return `{"status":"Success"}`
+	}
+
+	return `{"status":"Failed","statusDescription":"unexpected request"}`
+}
+
+func TestCloneZone(t *testing.T) {
+	// given
+	source := newCloneSourceClient(t, true)
+	fakeTarget := &fakeCloneTarget{}
+	target := newStubClient(t, fakeTarget.handle)
+
+	// when
+	result, err := CloneZone(context.Background(), source, target, testDomain, CloneOptions{})
+
+	// then
+	assert.NoError(t, err)
+	assert.True(t, result.Created)
+	assert.True(t, result.DNSSECActivated)
+	assert.Len(t, result.Changes, 2)
+	assert.Len(t, result.Skipped, 1, "apex NS records should be skipped")
+	assert.Empty(t, result.Missing)
+
+	assert.Len(t, fakeTarget.records, 3)
+	assert.Equal(t, "ns1.target.example", fakeTarget.soa["primary-ns"], "primary nameserver of target should be kept")
+	assert.Equal(t, "admin@example.com", fakeTarget.soa["admin-mail"])
+	assert.True(t, fakeTarget.dnssec)
+}
+
+func TestCloneZone_FailIfExists(t *testing.T) {
+	// given
+	source := newCloneSourceClient(t, false)
+	fakeTarget := &fakeCloneTarget{exists: true, records: make(RecordMap)}
+	target := newStubClient(t, fakeTarget.handle)
+
+	// when
+	_, err := CloneZone(context.Background(), source, target, testDomain, CloneOptions{FailIfExists: true})
+
+	// then
+	assert.ErrorIs(t, err, ErrIllegalArgument)
+	assert.Empty(t, fakeTarget.records, "no changes should be performed")
+}
+
+func TestCloneZone_Conflicts(t *testing.T) {
+	// given
+	source := newCloneSourceClient(t, false)
+	fakeTarget := &fakeCloneTarget{exists: true, records: buildRecordMap(NewRecordA("www", "192.0.2.99", 3600))}
+	target := newStubClient(t, fakeTarget.handle)
+
+	// when
+	result, err := CloneZone(context.Background(), source, target, testDomain, CloneOptions{SkipSOA: true})
+
+	// then
+	assert.NoError(t, err, "skipped conflicts should not fail verification")
+	assert.False(t, result.Created)
+	assert.False(t, result.DNSSECActivated)
+	assert.Len(t, result.Changes, 1)
+	assert.Len(t, result.Skipped, 2)
+	assert.Nil(t, fakeTarget.soa)
+}
+
+func TestCloneZone_Incomplete(t *testing.T) {
+	// given
+	source := newCloneSourceClient(t, false)
+	fakeTarget := &fakeCloneTarget{}
+	target := newStubClient(t, func(req *http.Request) string {
+		if req.URL.Path == recordCreateURL {
+			return `{"status":"Success"}`
+		}
+		return fakeTarget.handle(req)
+	})
+
+	// when
+	result, err := CloneZone(context.Background(), source, target, testDomain, CloneOptions{})
+
+	// then
+	assert.ErrorIs(t, err, ErrCloneIncomplete)
+	assert.Len(t, result.Missing, 2)
+}
+
+func TestIsDNSSECActive_Throttled(t *testing.T) {
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `{"status":"Failed","statusDescription":"Too many requests. Please try again later."}`
+	})
+
+	_, err := isDNSSECActive(context.Background(), stubClient, testDomain)
+	assert.ErrorIs(t, err, ErrRateLimited, "only inactive dnssec should be treated as inactive")
+}
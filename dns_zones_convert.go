@@ -0,0 +1,206 @@
+package cloudns
+
+import (
+	"context"
+	"fmt"
+)
+
+// ZoneConversion represents the result of converting a zone into another zone type
+type ZoneConversion struct {
+	Zone string
+	From ZoneType
+	To   ZoneType
+	// Records contains all records which have been recreated within the converted zone
+	Records []Record
+	// Captured contains all records of the original zone as captured before deleting it, which allows recovering them
+	// manually if neither the conversion nor the rollback succeeded
+	Captured []Record
+	// Skipped contains all records of the original zone which were not recreated, either because the new zone already
+	// contained an equivalent record or because they are managed by ClouDNS, like the apex NS records
+	Skipped []Record
+	// RolledBack is true if the conversion failed and the original zone has been restored
+	RolledBack bool
+}
+
+// PromotionOptions specifies how ZoneService.PromoteSlaveToMaster promotes a slave zone
+type PromotionOptions struct {
+	// DryRun only captures and returns the records which would be recreated, without changing the zone
+	DryRun bool
+	// RollbackToSlave recreates the slave zone with its previous master server if the promotion fails after the slave
+	// zone has been deleted
+	RollbackToSlave bool
+}
+
+// ConvertParkedToMaster converts a parked zone into a master zone by deleting and recreating it, as ClouDNS does not
+// support changing the type of a zone. All records of the parked zone are recreated within the master zone, except for
+// the apex NS records which are provided by ClouDNS, and the zone is moved back into its zone group. The zone does not
+// resolve while being recreated. If the conversion fails after the parked zone has been deleted, the parked zone is
+// recreated with its records and group.
+func (svc *ZoneService) ConvertParkedToMaster(ctx context.Context, zoneName string) (result ZoneConversion, err error) {
+	result = ZoneConversion{Zone: zoneName, From: ZoneTypeParked, To: ZoneTypeMaster}
+
+	zone, err := svc.Get(ctx, zoneName)
+	if err != nil {
+		return
+	}
+	if zone.Type != ZoneTypeParked {
+		return result, ErrIllegalArgument.wrap(fmt.Errorf("zone %s is of type %s instead of parked", zoneName, zone.Type))
+	}
+
+	records, err := svc.api.Records.List(ctx, zoneName)
+	if err != nil {
+		return
+	}
+	result.Captured = records.SortedSlice()
+
+	groupID, err := svc.groupIDOf(ctx, zoneName)
+	if err != nil {
+		return
+	}
+
+	if _, err = svc.Delete(ctx, zoneName); err != nil {
+		return
+	}
+
+	if err = svc.recreateZone(ctx, zoneName, ZoneTypeMaster, ZoneCreateOptions{}, groupID, &result); err == nil {
+		return
+	}
+
+	// Restore the parked zone, ignoring a failed deletion as the master zone might not have been created at all
+	_, _ = svc.Delete(ctx, zoneName)
+	rollback := ZoneConversion{Captured: result.Captured}
+	if rollbackErr := svc.recreateZone(ctx, zoneName, ZoneTypeParked, ZoneCreateOptions{}, groupID, &rollback); rollbackErr != nil {
+		return result, fmt.Errorf("%w (rollback failed: %v)", err, rollbackErr)
+	}
+
+	result.Records = nil
+	result.RolledBack = true
+	return
+}
+
+// recreateZone creates a zone of the given type, recreates the captured records of the conversion within it and moves
+// it into the given zone group unless the group ID is zero
+func (svc *ZoneService) recreateZone(ctx context.Context, zoneName string, zoneType ZoneType, options ZoneCreateOptions, groupID int, conversion *ZoneConversion) (err error) {
+	if _, err = svc.Create(ctx, zoneName, zoneType, options); err != nil {
+		return
+	}
+	if conversion.Records, conversion.Skipped, err = svc.recreateRecords(ctx, zoneName, conversion.Captured); err != nil {
+		return
+	}
+	if groupID != 0 {
+		_, err = svc.SetGroup(ctx, zoneName, groupID)
+	}
+
+	return
+}
+
+// groupIDOf returns the ID of the zone group containing the given zone, or zero if the zone does not belong to a group,
+// as the zone information returned by ClouDNS does not contain the group
+func (svc *ZoneService) groupIDOf(ctx context.Context, zoneName string) (int, error) {
+	groups, err := svc.Groups(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, group := range groups {
+		zones, err := svc.Search(ctx, zoneName, group.ID)
+		if err != nil {
+			return 0, err
+		}
+		for _, zone := range zones {
+			if normalizeHostname(zone.Name) == normalizeHostname(zoneName) {
+				return group.ID, nil
+			}
+		}
+	}
+
+	return 0, nil
+}
+
+// PromoteSlaveToMaster promotes a slave zone to a master zone, which is useful when decommissioning hidden masters. The
+// current records of the slave zone are captured using an export, afterwards the slave zone gets deleted, recreated as
+// master zone and all records except for the apex NS records are recreated. If the promotion fails midway and
+// RollbackToSlave has been specified, the slave zone is recreated with its first master server. Additional master
+// servers are not restored.
+func (svc *ZoneService) PromoteSlaveToMaster(ctx context.Context, zoneName string, options PromotionOptions) (result ZoneConversion, err error) {
+	result = ZoneConversion{Zone: zoneName, From: ZoneTypeSlave, To: ZoneTypeMaster}
+
+	zone, err := svc.Get(ctx, zoneName)
+	if err != nil {
+		return
+	}
+	if zone.Type != ZoneTypeSlave {
+		return result, ErrIllegalArgument.wrap(fmt.Errorf("zone %s is of type %s instead of slave", zoneName, zone.Type))
+	}
+
+	masterServers, err := svc.MasterServers(ctx, zoneName)
+	if err != nil {
+		return
+	}
+	export, err := svc.api.Records.Export(ctx, zoneName)
+	if err != nil {
+		return
+	}
+	records, err := ParseBIND(zoneName, export.Zone)
+	if err != nil {
+		return
+	}
+
+	if options.DryRun {
+		for _, record := range records {
+			if record.RecordType == RecordTypeNS && normalizeRecordHost(record.Host) == "" {
+				result.Skipped = append(result.Skipped, record)
+			} else {
+				result.Records = append(result.Records, record)
+			}
+		}
+		return
+	}
+
+	if _, err = svc.Delete(ctx, zoneName); err != nil {
+		return
+	}
+
+	if _, err = svc.Create(ctx, zoneName, ZoneTypeMaster, ZoneCreateOptions{}); err == nil {
+		result.Records, result.Skipped, err = svc.recreateRecords(ctx, zoneName, records)
+	}
+	if err == nil || !options.RollbackToSlave || len(masterServers) == 0 {
+		return
+	}
+
+	// Restore the slave zone, ignoring a failed deletion as the master zone might not have been created at all
+	_, _ = svc.Delete(ctx, zoneName)
+	if _, rollbackErr := svc.Create(ctx, zoneName, ZoneTypeSlave, ZoneCreateOptions{MasterIP: masterServers[0].IP}); rollbackErr != nil {
+		return result, fmt.Errorf("%w (rollback failed: %v)", err, rollbackErr)
+	}
+
+	result.Records = nil
+	result.RolledBack = true
+	return
+}
+
+// recreateRecords creates the given records within a freshly created zone, skipping apex NS records as well as records
+// which already exist in equivalent form. Created records are returned even if a later record could not be created.
+func (svc *ZoneService) recreateRecords(ctx context.Context, zoneName string, records []Record) (created, skipped []Record, err error) {
+	existing, err := svc.api.Records.List(ctx, zoneName)
+	if err != nil {
+		return
+	}
+	existingRecords := existing.SortedSlice()
+
+	for _, record := range records {
+		isApexNS := record.RecordType == RecordTypeNS && normalizeRecordHost(record.Host) == ""
+		if isApexNS || indexOfEquivalentRecord(existingRecords, record) >= 0 {
+			skipped = append(skipped, record)
+			continue
+		}
+
+		record.ID = 0
+		if _, err = svc.api.Records.Create(ctx, zoneName, record); err != nil {
+			return
+		}
+		created = append(created, record)
+	}
+
+	return
+}
@@ -0,0 +1,177 @@
+package cloudns
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func newParkedConversionHandler(requests *[]string, failCreate bool) func(req *http.Request) string {
+	var deleted bool
+	return func(req *http.Request) string {
+		var params map[string]interface{}
+		_ = json.NewDecoder(req.Body).Decode(&params)
+		*requests = append(*requests, req.URL.Path)
+
+		switch req.URL.Path {
+		case zoneGetURL:
+			return `{"name":"api-example.com","type":"parked","zone":"domain","status":"1"}`
+		case groupListURL:
+			return `{"1":{"id":"1","name":"parking"},"2":{"id":"2","name":"customers"}}`
+		case zonePageCountURL:
+			return `1`
+		case zoneListURL:
+			if params["group-id"] == float64(2) {
+				return `[{"name":"api-example.com","type":"parked","zone":"domain","status":"1"}]`
+			}
+			return `[]`
+		case zoneDeleteURL:
+			deleted = true
+		case zoneRegisterURL:
+			if failCreate && params["zone-type"] == "master" {
+				return `{"status":"Failed","statusDescription":"Zone limit reached."}`
+			}
+		case recordListURL:
+			if !deleted {
+				return `{
+					"1":{"id":"1","host":"","record":"ns1.parking.example","type":"NS","ttl":"3600","status":1},
+					"2":{"id":"2","host":"","record":"192.0.2.1","type":"A","ttl":"3600","status":1},
+					"3":{"id":"3","host":"www","record":"192.0.2.1","type":"A","ttl":"3600","status":1}
+				}`
+			}
+			return `{"10":{"id":"10","host":"www","record":"192.0.2.1","type":"A","ttl":"3600","status":1}}`
+		}
+		return `{"status":"Success","statusDescription":"OK"}`
+	}
+}
+
+func TestZoneService_ConvertParkedToMaster(t *testing.T) {
+	// given
+	var requests []string
+	stubClient := newStubClient(t, newParkedConversionHandler(&requests, false))
+
+	// when
+	result, err := stubClient.Zones.ConvertParkedToMaster(context.Background(), testDomain)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		zoneGetURL, recordListURL, groupListURL, zonePageCountURL, zoneListURL, zonePageCountURL, zoneListURL,
+		zoneDeleteURL, zoneRegisterURL, recordListURL, recordCreateURL, groupChangeURL,
+	}, requests, "zone should be moved back into its group")
+	assert.Len(t, result.Captured, 3)
+	assert.Len(t, result.Records, 1)
+	assert.Equal(t, "", result.Records[0].Host)
+	assert.Len(t, result.Skipped, 2, "apex ns and existing records should be skipped")
+	assert.False(t, result.RolledBack)
+}
+
+func TestZoneService_ConvertParkedToMaster_Rollback(t *testing.T) {
+	// given
+	var requests []string
+	stubClient := newStubClient(t, newParkedConversionHandler(&requests, true))
+
+	// when
+	result, err := stubClient.Zones.ConvertParkedToMaster(context.Background(), testDomain)
+
+	// then
+	assert.ErrorIs(t, err, ErrAPIInvocation)
+	assert.True(t, result.RolledBack)
+	assert.Len(t, result.Captured, 3, "captured records should be returned")
+	assert.Nil(t, result.Records)
+	assert.Equal(t, []string{
+		zoneDeleteURL, zoneRegisterURL, zoneDeleteURL, zoneRegisterURL, recordListURL, recordCreateURL, groupChangeURL,
+	}, requests[7:], "parked zone should be recreated with its records and group")
+}
+
+func TestZoneService_ConvertParkedToMaster_WrongType(t *testing.T) {
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `{"name":"api-example.com","type":"master","zone":"domain","status":"1"}`
+	})
+
+	_, err := stubClient.Zones.ConvertParkedToMaster(context.Background(), testDomain)
+	assert.ErrorIs(t, err, ErrIllegalArgument)
+}
+
+func TestZoneService_Create_Invalid(t *testing.T) {
+	client, err := New()
+	assert.NoError(t, err)
+
+	_, err = client.Zones.Create(context.Background(), testDomain, ZoneTypeUnknown, ZoneCreateOptions{})
+	assert.ErrorIs(t, err, ErrIllegalArgument)
+
+	_, err = client.Zones.Create(context.Background(), testDomain, ZoneTypeSlave, ZoneCreateOptions{})
+	assert.ErrorIs(t, err, ErrIllegalArgument, "slave zones should require master ip")
+}
+
+func newSlavePromotionHandler(requests *[]string, failCreate bool) func(req *http.Request) string {
+	return func(req *http.Request) string {
+		var params map[string]interface{}
+		_ = json.NewDecoder(req.Body).Decode(&params)
+		*requests = append(*requests, fmt.Sprintf("%s %v", req.URL.Path, params["zone-type"]))
+
+		switch req.URL.Path {
+		case zoneGetURL:
+			return `{"name":"api-example.com","type":"slave","zone":"domain","status":"1"}`
+		case zoneMasterServersURL:
+			return `{"1":{"id":"1","ip":"192.0.2.53"}}`
+		case recordExportURL:
+			return `{"status":"Success","zone":"$ORIGIN api-example.com.\n@ 3600 IN SOA ns1.api-example.com. admin.api-example.com. 1 7200 1800 1209600 3600\n@ 3600 IN NS ns1.example.net.\nwww 3600 IN A 192.0.2.1\n"}`
+		case recordListURL:
+			return `[]`
+		case zoneRegisterURL:
+			if failCreate && params["zone-type"] == "master" {
+				return `{"status":"Failed","statusDescription":"Limit reached"}`
+			}
+		}
+		return `{"status":"Success","statusDescription":"OK"}`
+	}
+}
+
+func TestZoneService_PromoteSlaveToMaster(t *testing.T) {
+	// given
+	var requests []string
+	stubClient := newStubClient(t, newSlavePromotionHandler(&requests, false))
+
+	// when
+	result, err := stubClient.Zones.PromoteSlaveToMaster(context.Background(), testDomain, PromotionOptions{})
+
+	// then
+	assert.NoError(t, err)
+	assert.False(t, result.RolledBack)
+	assert.Len(t, result.Records, 1)
+	assert.Len(t, result.Skipped, 1)
+	assert.Contains(t, requests, zoneRegisterURL+" master")
+	assert.Contains(t, requests, recordCreateURL+" <nil>")
+}
+
+func TestZoneService_PromoteSlaveToMaster_DryRun(t *testing.T) {
+	// given
+	var requests []string
+	stubClient := newStubClient(t, newSlavePromotionHandler(&requests, false))
+
+	// when
+	result, err := stubClient.Zones.PromoteSlaveToMaster(context.Background(), testDomain, PromotionOptions{DryRun: true})
+
+	// then
+	assert.NoError(t, err)
+	assert.Len(t, result.Records, 1)
+	assert.NotContains(t, requests, zoneDeleteURL+" <nil>", "dry run should not delete zone")
+}
+
+func TestZoneService_PromoteSlaveToMaster_Rollback(t *testing.T) {
+	// given
+	var requests []string
+	stubClient := newStubClient(t, newSlavePromotionHandler(&requests, true))
+
+	// when
+	result, err := stubClient.Zones.PromoteSlaveToMaster(context.Background(), testDomain, PromotionOptions{RollbackToSlave: true})
+
+	// then
+	assert.ErrorIs(t, err, ErrAPIInvocation)
+	assert.True(t, result.RolledBack)
+	assert.Equal(t, zoneRegisterURL+" slave", requests[len(requests)-1], "slave zone should be recreated")
+}
@@ -1,8 +1,16 @@
 package cloudns
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/stretchr/testify/assert"
+	"net/http"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestZoneService_AvailableNameservers(t *testing.T) {
@@ -90,3 +98,269 @@ func TestZoneService_GetUsage(t *testing.T) {
 	_, err := client.Zones.GetUsage(ctx)
 	assert.NoError(t, err, "should not fail")
 }
+
+func TestCompareNameservers(t *testing.T) {
+	available := []Nameserver{{Name: "ns1.cloudns.net"}, {Name: "ns2.cloudns.net"}, {Name: "ns3.cloudns.net"}}
+
+	consistent := compareNameservers(
+		[]string{"NS1.cloudns.net.", "ns2.cloudns.net"},
+		[]string{"ns2.cloudns.net", "ns1.cloudns.net"},
+		available,
+	)
+	assert.True(t, consistent.IsConsistent(), "matching nameservers should be consistent")
+
+	inconsistent := compareNameservers(
+		[]string{"ns1.cloudns.net", "ns1.legacy.local"},
+		[]string{"ns1.cloudns.net", "ns3.cloudns.net"},
+		available,
+	)
+	assert.False(t, inconsistent.IsConsistent(), "mismatching nameservers should not be consistent")
+	assert.Equal(t, []string{"ns3.cloudns.net"}, inconsistent.MissingAtRegistrar)
+	assert.Equal(t, []string{"ns1.legacy.local"}, inconsistent.MissingInZone)
+	assert.Equal(t, []string{"ns1.legacy.local"}, inconsistent.Foreign)
+}
+
+func TestZoneService_GetRecordCount(t *testing.T) {
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `42`
+	})
+
+	count, err := stubClient.Zones.GetRecordCount(context.Background(), testDomain)
+	assert.NoError(t, err, "should not fail")
+	assert.Equal(t, 42, count)
+}
+
+func TestZoneService_MasterServers(t *testing.T) {
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `{"7":{"id":"7","ip":"192.0.2.2"},"3":{"id":"3","ip":"192.0.2.1"}}`
+	})
+
+	servers, err := stubClient.Zones.MasterServers(context.Background(), testDomain)
+	assert.NoError(t, err, "should not fail")
+	assert.Len(t, servers, 2)
+	assert.Equal(t, "192.0.2.1", servers[0].IP.String(), "master servers should be sorted by id")
+}
+
+func newPagedZoneHandler(pageCount int, failures map[int]int) func(req *http.Request) string {
+	var mutex sync.Mutex
+	return func(req *http.Request) string {
+		var params map[string]interface{}
+		_ = json.NewDecoder(req.Body).Decode(&params)
+
+		switch req.URL.Path {
+		case zonePageCountURL:
+			return strconv.Itoa(pageCount)
+		case zoneListURL:
+			page := int(params["page"].(float64))
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if failures[page] > 0 {
+				failures[page]--
+				return `{"status":"Failed","statusDescription":"temporary failure"}`
+			}
+
+			return fmt.Sprintf(`[{"name":"page%d.example","type":"master","zone":"domain","status":"1"}]`, page)
+		}
+		return `{"status":"Failed"}`
+	}
+}
+
+func TestZoneService_SearchWithOptions_Retry(t *testing.T) {
+	// given
+	stubClient := newStubClient(t, newPagedZoneHandler(3, map[int]int{2: 1}))
+
+	// when
+	zones, err := stubClient.Zones.SearchWithOptions(context.Background(), "", 0, ZoneSearchOptions{PageRetries: 1})
+
+	// then
+	assert.NoError(t, err)
+	assert.Len(t, zones, 3, "failed page should have been retried")
+}
+
+func TestZoneService_SearchWithOptions_Partial(t *testing.T) {
+	// given
+	stubClient := newStubClient(t, newPagedZoneHandler(3, map[int]int{2: 2}))
+
+	// when
+	zones, err := stubClient.Zones.SearchWithOptions(context.Background(), "", 0, ZoneSearchOptions{PageRetries: 1, AllowPartial: true})
+
+	// then
+	var partialErr *PartialResultError
+	assert.True(t, errors.As(err, &partialErr), "should return partial result error")
+	assert.ErrorIs(t, err, ErrAPIInvocation)
+	assert.Equal(t, 3, partialErr.TotalPages)
+	assert.Equal(t, []int{2}, partialErr.FailedPages)
+	assert.Equal(t, []string{"page1.example", "page3.example"}, []string{zones[0].Name, zones[1].Name})
+}
+
+func TestZoneService_Search_PageFailure(t *testing.T) {
+	stubClient := newStubClient(t, newPagedZoneHandler(3, map[int]int{2: 1}))
+
+	zones, err := stubClient.Zones.Search(context.Background(), "", 0)
+	assert.ErrorIs(t, err, ErrAPIInvocation)
+	assert.Nil(t, zones, "search should abort without partial results by default")
+}
+
+// newConcurrentZoneHandler returns a paged zone handler which blocks every page request until the expected amount of
+// page requests is in flight at the same time, failing the test if that never happens. The highest amount of page
+// requests in flight is stored in maxInFlight.
+func newConcurrentZoneHandler(t *testing.T, pageCount, expectedInFlight int, maxInFlight *int) func(req *http.Request) string {
+	var mutex sync.Mutex
+	var inFlight int
+	var reachedOnce sync.Once
+	reached := make(chan struct{})
+	pagedHandler := newPagedZoneHandler(pageCount, nil)
+
+	return func(req *http.Request) string {
+		if req.URL.Path != zoneListURL {
+			return pagedHandler(req)
+		}
+
+		mutex.Lock()
+		inFlight++
+		if inFlight > *maxInFlight {
+			*maxInFlight = inFlight
+		}
+		if inFlight == expectedInFlight {
+			reachedOnce.Do(func() { close(reached) })
+		}
+		mutex.Unlock()
+
+		select {
+		case <-reached:
+		case <-time.After(5 * time.Second):
+			t.Errorf("expected %d page requests in flight", expectedInFlight)
+		}
+
+		mutex.Lock()
+		inFlight--
+		mutex.Unlock()
+
+		return pagedHandler(req)
+	}
+}
+
+func TestZoneService_Search_Concurrency(t *testing.T) {
+	// given
+	var maxInFlight int
+	stubClient := newStubClient(t, newConcurrentZoneHandler(t, 10, DefaultZoneSearchConcurrency, &maxInFlight))
+
+	// when
+	zones, err := stubClient.Zones.Search(context.Background(), "", 0)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultZoneSearchConcurrency, maxInFlight, "pages should be fetched concurrently by default")
+	assert.Len(t, zones, 10)
+}
+
+func TestZoneService_SearchWithOptions_Concurrency(t *testing.T) {
+	// given
+	var maxInFlight int
+	stubClient := newStubClient(t, newConcurrentZoneHandler(t, 10, 3, &maxInFlight))
+
+	// when
+	zones, err := stubClient.Zones.SearchWithOptions(context.Background(), "", 0, ZoneSearchOptions{Concurrency: 3})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 3, maxInFlight, "should fetch pages concurrently without exceeding concurrency")
+	assert.Len(t, zones, 10)
+	for i, zone := range zones {
+		assert.Equal(t, fmt.Sprintf("page%d.example", i+1), zone.Name, "zones should be ordered by page")
+	}
+}
+
+func TestZoneService_SearchWithOptions_ConcurrencyFailure(t *testing.T) {
+	stubClient := newStubClient(t, newPagedZoneHandler(10, map[int]int{7: 1}))
+
+	zones, err := stubClient.Zones.SearchWithOptions(context.Background(), "", 0, ZoneSearchOptions{Concurrency: 4})
+	assert.ErrorIs(t, err, ErrAPIInvocation, "should return error of failed page instead of cancellation")
+	assert.Nil(t, zones)
+}
+
+func TestZoneKindFromName(t *testing.T) {
+	tests := map[string]ZoneKind{
+		"api-example.com":                  ZoneKindDomain,
+		"api-example.com.":                 ZoneKindDomain,
+		"2.0.192.in-addr.arpa":             ZoneKindIPv4,
+		"0/25.2.0.192.IN-ADDR.ARPA.":       ZoneKindIPv4,
+		"8.b.d.0.1.0.0.2.ip6.arpa":         ZoneKindIPv6,
+		"256.0.192.in-addr.arpa":           ZoneKindUnknown,
+		"1.2.3.4.5.in-addr.arpa":           ZoneKindUnknown,
+		"db8.2001.ip6.arpa":                ZoneKindUnknown,
+		"in-addr.arpa":                     ZoneKindUnknown,
+		"example.in-addr.arpa":             ZoneKindUnknown,
+		"0.0.0.0.0.0.0.0.0.0.0.0.ip6.arpa": ZoneKindIPv6,
+	}
+
+	for name, expected := range tests {
+		assert.Equal(t, expected, ZoneKindFromName(name), "unexpected kind for %s", name)
+	}
+}
+
+func TestZoneService_VerifyKind(t *testing.T) {
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `{"name":"2.0.192.in-addr.arpa","type":"master","zone":"domain","status":"1"}`
+	})
+
+	zone, err := stubClient.Zones.VerifyKind(context.Background(), "2.0.192.in-addr.arpa")
+	assert.ErrorIs(t, err, ErrZoneKindMismatch)
+	assert.Equal(t, ZoneKindDomain, zone.Kind)
+	assert.Contains(t, err.Error(), "domain instead of ipv4")
+}
+
+func TestZoneService_Iterate(t *testing.T) {
+	// given
+	stubClient := newStubClient(t, newPagedZoneHandler(3, nil))
+
+	// when
+	var names []string
+	for zone, err := range stubClient.Zones.Iterate(context.Background(), "", 0) {
+		assert.NoError(t, err)
+		names = append(names, zone.Name)
+	}
+
+	// then
+	assert.Equal(t, []string{"page1.example", "page2.example", "page3.example"}, names)
+}
+
+func TestZoneService_Iterate_Break(t *testing.T) {
+	// given
+	var requests []string
+	handler := newPagedZoneHandler(3, nil)
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		requests = append(requests, req.URL.Path)
+		return handler(req)
+	})
+
+	// when
+	for range stubClient.Zones.Iterate(context.Background(), "", 0) {
+		break
+	}
+
+	// then
+	assert.Equal(t, []string{zonePageCountURL, zoneListURL}, requests, "remaining pages should not be fetched")
+}
+
+func TestZoneService_Iterate_Error(t *testing.T) {
+	// given
+	stubClient := newStubClient(t, newPagedZoneHandler(3, map[int]int{2: 1}))
+
+	// when
+	var names []string
+	var errs []error
+	for zone, err := range stubClient.Zones.Iterate(context.Background(), "", 0) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		names = append(names, zone.Name)
+	}
+
+	// then
+	assert.Equal(t, []string{"page1.example"}, names)
+	assert.Len(t, errs, 1, "iteration should stop after the first error")
+	assert.ErrorIs(t, errs[0], ErrAPIInvocation)
+}
@@ -0,0 +1,233 @@
+package cloudns
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// DocumentVersion is the version of the document schemas, which is incremented on every incompatible change. Fields
+// might be added to documents without incrementing the version.
+const DocumentVersion = 1
+
+// Document is a versioned envelope for emitting library types to external systems like webhooks or queues. Documents
+// use a stable JSON representation which is independent of the wire format of the ClouDNS API, see MarshalDocument.
+type Document struct {
+	// Kind is the kind of the contained data, e.g. `record` or `zone`
+	Kind    string      `json:"kind"`
+	Version int         `json:"version"`
+	Data    interface{} `json:"data"`
+}
+
+// RecordDocument is the stable JSON representation of a Record. Params contains the type-specific parameters of the
+// record, e.g. `weight` and `port` for SRV records.
+type RecordDocument struct {
+	ID               int                    `json:"id,omitempty"`
+	Host             string                 `json:"host"`
+	Type             RecordType             `json:"type"`
+	Value            string                 `json:"value"`
+	TTL              int                    `json:"ttl"`
+	Active           bool                   `json:"active"`
+	GeoDNSLocationID int                    `json:"geodnsLocationId,omitempty"`
+	Params           map[string]interface{} `json:"params,omitempty"`
+}
+
+// ZoneDocument is the stable JSON representation of a Zone
+type ZoneDocument struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Kind   string `json:"kind"`
+	Active bool   `json:"active"`
+}
+
+// ZoneRecordsDocument is the stable JSON representation of all records of a zone, see RecordMap.Export
+type ZoneRecordsDocument struct {
+	Zone    string           `json:"zone"`
+	Records []RecordDocument `json:"records"`
+}
+
+// RecordChangeDocument is the stable JSON representation of a RecordChange
+type RecordChangeDocument struct {
+	Type   string         `json:"type"`
+	ID     int            `json:"id,omitempty"`
+	Record RecordDocument `json:"record"`
+}
+
+// SyncPlanDocument is the stable JSON representation of a SyncPlan
+type SyncPlanDocument struct {
+	Changes []RecordChangeDocument `json:"changes"`
+	Applied []RecordChangeDocument `json:"applied"`
+}
+
+// AccountEventDocument is the stable JSON representation of an AccountEvent
+type AccountEventDocument struct {
+	Type          string                `json:"type"`
+	Zone          string                `json:"zone,omitempty"`
+	DetectedAt    time.Time             `json:"detectedAt"`
+	Change        *RecordChangeDocument `json:"change,omitempty"`
+	RecordID      int                   `json:"recordId,omitempty"`
+	FailoverState string                `json:"failoverState,omitempty"`
+	Error         string                `json:"error,omitempty"`
+}
+
+// MarshalDocument returns the versioned JSON document of a Record, Zone, RecordChange, SyncPlan or AccountEvent, which
+// fails with ErrIllegalArgument for all other types
+func MarshalDocument(value interface{}) ([]byte, error) {
+	var document Document
+	switch value := value.(type) {
+	case Record:
+		document = Document{Kind: "record", Data: value.Document()}
+	case Zone:
+		document = Document{Kind: "zone", Data: value.Document()}
+	case RecordChange:
+		document = Document{Kind: "record-change", Data: value.Document()}
+	case SyncPlan:
+		document = Document{Kind: "sync-plan", Data: value.Document()}
+	case AccountEvent:
+		document = Document{Kind: "account-event", Data: value.Document()}
+	default:
+		return nil, ErrIllegalArgument.wrap(fmt.Errorf("type %T can not be marshalled as document", value))
+	}
+
+	document.Version = DocumentVersion
+	return json.Marshal(document)
+}
+
+// Document returns the stable representation of the record for external systems
+func (rec Record) Document() RecordDocument {
+	document := RecordDocument{
+		ID:               rec.ID,
+		Host:             rec.Host,
+		Type:             rec.RecordType,
+		Value:            rec.Record,
+		TTL:              rec.TTL,
+		Active:           bool(rec.IsActive),
+		GeoDNSLocationID: rec.GeoDNSLocationID,
+	}
+
+	switch rec.RecordType {
+	case RecordTypeMX:
+		document.Params = map[string]interface{}{"priority": rec.Priority}
+	case RecordTypeSRV:
+		document.Params = map[string]interface{}{"priority": rec.Priority, "weight": rec.SRV.Weight, "port": rec.SRV.Port}
+	case RecordTypeWebRedirect:
+		document.Params = map[string]interface{}{
+			"redirectType":     rec.WebRedirect.RedirectType,
+			"savePath":         bool(rec.WebRedirect.SavePath),
+			"mobileMeta":       bool(rec.WebRedirect.MobileMeta),
+			"frame":            bool(rec.WebRedirect.IsFrame),
+			"frameTitle":       rec.WebRedirect.FrameTitle,
+			"frameKeywords":    rec.WebRedirect.FrameKeywords,
+			"frameDescription": rec.WebRedirect.FrameDescription,
+		}
+	case RecordTypeRP:
+		document.Params = map[string]interface{}{"mail": rec.RP.Mail, "txt": rec.RP.TXT}
+	case RecordTypeSSHFP:
+		document.Params = map[string]interface{}{"algorithm": rec.SSHFP.Algorithm, "fingerprintType": rec.SSHFP.Type}
+	case RecordTypeTLSA:
+		document.Params = map[string]interface{}{
+			"usage":        rec.TLSA.Usage,
+			"selector":     rec.TLSA.Selector,
+			"matchingType": rec.TLSA.MatchingType,
+		}
+	case RecordTypeCAA:
+		document.Params = map[string]interface{}{"flag": rec.CAA.Flag, "tag": rec.CAA.Type, "value": rec.CAA.Value}
+	case RecordTypeNAPTR:
+		document.Params = map[string]interface{}{
+			"order":       rec.NAPTR.Order,
+			"preference":  rec.NAPTR.Preference,
+			"flags":       rec.NAPTR.Flags,
+			"service":     rec.NAPTR.Service,
+			"regexp":      rec.NAPTR.Regexp,
+			"replacement": rec.NAPTR.Replacement,
+		}
+	case RecordTypeSMIMEA:
+		document.Params = map[string]interface{}{
+			"usage":        rec.SMIMEA.Usage,
+			"selector":     rec.SMIMEA.Selector,
+			"matchingType": rec.SMIMEA.MatchingType,
+		}
+	case RecordTypeDS:
+		document.Params = map[string]interface{}{
+			"keyTag":     rec.DS.KeyTag,
+			"algorithm":  rec.DS.Algorithm,
+			"digestType": rec.DS.DigestType,
+		}
+	case RecordTypeCERT:
+		document.Params = map[string]interface{}{
+			"certType":  rec.CERT.Type,
+			"keyTag":    rec.CERT.KeyTag,
+			"algorithm": rec.CERT.Algorithm,
+		}
+	case RecordTypeHINFO:
+		document.Params = map[string]interface{}{"cpu": rec.HINFO.CPU, "os": rec.HINFO.OS}
+	case RecordTypeLOC:
+		document.Params = map[string]interface{}{
+			"latitudeDegrees":     rec.LOC.LatitudeDegrees,
+			"latitudeMinutes":     rec.LOC.LatitudeMinutes,
+			"latitudeSeconds":     rec.LOC.LatitudeSeconds,
+			"latitudeDirection":   rec.LOC.LatitudeDirection,
+			"longitudeDegrees":    rec.LOC.LongitudeDegrees,
+			"longitudeMinutes":    rec.LOC.LongitudeMinutes,
+			"longitudeSeconds":    rec.LOC.LongitudeSeconds,
+			"longitudeDirection":  rec.LOC.LongitudeDirection,
+			"altitude":            rec.LOC.Altitude,
+			"size":                rec.LOC.Size,
+			"horizontalPrecision": rec.LOC.HorizontalPrecision,
+			"verticalPrecision":   rec.LOC.VerticalPrecision,
+		}
+	}
+
+	return document
+}
+
+// Document returns the stable representation of the zone for external systems
+func (zone Zone) Document() ZoneDocument {
+	return ZoneDocument{
+		Name:   zone.Name,
+		Type:   zone.Type.String(),
+		Kind:   zone.Kind.String(),
+		Active: bool(zone.IsActive),
+	}
+}
+
+// Document returns the stable representation of the change for external systems
+func (change RecordChange) Document() RecordChangeDocument {
+	return RecordChangeDocument{Type: change.Type.String(), ID: change.ID, Record: change.Record.Document()}
+}
+
+// Document returns the stable representation of the plan for external systems
+func (plan SyncPlan) Document() SyncPlanDocument {
+	document := SyncPlanDocument{
+		Changes: make([]RecordChangeDocument, 0, len(plan.Changes)),
+		Applied: make([]RecordChangeDocument, 0, len(plan.Applied)),
+	}
+	for _, change := range plan.Changes {
+		document.Changes = append(document.Changes, change.Document())
+	}
+	for _, change := range plan.Applied {
+		document.Applied = append(document.Applied, change.Document())
+	}
+
+	return document
+}
+
+// Document returns the stable representation of the event for external systems
+func (event AccountEvent) Document() AccountEventDocument {
+	document := AccountEventDocument{Type: event.Type.String(), Zone: event.Zone, DetectedAt: event.DetectedAt}
+
+	switch event.Type {
+	case AccountEventRecordChanged:
+		change := event.Change.Document()
+		document.Change = &change
+	case AccountEventFailoverChanged:
+		document.RecordID = event.RecordID
+		document.FailoverState = event.FailoverState.String()
+	case AccountEventError:
+		if event.Error != nil {
+			document.Error = event.Error.Error()
+		}
+	}
+
+	return document
+}
@@ -0,0 +1,72 @@
+package cloudns
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestMarshalDocument_Record(t *testing.T) {
+	// given
+	record := NewRecordSRV("_sip._tcp", 10, 20, 5060, "sip.api-example.com", 3600)
+	record.ID = 42
+	record.IsActive = true
+
+	// when
+	data, err := MarshalDocument(record)
+
+	// then
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"kind":"record","version":1,"data":{
+		"id":42,"host":"_sip._tcp","type":"SRV","value":"sip.api-example.com","ttl":3600,"active":true,
+		"params":{"priority":10,"weight":20,"port":5060}
+	}}`, string(data))
+}
+
+func TestMarshalDocument_Zone(t *testing.T) {
+	zone := Zone{Name: "api-example.com", Type: ZoneTypeMaster, Kind: ZoneKindDomain, IsActive: true}
+
+	data, err := MarshalDocument(zone)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"kind":"zone","version":1,"data":{
+		"name":"api-example.com","type":"master","kind":"domain","active":true
+	}}`, string(data))
+}
+
+func TestMarshalDocument_SyncPlan(t *testing.T) {
+	plan := SyncPlan{Changes: []RecordChange{
+		{Type: RecordChangeDelete, ID: 3, Record: NewRecordA("old", "192.0.2.1", 60)},
+	}}
+
+	data, err := MarshalDocument(plan)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"kind":"sync-plan","version":1,"data":{"applied":[],"changes":[
+		{"type":"delete","id":3,"record":{"host":"old","type":"A","value":"192.0.2.1","ttl":60,"active":true}}
+	]}}`, string(data))
+}
+
+func TestMarshalDocument_AccountEvent(t *testing.T) {
+	detectedAt := time.Date(2022, 12, 24, 0, 0, 0, 0, time.UTC)
+	failoverEvent := AccountEvent{Type: AccountEventFailoverChanged, Zone: testDomain, DetectedAt: detectedAt,
+		RecordID: 7, FailoverState: FailoverStateDown}
+	errorEvent := AccountEvent{Type: AccountEventError, DetectedAt: detectedAt, Error: errors.New("boom")}
+
+	failoverData, failoverErr := MarshalDocument(failoverEvent)
+	errorData, errorErr := MarshalDocument(errorEvent)
+
+	assert.NoError(t, failoverErr)
+	assert.JSONEq(t, `{"kind":"account-event","version":1,"data":{
+		"type":"failover-changed","zone":"`+testDomain+`","detectedAt":"2022-12-24T00:00:00Z",
+		"recordId":7,"failoverState":"down"
+	}}`, string(failoverData))
+	assert.NoError(t, errorErr)
+	assert.JSONEq(t, `{"kind":"account-event","version":1,"data":{
+		"type":"error","detectedAt":"2022-12-24T00:00:00Z","error":"boom"
+	}}`, string(errorData))
+}
+
+func TestMarshalDocument_Unsupported(t *testing.T) {
+	_, err := MarshalDocument(SOA{})
+	assert.ErrorIs(t, err, ErrIllegalArgument)
+}
@@ -0,0 +1,258 @@
+package cloudns
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const domainCheckAvailableURL = "/domains/check-available.json"
+const domainRegisterURL = "/domains/order-new-domain.json"
+const domainRenewURL = "/domains/order-renew-domain.json"
+const domainTransferURL = "/domains/order-transfer-domain.json"
+const domainListURL = "/domains/list-domains.json"
+const domainInfoURL = "/domains/domain-info.json"
+const domainTransferCodeURL = "/domains/get-transfer-code.json"
+const domainContactsGetURL = "/domains/get-contacts.json"
+const domainContactsSetURL = "/domains/set-contacts.json"
+const domainPrivacyURL = "/domains/edit-privacy-protection.json"
+const domainRowsPerPage = 100
+
+// DomainService is a service object which groups all operations related to ClouDNS domain registration. All ordering
+// methods are charged to the balance of the account.
+type DomainService struct {
+	api *Client
+}
+
+// DomainAvailability represents the registration availability of a single domain
+type DomainAvailability struct {
+	Domain    string
+	Available bool
+}
+
+// DomainContact represents a WHOIS contact of a registered domain
+type DomainContact struct {
+	Mail    string `json:"mail"`
+	Name    string `json:"name"`
+	Company string `json:"company"`
+	Address string `json:"address"`
+	City    string `json:"city"`
+	State   string `json:"state"`
+	Zip     string `json:"zip"`
+	// Country is the ISO 3166-1 alpha-2 code of the country, e.g. `DE`
+	Country string `json:"country"`
+	// PhoneCountryCode is the international calling code without any prefix, e.g. `49`
+	PhoneCountryCode string `json:"telnocc"`
+	Phone            string `json:"telno"`
+	FaxCountryCode   string `json:"faxnocc"`
+	Fax              string `json:"faxno"`
+}
+
+// DomainContactType is an enumeration of the WHOIS contacts of a registered domain
+type DomainContactType string
+
+// Enumeration values for DomainContactType
+const (
+	DomainContactRegistrant DomainContactType = "registrant"
+	DomainContactAdmin      DomainContactType = "admin"
+	DomainContactTech       DomainContactType = "tech"
+	DomainContactBilling    DomainContactType = "billing"
+)
+
+// DomainContacts represents all WHOIS contacts of a registered domain
+type DomainContacts struct {
+	Registrant DomainContact `json:"registrant"`
+	Admin      DomainContact `json:"admin"`
+	Tech       DomainContact `json:"tech"`
+	Billing    DomainContact `json:"billing"`
+}
+
+// DomainOrder specifies a domain which gets registered or transferred
+type DomainOrder struct {
+	// Name is the name of the domain without its TLD, e.g. `example` for `example.com`
+	Name string
+	TLD  string
+	// Period is the registration period in years
+	Period int
+	// Contact is used as registrant, admin, tech and billing contact
+	Contact DomainContact
+	// Nameservers are set at the registry, using the default ClouDNS nameservers if empty
+	Nameservers []string
+	// TransferCode is the EPP code provided by the current registrar, only used for transfers
+	TransferCode string
+}
+
+// RegisteredDomain represents a domain registered through ClouDNS
+type RegisteredDomain struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	// CreationDate and ExpirationDate are formatted as `YYYY-MM-DD`
+	CreationDate   string   `json:"creation_date"`
+	ExpirationDate string   `json:"expiration_date"`
+	Nameservers    []string `json:"nameservers"`
+	WhoisPrivacy   APIBool  `json:"privacy_protection"`
+}
+
+// CheckAvailability checks whether the domain with the given name is available for registration with each of the given
+// TLDs, e.g. `com` and `net`
+func (svc *DomainService) CheckAvailability(ctx context.Context, name string, tlds ...string) ([]DomainAvailability, error) {
+	var result map[string]struct {
+		Status string `json:"status"`
+	}
+
+	params := HTTPParams{"name": name, "tld": tlds}
+	if err := svc.api.request(ctx, "POST", domainCheckAvailableURL, params, nil, &result); err != nil {
+		return nil, err
+	}
+
+	availability := make([]DomainAvailability, 0, len(result))
+	for domain, status := range result {
+		availability = append(availability, DomainAvailability{Domain: domain, Available: status.Status == "1"})
+	}
+	sort.Slice(availability, func(i, j int) bool {
+		return availability[i].Domain < availability[j].Domain
+	})
+
+	return availability, nil
+}
+
+// Register orders the registration of a new domain
+func (svc *DomainService) Register(ctx context.Context, order DomainOrder) (result StatusResult, err error) {
+	err = svc.api.request(ctx, "POST", domainRegisterURL, order.asParams(), nil, &result)
+	return
+}
+
+// Transfer orders the transfer of a domain from another registrar, which requires DomainOrder.TransferCode
+func (svc *DomainService) Transfer(ctx context.Context, order DomainOrder) (result StatusResult, err error) {
+	params := order.asParams()
+	params["transfer-code"] = order.TransferCode
+
+	err = svc.api.request(ctx, "POST", domainTransferURL, params, nil, &result)
+	return
+}
+
+// Renew orders the renewal of a registered domain for the given period in years
+func (svc *DomainService) Renew(ctx context.Context, domainName string, period int) (result StatusResult, err error) {
+	params := HTTPParams{"domain-name": domainName, "period": period}
+	err = svc.api.request(ctx, "POST", domainRenewURL, params, nil, &result)
+	return
+}
+
+// List returns all domains registered through ClouDNS sorted by their name
+func (svc *DomainService) List(ctx context.Context) ([]RegisteredDomain, error) {
+	var domains []RegisteredDomain
+	for page := 1; ; page++ {
+		var result map[string]RegisteredDomain
+
+		params := HTTPParams{"page": page, "rows-per-page": domainRowsPerPage}
+		if err := svc.api.request(ctx, "POST", domainListURL, params, nil, &result); err != nil {
+			return nil, err
+		}
+
+		for _, domain := range result {
+			domains = append(domains, domain)
+		}
+		if len(result) < domainRowsPerPage {
+			break
+		}
+	}
+
+	sort.Slice(domains, func(i, j int) bool {
+		return domains[i].Name < domains[j].Name
+	})
+	return domains, nil
+}
+
+// Get returns the details of a domain registered through ClouDNS
+func (svc *DomainService) Get(ctx context.Context, domainName string) (result RegisteredDomain, err error) {
+	params := HTTPParams{"domain-name": domainName}
+	err = svc.api.request(ctx, "POST", domainInfoURL, params, nil, &result)
+	return
+}
+
+// CheckNameservers compares the nameservers of a domain registered through ClouDNS with the NS records of its zone, see
+// ZoneService.CheckNameservers
+func (svc *DomainService) CheckNameservers(ctx context.Context, domainName string) (NameserverReport, error) {
+	domain, err := svc.Get(ctx, domainName)
+	if err != nil {
+		return NameserverReport{}, err
+	}
+
+	return svc.api.Zones.CheckNameservers(ctx, domainName, domain.Nameservers)
+}
+
+// GetTransferCode returns the EPP code of a registered domain, which is required for transferring it to another
+// registrar
+func (svc *DomainService) GetTransferCode(ctx context.Context, domainName string) (string, error) {
+	var result struct {
+		TransferCode string `json:"transfer_code"`
+	}
+
+	params := HTTPParams{"domain-name": domainName}
+	err := svc.api.request(ctx, "POST", domainTransferCodeURL, params, nil, &result)
+	return result.TransferCode, err
+}
+
+// GetContacts returns all WHOIS contacts of a registered domain
+func (svc *DomainService) GetContacts(ctx context.Context, domainName string) (result DomainContacts, err error) {
+	params := HTTPParams{"domain-name": domainName}
+	err = svc.api.request(ctx, "POST", domainContactsGetURL, params, nil, &result)
+	return
+}
+
+// UpdateContact replaces a single WHOIS contact of a registered domain. Some registries charge a fee or require a
+// confirmation by the previous registrant when changing the registrant contact.
+func (svc *DomainService) UpdateContact(ctx context.Context, domainName string, contactType DomainContactType, contact DomainContact) (result StatusResult, err error) {
+	switch contactType {
+	case DomainContactRegistrant, DomainContactAdmin, DomainContactTech, DomainContactBilling:
+	default:
+		return result, ErrIllegalArgument.wrap(fmt.Errorf("unknown contact type: %s", contactType))
+	}
+
+	params := contact.asParams()
+	params["domain-name"] = domainName
+	params["type"] = string(contactType)
+
+	err = svc.api.request(ctx, "POST", domainContactsSetURL, params, nil, &result)
+	return
+}
+
+// SetWhoisPrivacy enables or disables the WHOIS privacy protection of a registered domain, which hides its contacts
+// from public WHOIS queries. Not all TLDs support privacy protection.
+func (svc *DomainService) SetWhoisPrivacy(ctx context.Context, domainName string, isEnabled bool) (result StatusResult, err error) {
+	params := HTTPParams{"domain-name": domainName}
+	if isEnabled {
+		params["status"] = 1
+	} else {
+		params["status"] = 0
+	}
+
+	err = svc.api.request(ctx, "POST", domainPrivacyURL, params, nil, &result)
+	return
+}
+
+func (order DomainOrder) asParams() HTTPParams {
+	params := HTTPParams{
+		"domain-name": strings.ToLower(order.Name),
+		"tld":         strings.TrimPrefix(strings.ToLower(order.TLD), "."),
+		"period":      order.Period,
+	}
+	if len(order.Nameservers) > 0 {
+		params["ns"] = order.Nameservers
+	}
+	copyParams(params, order.Contact.asParams())
+
+	return params
+}
+
+// asParams returns the fields of the contact as API parameters, which share their names with the JSON fields
+func (contact DomainContact) asParams() HTTPParams {
+	params := make(HTTPParams)
+	if data, err := json.Marshal(contact); err == nil {
+		_ = json.Unmarshal(data, &params)
+	}
+
+	return params
+}
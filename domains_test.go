@@ -0,0 +1,255 @@
+package cloudns
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestDomainService_CheckAvailability(t *testing.T) {
+	// given
+	var params map[string]interface{}
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		_ = json.NewDecoder(req.Body).Decode(&params)
+		return `{"example.net":{"status":"2"},"example.com":{"status":"1"}}`
+	})
+
+	// when
+	availability, err := stubClient.Domains.CheckAvailability(context.Background(), "example", "com", "net")
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []DomainAvailability{
+		{Domain: "example.com", Available: true},
+		{Domain: "example.net", Available: false},
+	}, availability)
+	assert.Equal(t, "example", params["name"])
+	assert.Equal(t, []interface{}{"com", "net"}, params["tld"])
+}
+
+func TestDomainService_Register(t *testing.T) {
+	// given
+	var params map[string]interface{}
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		_ = json.NewDecoder(req.Body).Decode(&params)
+		return `{"status":"Success","statusDescription":"The domain was registered successfully."}`
+	})
+
+	// when
+	result, err := stubClient.Domains.Register(context.Background(), DomainOrder{
+		Name:        "Example",
+		TLD:         ".com",
+		Period:      1,
+		Contact:     DomainContact{Mail: "admin@example.net", Name: "Jane Doe", Country: "DE"},
+		Nameservers: []string{"ns1.example.net", "ns2.example.net"},
+	})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "Success", result.Status)
+	assert.Equal(t, "example", params["domain-name"])
+	assert.Equal(t, "com", params["tld"])
+	assert.EqualValues(t, 1, params["period"])
+	assert.Equal(t, "admin@example.net", params["mail"])
+	assert.Equal(t, "Jane Doe", params["name"])
+	assert.Equal(t, "DE", params["country"])
+	assert.Equal(t, []interface{}{"ns1.example.net", "ns2.example.net"}, params["ns"])
+	assert.NotContains(t, params, "transfer-code")
+}
+
+func TestDomainService_Transfer(t *testing.T) {
+	// given
+	var path string
+	var params map[string]interface{}
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		path = req.URL.Path
+		_ = json.NewDecoder(req.Body).Decode(&params)
+		return `{"status":"Success","statusDescription":"The transfer was initiated successfully."}`
+	})
+
+	// when
+	_, err := stubClient.Domains.Transfer(context.Background(), DomainOrder{Name: "example", TLD: "com", Period: 1, TransferCode: "s3cr3t"})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, domainTransferURL, path)
+	assert.Equal(t, "s3cr3t", params["transfer-code"])
+}
+
+func TestDomainService_Register_NotRetried(t *testing.T) {
+	// given
+	attempts := 0
+	transport := stubTransport(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		return nil, fmt.Errorf("connection reset")
+	})
+	client, err := New(HTTPClient(&http.Client{Transport: transport}), Retries(RetryPolicy{MaxAttempts: 3}))
+	assert.NoError(t, err)
+
+	// when
+	_, err = client.Domains.Register(context.Background(), DomainOrder{Name: "example", TLD: "com", Period: 1})
+
+	// then
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts, "domain orders must never be retried")
+}
+
+func TestDomainService_List(t *testing.T) {
+	// given
+	var pages []interface{}
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		var params map[string]interface{}
+		_ = json.NewDecoder(req.Body).Decode(&params)
+		pages = append(pages, params["page"])
+
+		if len(pages) == 1 {
+			domains := make(map[string]RegisteredDomain)
+			for i := 0; i < domainRowsPerPage; i++ {
+				name := fmt.Sprintf("example%03d.com", i)
+				domains[name] = RegisteredDomain{Name: name}
+			}
+			data, _ := json.Marshal(domains)
+			return string(data)
+		}
+		return `{"a-example.com":{"name":"a-example.com","status":"active","expiration_date":"2030-01-01","privacy_protection":"1"}}`
+	})
+
+	// when
+	domains, err := stubClient.Domains.List(context.Background())
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{1.0, 2.0}, pages)
+	assert.Len(t, domains, domainRowsPerPage+1)
+	assert.Equal(t, RegisteredDomain{
+		Name: "a-example.com", Status: "active", ExpirationDate: "2030-01-01", WhoisPrivacy: true,
+	}, domains[0])
+}
+
+func TestDomainService_List_Empty(t *testing.T) {
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `[]`
+	})
+
+	domains, err := stubClient.Domains.List(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, domains)
+}
+
+func TestDomainService_CheckNameservers(t *testing.T) {
+	// given
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		switch req.URL.Path {
+		case domainInfoURL:
+			return `{"name":"example.com","nameservers":["ns1.cloudns.net","ns2.cloudns.net"]}`
+		case recordListURL:
+			return `{"1":{"id":"1","host":"","record":"ns1.cloudns.net","type":"NS","ttl":"3600","status":1}}`
+		default:
+			return `[{"type":"free","name":"ns1.cloudns.net"},{"type":"free","name":"ns2.cloudns.net"}]`
+		}
+	})
+
+	// when
+	report, err := stubClient.Domains.CheckNameservers(context.Background(), "example.com")
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ns1.cloudns.net", "ns2.cloudns.net"}, report.Registrar)
+	assert.Equal(t, []string{"ns2.cloudns.net"}, report.MissingInZone)
+	assert.False(t, report.IsConsistent())
+}
+
+func TestDomainService_GetTransferCode(t *testing.T) {
+	// given
+	var params map[string]interface{}
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		_ = json.NewDecoder(req.Body).Decode(&params)
+		return `{"transfer_code":"s3cr3t"}`
+	})
+
+	// when
+	code, err := stubClient.Domains.GetTransferCode(context.Background(), "example.com")
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cr3t", code)
+	assert.Equal(t, "example.com", params["domain-name"])
+}
+
+func TestDomainService_GetContacts(t *testing.T) {
+	// given
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `{
+			"registrant":{"mail":"owner@example.net","name":"Jane Doe","country":"DE","telnocc":"49","telno":"301234567"},
+			"admin":{"mail":"admin@example.net","name":"John Doe"},
+			"tech":{"mail":"tech@example.net"},
+			"billing":{"mail":"billing@example.net"}
+		}`
+	})
+
+	// when
+	contacts, err := stubClient.Domains.GetContacts(context.Background(), "example.com")
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, DomainContact{
+		Mail: "owner@example.net", Name: "Jane Doe", Country: "DE", PhoneCountryCode: "49", Phone: "301234567",
+	}, contacts.Registrant)
+	assert.Equal(t, "admin@example.net", contacts.Admin.Mail)
+	assert.Equal(t, "tech@example.net", contacts.Tech.Mail)
+	assert.Equal(t, "billing@example.net", contacts.Billing.Mail)
+}
+
+func TestDomainService_UpdateContact(t *testing.T) {
+	// given
+	var params map[string]interface{}
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		_ = json.NewDecoder(req.Body).Decode(&params)
+		return `{"status":"Success","statusDescription":"Contacts were updated successfully."}`
+	})
+
+	// when
+	contact := DomainContact{Mail: "admin@example.net", Name: "John Doe", PhoneCountryCode: "49"}
+	result, err := stubClient.Domains.UpdateContact(context.Background(), "example.com", DomainContactAdmin, contact)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "Success", result.Status)
+	assert.Equal(t, "example.com", params["domain-name"])
+	assert.Equal(t, "admin", params["type"])
+	assert.Equal(t, "admin@example.net", params["mail"])
+	assert.Equal(t, "49", params["telnocc"])
+}
+
+func TestDomainService_UpdateContact_UnknownType(t *testing.T) {
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		t.Error("request should not be sent")
+		return `{"status":"Success"}`
+	})
+
+	_, err := stubClient.Domains.UpdateContact(context.Background(), "example.com", "owner", DomainContact{})
+	assert.ErrorIs(t, err, ErrIllegalArgument)
+}
+
+func TestDomainService_SetWhoisPrivacy(t *testing.T) {
+	// given
+	var statuses []interface{}
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		var params map[string]interface{}
+		_ = json.NewDecoder(req.Body).Decode(&params)
+		statuses = append(statuses, params["status"])
+		return `{"status":"Success"}`
+	})
+
+	// when
+	_, enableErr := stubClient.Domains.SetWhoisPrivacy(context.Background(), "example.com", true)
+	_, disableErr := stubClient.Domains.SetWhoisPrivacy(context.Background(), "example.com", false)
+
+	// then
+	assert.NoError(t, enableErr)
+	assert.NoError(t, disableErr)
+	assert.Equal(t, []interface{}{1.0, 0.0}, statuses)
+}
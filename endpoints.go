@@ -0,0 +1,155 @@
+package cloudns
+
+// Endpoint describes a single ClouDNS API endpoint which is wrapped by cloudns-go
+type Endpoint struct {
+	// Service is the name of the service object providing the endpoint, e.g. `Records`
+	Service string
+	// Name is the name of the method of the service object wrapping the endpoint, e.g. `Create`
+	Name string
+	// Method is the HTTP method used for invoking the endpoint
+	Method string
+	// Path is the path of the endpoint relative to the base URL of the API
+	Path string
+	// Mutating is true if the endpoint modifies the contents or state of a zone, or places an order for the account.
+	// Requests against mutating endpoints are never cached, not retried after ambiguous failures and not sent at all for
+	// read-only clients or dry runs.
+	Mutating bool
+	// Cacheable is true if the endpoint returns rarely changing metadata, whose responses are cached by the
+	// ResponseCache option
+	Cacheable bool
+}
+
+// endpoints contains all API endpoints wrapped by cloudns-go, which must be extended when wrapping new endpoints.
+// Methods using multiple endpoints are listed once per endpoint.
+var endpoints = []Endpoint{
+	{Service: "Account", Name: "Login", Method: "POST", Path: accountLoginURL},
+	{Service: "Account", Name: "GetCurrentIP", Method: "POST", Path: accountCurrentIPURL},
+	{Service: "Account", Name: "GetBalance", Method: "POST", Path: accountBalanceURL},
+
+	{Service: "Zones", Name: "Search", Method: "POST", Path: zonePageCountURL},
+	{Service: "Zones", Name: "Search", Method: "POST", Path: zoneListURL},
+	{Service: "Zones", Name: "Create", Method: "POST", Path: zoneRegisterURL, Mutating: true},
+	{Service: "Zones", Name: "Delete", Method: "POST", Path: zoneDeleteURL, Mutating: true},
+	{Service: "Zones", Name: "Get", Method: "POST", Path: zoneGetURL},
+	{Service: "Zones", Name: "GetRecordCount", Method: "POST", Path: zoneRecordCountURL},
+	{Service: "Zones", Name: "MasterServers", Method: "POST", Path: zoneMasterServersURL},
+	{Service: "Zones", Name: "TriggerUpdate", Method: "POST", Path: zoneTriggerUpdateURL, Mutating: true},
+	{Service: "Zones", Name: "SetActive", Method: "POST", Path: zoneSetActiveURL, Mutating: true},
+	{Service: "Zones", Name: "IsUpdated", Method: "POST", Path: zoneIsUpdatedURL},
+	{Service: "Zones", Name: "GetUpdateStatus", Method: "POST", Path: zoneUpdateStatusURL},
+	{Service: "Zones", Name: "AvailableNameservers", Method: "POST", Path: zoneAvailableNameserversURL, Cacheable: true},
+	{Service: "Zones", Name: "GetUsage", Method: "POST", Path: zoneUsageURL},
+	{Service: "Zones", Name: "Statistics", Method: "POST", Path: zoneStatisticsHourlyURL},
+	{Service: "Zones", Name: "Statistics", Method: "POST", Path: zoneStatisticsDailyURL},
+	{Service: "Zones", Name: "Statistics", Method: "POST", Path: zoneStatisticsMonthlyURL},
+	{Service: "Zones", Name: "Statistics", Method: "POST", Path: zoneStatisticsYearlyURL},
+	{Service: "Zones", Name: "Statistics", Method: "POST", Path: zoneStatisticsLast30DaysURL},
+	{Service: "Zones", Name: "Groups", Method: "POST", Path: groupListURL},
+	{Service: "Zones", Name: "CreateGroup", Method: "POST", Path: groupCreateURL, Mutating: true},
+	{Service: "Zones", Name: "RenameGroup", Method: "POST", Path: groupRenameURL, Mutating: true},
+	{Service: "Zones", Name: "SetGroup", Method: "POST", Path: groupChangeURL, Mutating: true},
+	{Service: "Zones", Name: "DeleteGroup", Method: "POST", Path: groupDeleteURL, Mutating: true},
+	{Service: "Zones", Name: "TransferServers", Method: "POST", Path: transferListURL},
+	{Service: "Zones", Name: "AddTransferServer", Method: "POST", Path: transferAddURL, Mutating: true},
+	{Service: "Zones", Name: "RemoveTransferServer", Method: "POST", Path: transferRemoveURL, Mutating: true},
+	{Service: "Zones", Name: "ListCloudDomains", Method: "POST", Path: cloudDomainListURL},
+	{Service: "Zones", Name: "AddCloudDomain", Method: "POST", Path: cloudDomainAddURL, Mutating: true},
+	{Service: "Zones", Name: "DeleteCloudDomain", Method: "POST", Path: cloudDomainDeleteURL, Mutating: true},
+	{Service: "Zones", Name: "SetCloudMaster", Method: "POST", Path: cloudDomainSetMasterURL, Mutating: true},
+
+	{Service: "Records", Name: "GetSOA", Method: "POST", Path: recordSOAGetURL},
+	{Service: "Records", Name: "UpdateSOA", Method: "POST", Path: recordSOAUpdateURL, Mutating: true},
+	{Service: "Records", Name: "Search", Method: "POST", Path: recordListURL},
+	{Service: "Records", Name: "Create", Method: "POST", Path: recordCreateURL, Mutating: true},
+	{Service: "Records", Name: "Update", Method: "POST", Path: recordUpdateURL, Mutating: true},
+	{Service: "Records", Name: "Delete", Method: "POST", Path: recordDeleteURL, Mutating: true},
+	{Service: "Records", Name: "SetActive", Method: "POST", Path: recordSetActiveURL, Mutating: true},
+	{Service: "Records", Name: "CopyFromZone", Method: "POST", Path: recordCopyFromZoneURL, Mutating: true},
+	{Service: "Records", Name: "Import", Method: "POST", Path: recordImportURL, Mutating: true},
+	{Service: "Records", Name: "ImportTransfer", Method: "POST", Path: recordImportTransferURL, Mutating: true},
+	{Service: "Records", Name: "Export", Method: "POST", Path: recordExportURL},
+	{Service: "Records", Name: "GetDynamicURL", Method: "POST", Path: recordGetDynamicURL},
+	{Service: "Records", Name: "ChangeDynamicURL", Method: "POST", Path: recordChangeDynamicURL, Mutating: true},
+	{Service: "Records", Name: "DisableDynamicURL", Method: "POST", Path: recordDisableDynamicURL, Mutating: true},
+	{Service: "Records", Name: "AvailableTTLs", Method: "POST", Path: recordAvailableTTLsURL, Cacheable: true},
+	{Service: "Records", Name: "AvailableRecordTypes", Method: "POST", Path: recordAvailableRecordTypesURL, Cacheable: true},
+	{Service: "Records", Name: "GeoDNSLocations", Method: "POST", Path: geoDNSLocationsURL, Cacheable: true},
+
+	{Service: "DNSSEC", Name: "IsAvailable", Method: "POST", Path: dnssecAvailableURL},
+	{Service: "DNSSEC", Name: "Activate", Method: "POST", Path: dnssecActivateURL, Mutating: true},
+	{Service: "DNSSEC", Name: "GetDSRecords", Method: "POST", Path: dnssecDSRecordsURL},
+	{Service: "DNSSEC", Name: "SetOptOut", Method: "POST", Path: dnssecOptOutURL, Mutating: true},
+	{Service: "DNSSEC", Name: "Deactivate", Method: "POST", Path: dnssecDeactivateURL, Mutating: true},
+
+	{Service: "Failover", Name: "Activate", Method: "POST", Path: failoverActivateURL, Mutating: true},
+	{Service: "Failover", Name: "Get", Method: "POST", Path: failoverSettingsURL},
+	{Service: "Failover", Name: "Update", Method: "POST", Path: failoverModifyURL, Mutating: true},
+	{Service: "Failover", Name: "GetState", Method: "POST", Path: failoverStateURL},
+	{Service: "Failover", Name: "Deactivate", Method: "POST", Path: failoverDeactivateURL, Mutating: true},
+
+	{Service: "MailForwards", Name: "List", Method: "POST", Path: mailForwardListURL},
+	{Service: "MailForwards", Name: "Create", Method: "POST", Path: mailForwardCreateURL, Mutating: true},
+	{Service: "MailForwards", Name: "Delete", Method: "POST", Path: mailForwardDeleteURL, Mutating: true},
+
+	{Service: "Domains", Name: "CheckAvailability", Method: "POST", Path: domainCheckAvailableURL},
+	{Service: "Domains", Name: "Register", Method: "POST", Path: domainRegisterURL, Mutating: true},
+	{Service: "Domains", Name: "Transfer", Method: "POST", Path: domainTransferURL, Mutating: true},
+	{Service: "Domains", Name: "Renew", Method: "POST", Path: domainRenewURL, Mutating: true},
+	{Service: "Domains", Name: "List", Method: "POST", Path: domainListURL},
+	{Service: "Domains", Name: "Get", Method: "POST", Path: domainInfoURL},
+	{Service: "Domains", Name: "GetTransferCode", Method: "POST", Path: domainTransferCodeURL},
+	{Service: "Domains", Name: "GetContacts", Method: "POST", Path: domainContactsGetURL},
+	{Service: "Domains", Name: "UpdateContact", Method: "POST", Path: domainContactsSetURL, Mutating: true},
+	{Service: "Domains", Name: "SetWhoisPrivacy", Method: "POST", Path: domainPrivacyURL, Mutating: true},
+
+	{Service: "SSL", Name: "Order", Method: "POST", Path: sslOrderURL, Mutating: true},
+	{Service: "SSL", Name: "List", Method: "POST", Path: sslListURL},
+	{Service: "SSL", Name: "GetInfo", Method: "POST", Path: sslInfoURL},
+	{Service: "SSL", Name: "Reissue", Method: "POST", Path: sslReissueURL, Mutating: true},
+}
+
+// Endpoints returns all ClouDNS API endpoints wrapped by the installed version of cloudns-go, which allows detecting at
+// runtime whether a capability is available
+func Endpoints() []Endpoint {
+	return append([]Endpoint(nil), endpoints...)
+}
+
+// HasEndpoint returns true if the given API path, e.g. `/dns/add-record.json`, is wrapped by cloudns-go
+func HasEndpoint(path string) bool {
+	_, ok := lookupEndpoint(path)
+	return ok
+}
+
+// lookupEndpoint returns the wrapped endpoint with the given API path
+func lookupEndpoint(path string) (Endpoint, bool) {
+	for _, endpoint := range endpoints {
+		if endpoint.Path == path {
+			return endpoint, true
+		}
+	}
+
+	return Endpoint{}, false
+}
+
+// endpoint returns the classification of the given API path, preferring endpoints registered with ClassifyEndpoints
+// over the wrapped endpoints
+func (c *Client) endpoint(path string) (Endpoint, bool) {
+	if endpoint, ok := c.classifiedEndpoints[path]; ok {
+		return endpoint, true
+	}
+
+	return lookupEndpoint(path)
+}
+
+// isMutating returns true if the given API path is classified as mutating. Unknown endpoints are considered mutating,
+// as they might modify zones.
+func (c *Client) isMutating(path string) bool {
+	endpoint, ok := c.endpoint(path)
+	return !ok || endpoint.Mutating
+}
+
+// isCacheable returns true if the given API path is classified as cacheable and not mutating
+func (c *Client) isCacheable(path string) bool {
+	endpoint, ok := c.endpoint(path)
+	return ok && endpoint.Cacheable && !endpoint.Mutating
+}
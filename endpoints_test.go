@@ -0,0 +1,163 @@
+package cloudns
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEndpoints(t *testing.T) {
+	services := map[string]reflect.Type{
+		"Account":      reflect.TypeOf(&AccountService{}),
+		"Zones":        reflect.TypeOf(&ZoneService{}),
+		"Records":      reflect.TypeOf(&RecordService{}),
+		"DNSSEC":       reflect.TypeOf(&DNSSECService{}),
+		"Failover":     reflect.TypeOf(&FailoverService{}),
+		"MailForwards": reflect.TypeOf(&MailForwardService{}),
+		"Domains":      reflect.TypeOf(&DomainService{}),
+		"SSL":          reflect.TypeOf(&SSLService{}),
+	}
+
+	paths := make(map[string]bool)
+	for _, endpoint := range Endpoints() {
+		assert.False(t, paths[endpoint.Path], "path %s should only be listed once", endpoint.Path)
+		paths[endpoint.Path] = true
+
+		service, ok := services[endpoint.Service]
+		if assert.True(t, ok, "service %s should exist", endpoint.Service) {
+			_, ok = service.MethodByName(endpoint.Name)
+			assert.True(t, ok, "method %s.%s should exist", endpoint.Service, endpoint.Name)
+		}
+		assert.Equal(t, http.MethodPost, endpoint.Method)
+		assert.False(t, endpoint.Mutating && endpoint.Cacheable, "mutating endpoint %s should not be cacheable", endpoint.Path)
+	}
+}
+
+func TestHasEndpoint(t *testing.T) {
+	assert.True(t, HasEndpoint(recordCreateURL))
+	assert.False(t, HasEndpoint("/dns/unknown.json"))
+}
+
+func TestClient_Call(t *testing.T) {
+	// given
+	var params map[string]interface{}
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		assert.Equal(t, "/dns/new-feature.json", req.URL.Path)
+		_ = json.NewDecoder(req.Body).Decode(&params)
+		return `{"enabled":true}`
+	})
+
+	// when
+	var result struct {
+		Enabled bool `json:"enabled"`
+	}
+	err := stubClient.Call(context.Background(), "/dns/new-feature.json", HTTPParams{"domain-name": testDomain}, &result)
+
+	// then
+	assert.NoError(t, err)
+	assert.True(t, result.Enabled)
+	assert.Equal(t, testDomain, params["domain-name"])
+}
+
+func TestClassifyEndpoints_ReadOnly(t *testing.T) {
+	// given
+	handler := func(req *http.Request) string { return `{"status":"Success"}` }
+	defaultClient := newStubClient(t, handler, ReadOnly())
+	classifiedClient := newStubClient(t, handler, ReadOnly(), ClassifyEndpoints(Endpoint{Path: "/dns/new-feature.json"}))
+
+	// when
+	defaultErr := defaultClient.Call(context.Background(), "/dns/new-feature.json", nil, nil)
+	classifiedErr := classifiedClient.Call(context.Background(), "/dns/new-feature.json", nil, nil)
+
+	// then
+	assert.ErrorIs(t, defaultErr, ErrReadOnlyClient, "unknown endpoints should be treated as mutating")
+	assert.NoError(t, classifiedErr)
+}
+
+func TestClassifyEndpoints_Retries(t *testing.T) {
+	// given
+	var defaultAttempts, classifiedAttempts int
+	policy := Retries(RetryPolicy{MaxAttempts: 3})
+	defaultClient := newFlakyClient(t, 1, &defaultAttempts, policy)
+	classifiedClient := newFlakyClient(t, 1, &classifiedAttempts, policy, ClassifyEndpoints(Endpoint{Path: "/dns/new-feature.json"}))
+
+	// when
+	defaultErr := defaultClient.Call(context.Background(), "/dns/new-feature.json", nil, nil)
+	classifiedErr := classifiedClient.Call(context.Background(), "/dns/new-feature.json", nil, nil)
+
+	// then
+	assert.Error(t, defaultErr)
+	assert.Equal(t, 1, defaultAttempts, "unknown endpoints should not be retried")
+	assert.NoError(t, classifiedErr)
+	assert.Equal(t, 2, classifiedAttempts)
+}
+
+func TestClassifyEndpoints_Cacheable(t *testing.T) {
+	// given
+	var requests int
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		requests++
+		return `{"status":"Success"}`
+	}, ResponseCache(NewMemoryCache(), time.Hour), ClassifyEndpoints(Endpoint{Path: "/dns/new-feature.json", Cacheable: true}))
+
+	// when
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, stubClient.Call(context.Background(), "/dns/new-feature.json", nil, nil))
+	}
+
+	// then
+	assert.Equal(t, 1, requests)
+}
+
+func TestClassifyEndpoints_Invalid(t *testing.T) {
+	_, err := New(ClassifyEndpoints(Endpoint{Name: "missing path"}))
+	assert.ErrorIs(t, err, ErrInvalidOptions)
+
+	_, err = New(ClassifyEndpoints(Endpoint{Path: "/dns/new-feature.json", Mutating: true, Cacheable: true}))
+	assert.ErrorIs(t, err, ErrInvalidOptions)
+}
+
+func TestWithDryRun(t *testing.T) {
+	// given
+	var requests []string
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		requests = append(requests, req.URL.Path)
+		return `[]`
+	})
+	ctx := WithDryRun(context.Background())
+
+	// when
+	_, listErr := stubClient.Records.List(ctx, testDomain)
+	result, createErr := stubClient.Records.Create(ctx, testDomain, NewRecordA("www", "192.0.2.1", testTTL))
+	callErr := stubClient.Call(ctx, "/dns/new-feature.json", nil, nil)
+
+	// then
+	assert.NoError(t, listErr)
+	assert.NoError(t, createErr)
+	assert.NoError(t, callErr)
+	assert.Equal(t, "Success", result.Status)
+	assert.Equal(t, []string{recordListURL}, requests, "mutating requests should not be sent")
+}
+
+func TestDryRun(t *testing.T) {
+	// given
+	var requests []string
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		requests = append(requests, req.URL.Path)
+		return `[]`
+	}, DryRun())
+
+	// when
+	_, listErr := stubClient.Records.List(context.Background(), testDomain)
+	result, createErr := stubClient.Records.Create(context.Background(), testDomain, NewRecordA("www", "192.0.2.1", testTTL))
+
+	// then
+	assert.NoError(t, listErr)
+	assert.NoError(t, createErr)
+	assert.Equal(t, "Success", result.Status)
+	assert.Equal(t, []string{recordListURL}, requests, "mutating requests should not be sent")
+}
@@ -2,16 +2,44 @@ package cloudns
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 )
 
 // Constant errors which can be returned by cloudns-go when something goes wrong
 const (
 	ErrHTTPRequest         = constError("http request failed")
+	ErrDNSQuery            = constError("dns query failed")
 	ErrAPIInvocation       = constError("api invocation failed")
 	ErrIllegalArgument     = constError("illegal argument provided")
 	ErrInvalidOptions      = constError("invalid options provided")
 	ErrMultipleCredentials = constError("more than one kind of credentials specified")
+	ErrMissingCredentials  = constError("no credentials specified")
+	ErrImportConflict      = constError("imported records conflict with existing records")
+	ErrImportNotConfirmed  = constError("import has not been confirmed")
+	ErrRateLimiterFailed   = constError("rate limiter failed")
+	ErrZoneFrozen          = constError("zone is frozen")
+	ErrInconsistentListing = constError("listing changed while being fetched")
+	ErrZoneKindMismatch    = constError("zone kind does not match zone name")
+	ErrReadOnlyClient      = constError("client is read-only")
+	ErrTTLPolicy           = constError("ttl violates policy")
+	ErrCloneIncomplete     = constError("cloned zone is incomplete")
+	ErrUpdateThrottled     = constError("zone update triggered too soon")
+	ErrUnsupportedRecord   = constError("record type is not supported")
+)
+
+// Constant errors classifying failures reported by the ClouDNS API, which are matched by errors.Is in addition to
+// ErrAPIInvocation. The classification is based on the description returned by the API on a best-effort basis.
+// Failures classified as ErrRecordQuotaExceeded are matched by ErrQuotaExceeded as well.
+const (
+	ErrZoneNotFound        = constError("zone not found")
+	ErrRecordNotFound      = constError("record not found")
+	ErrRateLimited         = constError("too many requests")
+	ErrAuthFailed          = constError("authentication failed")
+	ErrQuotaExceeded       = constError("quota exceeded")
+	ErrRecordQuotaExceeded = constError("record quota exceeded")
+	ErrDNSSECNotActive     = constError("dnssec is not active")
 )
 
 type constError string
@@ -49,3 +77,191 @@ func (err wrapError) Is(target error) bool {
 func (err wrapError) Unwrap() error {
 	return err.inner
 }
+
+// APIError represents a failure reported by the ClouDNS API. Structured information embedded in the human-readable
+// description is extracted into typed fields on a best-effort basis, which are left empty if not present. API errors
+// are always wrapped in ErrAPIInvocation and can be retrieved with errors.As.
+type APIError struct {
+	Status      string
+	Description string
+	// LocalizedDescription contains the original description returned by the API if it has been translated using the
+	// TranslateStatus option, while Description contains the translation
+	LocalizedDescription string
+	// ClientVersion contains the version of cloudns-go which sent the failed request, see Version
+	ClientVersion string
+
+	// Conflict is true if the API rejected a change because an equal or conflicting object already exists
+	Conflict bool
+	// ConflictingRecord contains the name of the conflicting record or object, if mentioned by the API
+	ConflictingRecord string
+	// Field contains the name of the invalid or missing parameter, if mentioned by the API
+	Field string
+	// LimitValue contains the limit which has been exceeded, e.g. the maximum amount of zones or records
+	LimitValue int
+	// Throttled is true if the API rejected the request because too many requests have been sent
+	Throttled bool
+
+	// kind is the classification of the failure, e.g. ErrZoneNotFound, which is empty for unknown failures
+	kind constError
+}
+
+var (
+	apiErrorQuotedPattern = regexp.MustCompile(`["'“]([^"'”]+)["'”]`)
+	apiErrorFieldPattern  = regexp.MustCompile(`(?i)(?:parameter|field)\s+["']?([a-z][a-z0-9_-]*)`)
+	apiErrorLimitPattern  = regexp.MustCompile(`(?i)(?:limit|maximum|max\.?)\D{0,40}?(\d+)`)
+)
+
+// newAPIError creates an APIError for the given status and description and extracts all known structured information
+func newAPIError(status, description string) *APIError {
+	err := &APIError{Status: status, Description: description, ClientVersion: Version()}
+	lowerDescription := strings.ToLower(description)
+
+	if strings.Contains(lowerDescription, "already exist") {
+		err.Conflict = true
+		if match := apiErrorQuotedPattern.FindStringSubmatch(description); match != nil {
+			err.ConflictingRecord = match[1]
+		}
+	}
+	if strings.Contains(lowerDescription, "too many requests") {
+		err.Throttled = true
+	}
+	if match := apiErrorLimitPattern.FindStringSubmatch(description); match != nil {
+		err.LimitValue, _ = strconv.Atoi(match[1])
+	}
+	if match := apiErrorFieldPattern.FindStringSubmatch(description); match != nil && !err.Conflict {
+		err.Field = match[1]
+	}
+	err.kind = classifyAPIError(err, lowerDescription)
+
+	return err
+}
+
+// classifyAPIError returns the constant error matching the failure described by the lowercased description
+func classifyAPIError(err *APIError, description string) constError {
+	containsAny := func(substrings ...string) bool {
+		for _, substring := range substrings {
+			if strings.Contains(description, substring) {
+				return true
+			}
+		}
+		return false
+	}
+	notFound := containsAny("not found", "does not exist", "doesn't exist", "not exist", "not in your account")
+	quota := containsAny("reached the limit", "limit exceeded", "exceeded the limit", "limit reached", "quota")
+
+	switch {
+	case err.Throttled:
+		return ErrRateLimited
+	case containsAny("invalid authentication", "auth-password", "auth-id", "sub-auth"):
+		return ErrAuthFailed
+	case containsAny("dnssec is not active", "dnssec not active"):
+		return ErrDNSSECNotActive
+	case containsAny("invalid record-id", "invalid record id") || (notFound && containsAny("record")):
+		return ErrRecordNotFound
+	case notFound && containsAny("zone", "domain"):
+		return ErrZoneNotFound
+	case quota && containsAny("record"):
+		return ErrRecordQuotaExceeded
+	case quota:
+		return ErrQuotaExceeded
+	}
+
+	return ""
+}
+
+func (err *APIError) Error() string {
+	return err.Description
+}
+
+// Is returns true if the target is the constant error classifying the failure, e.g. ErrZoneNotFound
+func (err *APIError) Is(target error) bool {
+	kind, ok := target.(constError)
+	if !ok || err.kind == "" {
+		return false
+	}
+
+	return kind == err.kind || (kind == ErrQuotaExceeded && err.kind == ErrRecordQuotaExceeded)
+}
+
+// requestSnapshotMaxLength is the maximum length of parameter values within a RequestError
+const requestSnapshotMaxLength = 64
+
+// RequestError annotates an error of an API request with a sanitized snapshot of the request, which can be retrieved
+// with errors.As for reproducing failing calls without enabling full debug logging. Credentials and transfer codes are
+// redacted, while long values like zone imports are truncated.
+type RequestError struct {
+	Method   string
+	Endpoint string
+	// Params contains all parameters which were sent, including client-wide and context parameters
+	Params map[string]string
+
+	inner error
+}
+
+// newRequestError creates a RequestError for the given merged parameters
+func newRequestError(method, endpoint string, params map[string]interface{}, inner error) *RequestError {
+	return &RequestError{Method: method, Endpoint: endpoint, Params: sanitizeParams(params), inner: inner}
+}
+
+// sanitizeParams formats the given parameters, redacting sensitive ones and truncating long values
+func sanitizeParams(params map[string]interface{}) map[string]string {
+	sanitized := make(map[string]string, len(params))
+	for key, value := range params {
+		formatted := formatParam(value)
+		if containsString(key, sensitiveParamKeys) {
+			formatted = "[redacted]"
+		} else if runes := []rune(formatted); len(runes) > requestSnapshotMaxLength {
+			formatted = fmt.Sprintf("%s... (%d characters)", string(runes[:requestSnapshotMaxLength]), len(runes))
+		}
+
+		sanitized[key] = formatted
+	}
+
+	return sanitized
+}
+
+func (err *RequestError) Error() string {
+	return err.inner.Error()
+}
+
+func (err *RequestError) Unwrap() error {
+	return err.inner
+}
+
+// requestIDError annotates an error with the request ID of the API request which caused it
+type requestIDError struct {
+	requestID string
+	inner     error
+}
+
+func (err requestIDError) Error() string {
+	return fmt.Sprintf("%v (request id: %s)", err.inner, err.requestID)
+}
+
+func (err requestIDError) Unwrap() error {
+	return err.inner
+}
+
+// PartialResultError is returned together with incomplete results if some pages of a paginated listing could not be
+// fetched. The returned results contain all entries of the remaining pages.
+type PartialResultError struct {
+	// TotalPages is the amount of pages which should have been fetched
+	TotalPages int
+	// FailedPages contains the indices of all pages which could not be fetched in ascending order
+	FailedPages []int
+	// PageErrors contains the last error for each failed page, indexed by page
+	PageErrors map[int]error
+}
+
+func (err *PartialResultError) Error() string {
+	return fmt.Sprintf("partial result: failed to fetch %d of %d pages: %v", len(err.FailedPages), err.TotalPages, err.Unwrap())
+}
+
+// Unwrap returns the error of the first failed page
+func (err *PartialResultError) Unwrap() error {
+	if len(err.FailedPages) == 0 {
+		return nil
+	}
+
+	return err.PageErrors[err.FailedPages[0]]
+}
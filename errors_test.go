@@ -1,8 +1,11 @@
 package cloudns
 
 import (
+	"context"
 	"errors"
 	"github.com/stretchr/testify/assert"
+	"net/http"
+	"strings"
 	"testing"
 )
 
@@ -52,3 +55,176 @@ func TestWrapError_Unwrap(t *testing.T) {
 	assert.True(t, errors.Is(wrapErr, outerErr), "errors.Is(wrapErr, outerErr) should return true")
 	assert.True(t, errors.Is(wrapErr, innerErr), "errors.Is(wrapErr, innerErr) should return true")
 }
+
+func TestNewAPIError(t *testing.T) {
+	tests := []struct {
+		description string
+		expected    APIError
+	}{
+		{
+			description: "Record with such name already exists",
+			expected:    APIError{Conflict: true},
+		},
+		{
+			description: `The record "www" already exists.`,
+			expected:    APIError{Conflict: true, ConflictingRecord: "www"},
+		},
+		{
+			description: "You have reached the limit of 10 zones in your plan.",
+			expected:    APIError{LimitValue: 10, kind: ErrQuotaExceeded},
+		},
+		{
+			description: "Invalid value for parameter ttl",
+			expected:    APIError{Field: "ttl"},
+		},
+		{
+			description: "Missing domain name",
+			expected:    APIError{},
+		},
+	}
+
+	for _, test := range tests {
+		test.expected.Status = "Failed"
+		test.expected.Description = test.description
+		test.expected.ClientVersion = Version()
+
+		err := newAPIError("Failed", test.description)
+		assert.Equal(t, &test.expected, err, "unexpected fields for %q", test.description)
+	}
+}
+
+func TestAPIError_Classification(t *testing.T) {
+	tests := map[string]error{
+		"Invalid domain-name param.":                                  nil,
+		"The zone example.com does not exist.":                        ErrZoneNotFound,
+		"Domain name is not in your account.":                         ErrZoneNotFound,
+		"Invalid record-id param.":                                    ErrRecordNotFound,
+		"Record not found.":                                           ErrRecordNotFound,
+		"Too many requests. Please try again later.":                  ErrRateLimited,
+		"Invalid authentication, incorrect auth-id or auth-password.": ErrAuthFailed,
+		"You have reached the limit of 10 zones in your plan.":        ErrQuotaExceeded,
+		"DNSSEC is not active for this zone.":                         ErrDNSSECNotActive,
+		"Record with such name already exists":                        nil,
+		"Invalid value for parameter ttl":                             nil,
+	}
+
+	kinds := []error{ErrZoneNotFound, ErrRecordNotFound, ErrRateLimited, ErrAuthFailed, ErrQuotaExceeded, ErrDNSSECNotActive}
+	for description, expected := range tests {
+		err := ErrAPIInvocation.wrap(newAPIError("Failed", description))
+		assert.ErrorIs(t, err, ErrAPIInvocation, "%q should still be an api invocation error", description)
+
+		for _, kind := range kinds {
+			assert.Equal(t, kind == expected, errors.Is(err, kind), "%q classified incorrectly as %v", description, kind)
+		}
+	}
+}
+
+func TestAPIError_Classification_RecordQuota(t *testing.T) {
+	err := ErrAPIInvocation.wrap(newAPIError("Failed", "You have reached the limit of 1000 records for this zone."))
+	assert.ErrorIs(t, err, ErrRecordQuotaExceeded)
+	assert.ErrorIs(t, err, ErrQuotaExceeded, "record quota should also match generic quota")
+	assert.NotErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestAPIError_Classification_Client(t *testing.T) {
+	// given
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `{"status":"Failed","statusDescription":"Invalid record-id param."}`
+	})
+
+	// when
+	_, err := stubClient.Records.Delete(context.Background(), testDomain, 42)
+
+	// then
+	assert.ErrorIs(t, err, ErrRecordNotFound)
+	assert.ErrorIs(t, err, ErrAPIInvocation)
+	assert.NotErrorIs(t, err, ErrZoneNotFound)
+	assert.Equal(t, "api invocation failed: Invalid record-id param.", err.Error())
+}
+
+func TestAPIError_Unwrap(t *testing.T) {
+	// given
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `{"status":"Failed","statusDescription":"Record with such name already exists"}`
+	})
+
+	// when
+	_, err := stubClient.Records.Create(context.Background(), testDomain, NewRecordA("www", "192.0.2.1", testTTL))
+
+	// then
+	var apiErr *APIError
+	assert.ErrorIs(t, err, ErrAPIInvocation)
+	assert.True(t, errors.As(err, &apiErr), "should contain API error")
+	assert.True(t, apiErr.Conflict)
+	assert.Equal(t, "api invocation failed: Record with such name already exists", err.Error())
+}
+
+func TestAPIError_Translated(t *testing.T) {
+	// given
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `{"status":"Failed","statusDescription":"Ein Eintrag mit diesem Namen existiert bereits "}`
+	}, StatusTranslations(map[string]string{
+		"Ein Eintrag mit diesem Namen existiert bereits": "Record with such name already exists",
+	}))
+
+	// when
+	_, err := stubClient.Records.Create(context.Background(), testDomain, NewRecordA("www", "192.0.2.1", testTTL))
+
+	// then
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr), "should contain API error")
+	assert.True(t, apiErr.Conflict, "translated description should be classified")
+	assert.Equal(t, "Record with such name already exists", apiErr.Description)
+	assert.Equal(t, "Ein Eintrag mit diesem Namen existiert bereits ", apiErr.LocalizedDescription)
+}
+
+func TestAPIError_Untranslated(t *testing.T) {
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `{"status":"Failed","statusDescription":"Missing domain name"}`
+	}, StatusTranslations(map[string]string{}))
+
+	_, err := stubClient.Records.Create(context.Background(), testDomain, NewRecordA("www", "192.0.2.1", testTTL))
+
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr), "should contain API error")
+	assert.Equal(t, "Missing domain name", apiErr.Description)
+	assert.Empty(t, apiErr.LocalizedDescription)
+}
+
+func TestRequestError(t *testing.T) {
+	// given
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `{"status":"Failed","statusDescription":"Invalid zone content."}`
+	}, AuthUserID(1234, "s3cr3t"), Params(HTTPParams{"lang": "en"}))
+	content := strings.Repeat("www 3600 IN A 192.0.2.1\n", 10)
+
+	// when
+	_, err := stubClient.Records.Import(context.Background(), testDomain, RecordFormatBIND, content, false)
+
+	// then
+	var requestErr *RequestError
+	assert.True(t, errors.As(err, &requestErr), "should contain request error")
+	assert.ErrorIs(t, err, ErrAPIInvocation)
+	assert.Equal(t, "api invocation failed: Invalid zone content.", err.Error(), "error message should not change")
+	assert.Equal(t, "POST", requestErr.Method)
+	assert.Equal(t, recordImportURL, requestErr.Endpoint)
+	assert.Equal(t, "[redacted]", requestErr.Params["auth-id"])
+	assert.Equal(t, "[redacted]", requestErr.Params["auth-password"])
+	assert.Equal(t, testDomain, requestErr.Params["domain-name"])
+	assert.Equal(t, "en", requestErr.Params["lang"])
+	assert.Equal(t, content[:64]+"... (240 characters)", requestErr.Params["content"])
+}
+
+func TestRequestError_RequestID(t *testing.T) {
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `{"status":"Failed","statusDescription":"Invalid transfer code."}`
+	})
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	_, err := stubClient.Domains.Transfer(ctx, DomainOrder{Name: "example", TLD: "com", TransferCode: "s3cr3t"})
+
+	var requestErr *RequestError
+	assert.True(t, errors.As(err, &requestErr), "should contain request error")
+	assert.Equal(t, "[redacted]", requestErr.Params["transfer-code"])
+	assert.Contains(t, err.Error(), "(request id: req-1)")
+}
@@ -0,0 +1,301 @@
+package cloudns
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// AccountEventType is an enumeration of all kinds of events emitted by AccountWatcher
+type AccountEventType int
+
+// Enumeration values for AccountEventType
+const (
+	// AccountEventError is emitted whenever polling fails, the watcher continues with the next poll
+	AccountEventError AccountEventType = iota
+	AccountEventZoneAdded
+	AccountEventZoneRemoved
+	AccountEventRecordChanged
+	AccountEventFailoverChanged
+)
+
+// String returns a human-readable name of the event type
+func (eventType AccountEventType) String() string {
+	switch eventType {
+	case AccountEventError:
+		return "error"
+	case AccountEventZoneAdded:
+		return "zone-added"
+	case AccountEventZoneRemoved:
+		return "zone-removed"
+	case AccountEventRecordChanged:
+		return "record-changed"
+	case AccountEventFailoverChanged:
+		return "failover-changed"
+	}
+
+	return "unknown"
+}
+
+// AccountEvent represents a single change within the account detected by AccountWatcher
+type AccountEvent struct {
+	Type       AccountEventType
+	Zone       string
+	DetectedAt time.Time
+	// Change contains the change of the record for AccountEventRecordChanged. Deletions contain the deleted record.
+	Change RecordChange
+	// RecordID and FailoverState contain the monitored record and its new state for AccountEventFailoverChanged
+	RecordID      int
+	FailoverState FailoverState
+	// Error contains the cause of AccountEventError
+	Error error
+}
+
+// AccountWatcherOptions specifies which parts of the account are watched by AccountWatcher
+type AccountWatcherOptions struct {
+	// Interval is the time waited between two polls, defaulting to one minute
+	Interval time.Duration
+	// Zones contains the names of all zones whose records are watched. As watching records requires listing all records
+	// of each zone per poll, records of other zones are not watched.
+	Zones []string
+	// FailoverRecords contains the IDs of all records with activated failover whose state is watched, indexed by zone
+	FailoverRecords map[string][]int
+	// Store persists the baseline after every poll and is used for restoring it before the first poll, so that a
+	// restarted watcher neither repeats events nor misses changes made while it was not running. Optional.
+	Store Store
+	// StoreKey is the key of the baseline within the store, defaulting to `account-watcher`
+	StoreKey string
+}
+
+// accountWatcherState is the baseline of AccountWatcher as persisted within the store
+type accountWatcherState struct {
+	Zones     map[string]bool                  `json:"zones"`
+	Records   map[string][]Record              `json:"records"`
+	Failovers map[string]map[int]FailoverState `json:"failovers"`
+}
+
+// AccountWatcher polls the ClouDNS API and converts the differences between two polls into a stream of account events,
+// e.g. for building operational dashboards. The first poll only establishes the baseline and emits no events, unless
+// the baseline has been restored from the store. Changes which are reverted between two polls are not detected, as
+// ClouDNS does not provide an audit log via its API.
+type AccountWatcher struct {
+	client  *Client
+	options AccountWatcherOptions
+	loaded  bool
+
+	zones     map[string]bool
+	records   map[string]RecordMap
+	failovers map[string]map[int]FailoverState
+}
+
+// NewAccountWatcher instantiates a new AccountWatcher using the given client
+func NewAccountWatcher(client *Client, options AccountWatcherOptions) *AccountWatcher {
+	if options.Interval <= 0 {
+		options.Interval = time.Minute
+	}
+	if options.StoreKey == "" {
+		options.StoreKey = "account-watcher"
+	}
+
+	return &AccountWatcher{client: client, options: options}
+}
+
+// Events starts polling in the background and returns a channel receiving all detected events, which gets closed once
+// the context is done. Polling blocks while events are not being received.
+func (watcher *AccountWatcher) Events(ctx context.Context) <-chan AccountEvent {
+	events := make(chan AccountEvent)
+
+	go func() {
+		defer close(events)
+
+		for {
+			for _, event := range watcher.Poll(ctx) {
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			if err := watcher.client.clock.Sleep(ctx, watcher.options.Interval); err != nil {
+				return
+			}
+		}
+	}()
+
+	return events
+}
+
+// Poll polls the API once and returns all events detected since the previous poll. Failing to restore or persist the
+// baseline is reported as AccountEventError, in which case restoring is attempted again with the next poll.
+func (watcher *AccountWatcher) Poll(ctx context.Context) []AccountEvent {
+	var events []AccountEvent
+	now := watcher.client.clock.Now()
+	emit := func(event AccountEvent) {
+		event.DetectedAt = now
+		events = append(events, event)
+	}
+
+	if watcher.options.Store != nil && !watcher.loaded {
+		if err := watcher.load(ctx); err != nil {
+			emit(AccountEvent{Type: AccountEventError, Error: err})
+			return events
+		}
+		watcher.loaded = true
+	}
+
+	if zones, err := watcher.client.Zones.List(ctx); err != nil {
+		emit(AccountEvent{Type: AccountEventError, Error: err})
+	} else {
+		watcher.diffZones(zones, emit)
+	}
+
+	for _, zoneName := range watcher.options.Zones {
+		records, err := watcher.client.Records.List(ctx, zoneName)
+		if err != nil {
+			emit(AccountEvent{Type: AccountEventError, Zone: zoneName, Error: err})
+			continue
+		}
+		watcher.diffRecords(zoneName, records, emit)
+	}
+
+	zoneNames := make([]string, 0, len(watcher.options.FailoverRecords))
+	for zoneName := range watcher.options.FailoverRecords {
+		zoneNames = append(zoneNames, zoneName)
+	}
+	sort.Strings(zoneNames)
+	for _, zoneName := range zoneNames {
+		for _, recordID := range watcher.options.FailoverRecords[zoneName] {
+			state, err := watcher.client.Failover.GetState(ctx, zoneName, recordID)
+			if err != nil {
+				emit(AccountEvent{Type: AccountEventError, Zone: zoneName, RecordID: recordID, Error: err})
+				continue
+			}
+			watcher.diffFailover(zoneName, recordID, state, emit)
+		}
+	}
+
+	if watcher.options.Store != nil {
+		if err := watcher.save(ctx); err != nil {
+			emit(AccountEvent{Type: AccountEventError, Error: err})
+		}
+	}
+
+	return events
+}
+
+// load restores the baseline from the store, keeping the current baseline if none has been stored yet
+func (watcher *AccountWatcher) load(ctx context.Context) error {
+	value, ok, err := watcher.options.Store.Get(ctx, watcher.options.StoreKey)
+	if err != nil || !ok {
+		return err
+	}
+
+	var state accountWatcherState
+	if err := json.Unmarshal(value, &state); err != nil {
+		return err
+	}
+
+	watcher.zones = state.Zones
+	watcher.failovers = state.Failovers
+	watcher.records = make(map[string]RecordMap, len(state.Records))
+	for zoneName, records := range state.Records {
+		watcher.records[zoneName] = make(RecordMap, len(records))
+		for _, record := range records {
+			watcher.records[zoneName][record.ID] = record
+		}
+	}
+
+	return nil
+}
+
+// save persists the current baseline within the store
+func (watcher *AccountWatcher) save(ctx context.Context) error {
+	state := accountWatcherState{
+		Zones:     watcher.zones,
+		Records:   make(map[string][]Record, len(watcher.records)),
+		Failovers: watcher.failovers,
+	}
+	for zoneName, records := range watcher.records {
+		state.Records[zoneName] = records.SortedSlice()
+	}
+
+	value, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	return watcher.options.Store.Put(ctx, watcher.options.StoreKey, value)
+}
+
+func (watcher *AccountWatcher) diffZones(zones []Zone, emit func(event AccountEvent)) {
+	current := make(map[string]bool, len(zones))
+	for _, zone := range zones {
+		current[zone.Name] = true
+	}
+
+	if watcher.zones != nil {
+		for _, zone := range zones {
+			if !watcher.zones[zone.Name] {
+				emit(AccountEvent{Type: AccountEventZoneAdded, Zone: zone.Name})
+			}
+		}
+
+		var removed []string
+		for zoneName := range watcher.zones {
+			if !current[zoneName] {
+				removed = append(removed, zoneName)
+			}
+		}
+		sort.Strings(removed)
+		for _, zoneName := range removed {
+			emit(AccountEvent{Type: AccountEventZoneRemoved, Zone: zoneName})
+		}
+	}
+
+	watcher.zones = current
+}
+
+func (watcher *AccountWatcher) diffRecords(zoneName string, records RecordMap, emit func(event AccountEvent)) {
+	if watcher.records == nil {
+		watcher.records = make(map[string]RecordMap)
+	}
+
+	previous, ok := watcher.records[zoneName]
+	watcher.records[zoneName] = records
+	if !ok {
+		return
+	}
+
+	for _, record := range records.SortedSlice() {
+		if old, exists := previous[record.ID]; !exists {
+			emit(AccountEvent{Type: AccountEventRecordChanged, Zone: zoneName,
+				Change: RecordChange{Type: RecordChangeCreate, ID: record.ID, Record: record}})
+		} else if old != record {
+			emit(AccountEvent{Type: AccountEventRecordChanged, Zone: zoneName,
+				Change: RecordChange{Type: RecordChangeUpdate, ID: record.ID, Record: record}})
+		}
+	}
+	for _, record := range previous.SortedSlice() {
+		if _, exists := records[record.ID]; !exists {
+			emit(AccountEvent{Type: AccountEventRecordChanged, Zone: zoneName,
+				Change: RecordChange{Type: RecordChangeDelete, ID: record.ID, Record: record}})
+		}
+	}
+}
+
+func (watcher *AccountWatcher) diffFailover(zoneName string, recordID int, state FailoverState, emit func(event AccountEvent)) {
+	if watcher.failovers == nil {
+		watcher.failovers = make(map[string]map[int]FailoverState)
+	}
+	if watcher.failovers[zoneName] == nil {
+		watcher.failovers[zoneName] = make(map[int]FailoverState)
+	}
+
+	previous, ok := watcher.failovers[zoneName][recordID]
+	watcher.failovers[zoneName][recordID] = state
+	if ok && previous != state {
+		emit(AccountEvent{Type: AccountEventFailoverChanged, Zone: zoneName, RecordID: recordID, FailoverState: state})
+	}
+}
@@ -0,0 +1,127 @@
+package cloudns
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestAccountWatcher_Poll(t *testing.T) {
+	// given
+	zones := `[{"name":"api-example.com","type":"master","zone":"domain","status":"1"}]`
+	records := `{"1":{"id":"1","host":"www","record":"192.0.2.1","type":"A","ttl":"3600","status":1},
+		"2":{"id":"2","host":"old","record":"192.0.2.2","type":"A","ttl":"3600","status":1}}`
+	state := `{"state":"UP"}`
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		switch req.URL.Path {
+		case zonePageCountURL:
+			return `1`
+		case zoneListURL:
+			return zones
+		case recordListURL:
+			return records
+		case failoverStateURL:
+			return state
+		}
+		return `{"status":"Success"}`
+	})
+	watcher := NewAccountWatcher(stubClient, AccountWatcherOptions{
+		Zones:           []string{testDomain},
+		FailoverRecords: map[string][]int{testDomain: {1}},
+	})
+
+	// when
+	baseline := watcher.Poll(context.Background())
+	zones = `[{"name":"api-example.com","type":"master","zone":"domain","status":"1"},
+		{"name":"api-example.net","type":"master","zone":"domain","status":"1"}]`
+	records = `{"1":{"id":"1","host":"www","record":"192.0.2.10","type":"A","ttl":"3600","status":1},
+		"3":{"id":"3","host":"new","record":"192.0.2.3","type":"A","ttl":"3600","status":1}}`
+	state = `{"state":"DOWN"}`
+	events := watcher.Poll(context.Background())
+
+	// then
+	assert.Empty(t, baseline, "first poll should only establish the baseline")
+	if assert.Len(t, events, 5) {
+		assert.Equal(t, AccountEventZoneAdded, events[0].Type)
+		assert.Equal(t, "api-example.net", events[0].Zone)
+		assert.Equal(t, RecordChangeUpdate, events[1].Change.Type)
+		assert.Equal(t, "192.0.2.10", events[1].Change.Record.Record)
+		assert.Equal(t, RecordChangeCreate, events[2].Change.Type)
+		assert.Equal(t, RecordChangeDelete, events[3].Change.Type)
+		assert.Equal(t, "old", events[3].Change.Record.Host)
+		assert.Equal(t, AccountEvent{Type: AccountEventFailoverChanged, Zone: testDomain, RecordID: 1,
+			FailoverState: FailoverStateDown, DetectedAt: events[4].DetectedAt}, events[4])
+	}
+}
+
+func TestAccountWatcher_Store(t *testing.T) {
+	// given
+	records := `{"1":{"id":"1","host":"www","record":"192.0.2.1","type":"A","ttl":"3600","status":1}}`
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		switch req.URL.Path {
+		case zonePageCountURL:
+			return `1`
+		case zoneListURL:
+			return `[{"name":"api-example.com","type":"master","zone":"domain","status":"1"}]`
+		case recordListURL:
+			return records
+		case failoverStateURL:
+			return `{"state":"UP"}`
+		}
+		return `{"status":"Success"}`
+	})
+	store := NewMemoryStore()
+	options := AccountWatcherOptions{
+		Zones:           []string{testDomain},
+		FailoverRecords: map[string][]int{testDomain: {1}},
+		Store:           store,
+	}
+	baseline := NewAccountWatcher(stubClient, options).Poll(context.Background())
+
+	// when
+	restarted := NewAccountWatcher(stubClient, options).Poll(context.Background())
+	records = `{"1":{"id":"1","host":"www","record":"192.0.2.10","type":"A","ttl":"3600","status":1}}`
+	changed := NewAccountWatcher(stubClient, options).Poll(context.Background())
+
+	// then
+	assert.Empty(t, baseline)
+	assert.Empty(t, restarted, "restored baseline should not cause events to fire again")
+	if assert.Len(t, changed, 1, "changes while not running should be detected after restart") {
+		assert.Equal(t, RecordChangeUpdate, changed[0].Change.Type)
+		assert.Equal(t, "192.0.2.10", changed[0].Change.Record.Record)
+	}
+}
+
+func TestAccountWatcher_Events(t *testing.T) {
+	// given
+	polls := 0
+	clock := NewManualClock(time.Date(2022, 12, 24, 0, 0, 0, 0, time.UTC))
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		switch req.URL.Path {
+		case zonePageCountURL:
+			return `1`
+		case zoneListURL:
+			polls++
+			if polls == 1 {
+				return `[]`
+			}
+			return `[{"name":"api-example.com","type":"master","zone":"domain","status":"1"}]`
+		}
+		return `{"status":"Failed","statusDescription":"Unexpected"}`
+	}, CustomClock(clock))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// when
+	events := NewAccountWatcher(stubClient, AccountWatcherOptions{}).Events(ctx)
+	event := <-events
+	cancel()
+
+	// then
+	assert.Equal(t, AccountEventZoneAdded, event.Type)
+	assert.Equal(t, testDomain, event.Zone)
+	assert.Equal(t, time.Date(2022, 12, 24, 0, 1, 0, 0, time.UTC), event.DetectedAt, "default interval should be used")
+	for range events {
+	}
+}
@@ -0,0 +1,485 @@
+// Package externaldns implements the webhook provider contract of Kubernetes external-dns on top of cloudns-go, which
+// allows clusters to manage ClouDNS records natively. Run the webhook returned by NewHandler as a sidecar of
+// external-dns, which is then started with `--provider=webhook`.
+package externaldns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ppmathis/cloudns-go"
+)
+
+// defaultTTL is used for endpoints without a configured TTL
+const defaultTTL = 3600
+
+// supportedRecordTypes contains all record types which are managed by the provider
+var supportedRecordTypes = []cloudns.RecordType{
+	cloudns.RecordTypeA, cloudns.RecordTypeAAAA, cloudns.RecordTypeCNAME, cloudns.RecordTypeMX, cloudns.RecordTypeNS,
+	cloudns.RecordTypeSRV, cloudns.RecordTypeTXT,
+}
+
+// Endpoint is a DNS name with all of its targets for a single record type, as exchanged with external-dns
+type Endpoint struct {
+	DNSName          string                     `json:"dnsName,omitempty"`
+	Targets          []string                   `json:"targets,omitempty"`
+	RecordType       string                     `json:"recordType,omitempty"`
+	SetIdentifier    string                     `json:"setIdentifier,omitempty"`
+	RecordTTL        int64                      `json:"recordTTL,omitempty"`
+	Labels           map[string]string          `json:"labels,omitempty"`
+	ProviderSpecific []ProviderSpecificProperty `json:"providerSpecific,omitempty"`
+}
+
+// ProviderSpecificProperty is a provider-specific option of an endpoint, which is not used by ClouDNS
+type ProviderSpecificProperty struct {
+	Name  string `json:"name,omitempty"`
+	Value string `json:"value,omitempty"`
+}
+
+// Changes contains all endpoints which external-dns wants to create, update or delete. UpdateOld and UpdateNew contain
+// the previous and desired state of all updated endpoints at the same index.
+type Changes struct {
+	Create    []*Endpoint `json:"Create"`
+	UpdateOld []*Endpoint `json:"UpdateOld"`
+	UpdateNew []*Endpoint `json:"UpdateNew"`
+	Delete    []*Endpoint `json:"Delete"`
+}
+
+// DomainFilter restricts the DNS names managed by the provider to the included domains and their subdomains, apart
+// from the excluded ones. An empty include list includes all domains.
+type DomainFilter struct {
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// Match returns true if the given DNS name is managed according to the filter
+func (filter DomainFilter) Match(name string) bool {
+	name = normalizeName(name)
+	for _, domain := range filter.Exclude {
+		if isWithin(name, domain) {
+			return false
+		}
+	}
+	if len(filter.Include) == 0 {
+		return true
+	}
+	for _, domain := range filter.Include {
+		if isWithin(name, domain) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchZone returns true if the zone might contain managed DNS names, either because it is included itself or because
+// it contains an included domain
+func (filter DomainFilter) matchZone(zoneName string) bool {
+	if filter.Match(zoneName) {
+		return true
+	}
+	for _, domain := range filter.Include {
+		if isWithin(domain, zoneName) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Provider manages the records of all master zones of a ClouDNS account matching the domain filter on behalf of
+// external-dns
+type Provider struct {
+	client *cloudns.Client
+	filter DomainFilter
+}
+
+// NewProvider instantiates a new provider which manages records using the given client. The client should be created
+// with the Retries and RateLimit options, as external-dns reconciles all records periodically.
+func NewProvider(client *cloudns.Client, filter DomainFilter) *Provider {
+	return &Provider{client: client, filter: filter}
+}
+
+// DomainFilter returns the domain filter of the provider, which is announced to external-dns during negotiation
+func (provider *Provider) DomainFilter() DomainFilter {
+	return provider.filter
+}
+
+// Records returns all active records of the managed zones as endpoints, grouped by DNS name and record type. Apex NS
+// records are omitted, as they are provided by ClouDNS.
+func (provider *Provider) Records(ctx context.Context) ([]*Endpoint, error) {
+	zoneNames, err := provider.zones(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var endpoints []*Endpoint
+	for _, zoneName := range zoneNames {
+		records, err := provider.client.Records.List(ctx, zoneName)
+		if err != nil {
+			return nil, err
+		}
+
+		index := make(map[string]*Endpoint)
+		for _, record := range records.SortedSlice() {
+			if !isManaged(record) || !bool(record.IsActive) {
+				continue
+			}
+			name := recordName(record.Host, zoneName)
+			if !provider.filter.Match(name) {
+				continue
+			}
+
+			key := name + "/" + string(record.RecordType)
+			endpoint, ok := index[key]
+			if !ok {
+				endpoint = &Endpoint{DNSName: name, RecordType: string(record.RecordType), RecordTTL: int64(record.TTL)}
+				index[key] = endpoint
+				endpoints = append(endpoints, endpoint)
+			}
+			endpoint.Targets = append(endpoint.Targets, targetFromRecord(record))
+		}
+	}
+
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		if endpoints[i].DNSName != endpoints[j].DNSName {
+			return endpoints[i].DNSName < endpoints[j].DNSName
+		}
+		return endpoints[i].RecordType < endpoints[j].RecordType
+	})
+
+	return endpoints, nil
+}
+
+// AdjustEndpoints drops endpoints with unsupported record types and rounds their TTLs up to the closest TTL available
+// within their zone, which avoids perpetual updates caused by ClouDNS rejecting or altering other TTLs. Endpoints
+// without TTL use one hour.
+func (provider *Provider) AdjustEndpoints(ctx context.Context, endpoints []*Endpoint) ([]*Endpoint, error) {
+	zoneNames, err := provider.zones(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	availableTTLs := make(map[string][]int)
+	adjusted := make([]*Endpoint, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		if !isSupportedType(cloudns.RecordType(endpoint.RecordType)) {
+			continue
+		}
+
+		zoneName := findZone(zoneNames, endpoint.DNSName)
+		if zoneName == "" {
+			adjusted = append(adjusted, endpoint)
+			continue
+		}
+
+		ttls, ok := availableTTLs[zoneName]
+		if !ok {
+			if ttls, err = provider.client.Records.AvailableTTLs(ctx, zoneName); err != nil {
+				return nil, err
+			}
+			sort.Ints(ttls)
+			availableTTLs[zoneName] = ttls
+		}
+
+		if endpoint.RecordTTL <= 0 {
+			endpoint.RecordTTL = defaultTTL
+		}
+		endpoint.RecordTTL = int64(roundTTL(int(endpoint.RecordTTL), ttls))
+		adjusted = append(adjusted, endpoint)
+	}
+
+	return adjusted, nil
+}
+
+// ApplyChanges applies the changes requested by external-dns. Existing records are matched by their DNS name, type and
+// target, and the changes of every zone are applied with RecordService.ApplyChanges, which turns replaced targets into
+// updates where possible. Endpoints outside of the managed zones fail with cloudns.ErrIllegalArgument.
+func (provider *Provider) ApplyChanges(ctx context.Context, changes *Changes) error {
+	if len(changes.UpdateOld) != len(changes.UpdateNew) {
+		return fmt.Errorf("%w: amount of old and new endpoints of updates differs", cloudns.ErrIllegalArgument)
+	}
+
+	zoneNames, err := provider.zones(ctx)
+	if err != nil {
+		return err
+	}
+
+	type zoneChanges struct {
+		deletes []*Endpoint
+		creates []*Endpoint
+	}
+	byZone := make(map[string]*zoneChanges)
+	var order []string
+	assign := func(endpoints []*Endpoint, isDelete bool) error {
+		for _, endpoint := range endpoints {
+			zoneName := findZone(zoneNames, endpoint.DNSName)
+			if zoneName == "" || !provider.filter.Match(endpoint.DNSName) {
+				return fmt.Errorf("%w: %s is not within a managed zone", cloudns.ErrIllegalArgument, endpoint.DNSName)
+			}
+
+			if _, ok := byZone[zoneName]; !ok {
+				byZone[zoneName] = &zoneChanges{}
+				order = append(order, zoneName)
+			}
+			if isDelete {
+				byZone[zoneName].deletes = append(byZone[zoneName].deletes, endpoint)
+			} else {
+				byZone[zoneName].creates = append(byZone[zoneName].creates, endpoint)
+			}
+		}
+		return nil
+	}
+
+	for _, group := range []struct {
+		endpoints []*Endpoint
+		isDelete  bool
+	}{{changes.Delete, true}, {changes.UpdateOld, true}, {changes.Create, false}, {changes.UpdateNew, false}} {
+		if err := assign(group.endpoints, group.isDelete); err != nil {
+			return err
+		}
+	}
+
+	for _, zoneName := range order {
+		existing, err := provider.client.Records.List(ctx, zoneName)
+		if err != nil {
+			return err
+		}
+
+		recordChanges, err := planChanges(zoneName, existing, byZone[zoneName].deletes, byZone[zoneName].creates)
+		if err != nil {
+			return err
+		}
+		if _, err := provider.client.Records.ApplyChanges(ctx, zoneName, recordChanges); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// planChanges converts the deleted and created endpoints of a zone into record changes. Deleted targets which do not
+// exist within the zone are ignored, as the desired state is already reached.
+func planChanges(zoneName string, existing cloudns.RecordMap, deletes, creates []*Endpoint) ([]cloudns.RecordChange, error) {
+	remaining := existing.SortedSlice()
+	var changes []cloudns.RecordChange
+
+	for _, endpoint := range deletes {
+		records, err := recordsFromEndpoint(endpoint, zoneName)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, record := range records {
+			for i, candidate := range remaining {
+				if recordKey(candidate) == recordKey(record) {
+					changes = append(changes, cloudns.RecordChange{Type: cloudns.RecordChangeDelete, ID: candidate.ID, Record: candidate})
+					remaining = append(remaining[:i], remaining[i+1:]...)
+					break
+				}
+			}
+		}
+	}
+
+	for _, endpoint := range creates {
+		records, err := recordsFromEndpoint(endpoint, zoneName)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, record := range records {
+			changes = append(changes, cloudns.RecordChange{Type: cloudns.RecordChangeCreate, Record: record})
+		}
+	}
+
+	return changes, nil
+}
+
+// zones returns the names of all master zones which might contain managed DNS names, longest names first
+func (provider *Provider) zones(ctx context.Context) ([]string, error) {
+	zones, err := provider.client.Zones.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var zoneNames []string
+	for _, zone := range zones {
+		if zone.Type == cloudns.ZoneTypeMaster && provider.filter.matchZone(zone.Name) {
+			zoneNames = append(zoneNames, normalizeName(zone.Name))
+		}
+	}
+
+	sort.Slice(zoneNames, func(i, j int) bool {
+		return len(zoneNames[i]) > len(zoneNames[j])
+	})
+	return zoneNames, nil
+}
+
+// recordsFromEndpoint converts every target of the endpoint into a record of the given zone
+func recordsFromEndpoint(endpoint *Endpoint, zoneName string) ([]cloudns.Record, error) {
+	recordType := cloudns.RecordType(endpoint.RecordType)
+	if !isSupportedType(recordType) {
+		return nil, fmt.Errorf("%w: unsupported record type %s", cloudns.ErrIllegalArgument, endpoint.RecordType)
+	}
+
+	host := normalizeName(endpoint.DNSName)
+	if host == zoneName {
+		host = ""
+	} else {
+		host = strings.TrimSuffix(host, "."+zoneName)
+	}
+
+	ttl := int(endpoint.RecordTTL)
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+
+	records := make([]cloudns.Record, 0, len(endpoint.Targets))
+	for _, target := range endpoint.Targets {
+		record, err := recordFromTarget(recordType, host, target, ttl)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid %s target %q of %s", cloudns.ErrIllegalArgument, recordType, target,
+				endpoint.DNSName)
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// recordFromTarget parses a target in the format used by external-dns into a record
+func recordFromTarget(recordType cloudns.RecordType, host, target string, ttl int) (cloudns.Record, error) {
+	fields := strings.Fields(target)
+
+	switch recordType {
+	case cloudns.RecordTypeTXT:
+		// Ownership records of the TXT registry are quoted by external-dns, while ClouDNS stores them unquoted
+		return cloudns.NewRecordTXT(host, unquoteTXT(target), ttl), nil
+	case cloudns.RecordTypeMX:
+		if len(fields) != 2 {
+			return cloudns.Record{}, errors.New("expected priority and host")
+		}
+		priority, err := strconv.ParseUint(fields[0], 10, 16)
+		if err != nil {
+			return cloudns.Record{}, err
+		}
+		return cloudns.NewRecordMX(host, uint16(priority), normalizeName(fields[1]), ttl), nil
+	case cloudns.RecordTypeSRV:
+		if len(fields) != 4 {
+			return cloudns.Record{}, errors.New("expected priority, weight, port and target")
+		}
+		var values [3]uint16
+		for i := range values {
+			value, err := strconv.ParseUint(fields[i], 10, 16)
+			if err != nil {
+				return cloudns.Record{}, err
+			}
+			values[i] = uint16(value)
+		}
+		return cloudns.NewRecordSRV(host, values[0], values[1], values[2], normalizeName(fields[3]), ttl), nil
+	case cloudns.RecordTypeA, cloudns.RecordTypeAAAA:
+		return cloudns.NewRecord(recordType, host, target, ttl), nil
+	}
+
+	return cloudns.NewRecord(recordType, host, normalizeName(target), ttl), nil
+}
+
+// targetFromRecord formats the value of a record as target in the format used by external-dns
+func targetFromRecord(record cloudns.Record) string {
+	switch record.RecordType {
+	case cloudns.RecordTypeTXT:
+		if strings.HasPrefix(record.Record, "heritage=") {
+			return `"` + record.Record + `"`
+		}
+		return record.Record
+	case cloudns.RecordTypeMX:
+		return fmt.Sprintf("%d %s", record.Priority, record.Record)
+	case cloudns.RecordTypeSRV:
+		return fmt.Sprintf("%d %d %d %s", record.Priority, record.SRV.Weight, record.SRV.Port, record.Record)
+	}
+
+	return record.Record
+}
+
+// recordKey identifies a record by its host, type and target for matching endpoints with existing records
+func recordKey(record cloudns.Record) string {
+	value := targetFromRecord(record)
+	if record.RecordType != cloudns.RecordTypeTXT {
+		value = strings.ToLower(value)
+	}
+
+	return strings.Join([]string{normalizeName(record.Host), string(record.RecordType), unquoteTXT(value)}, "/")
+}
+
+func isManaged(record cloudns.Record) bool {
+	if record.RecordType == cloudns.RecordTypeNS && (record.Host == "" || record.Host == "@") {
+		return false
+	}
+
+	return isSupportedType(record.RecordType)
+}
+
+func isSupportedType(recordType cloudns.RecordType) bool {
+	for _, supported := range supportedRecordTypes {
+		if recordType == supported {
+			return true
+		}
+	}
+
+	return false
+}
+
+// findZone returns the most specific zone containing the given DNS name, expecting the zones to be sorted with the
+// longest names first
+func findZone(zoneNames []string, name string) string {
+	for _, zoneName := range zoneNames {
+		if isWithin(name, zoneName) {
+			return zoneName
+		}
+	}
+
+	return ""
+}
+
+// roundTTL rounds the given TTL up to the closest of the sorted available TTLs, using the largest one if none is larger
+func roundTTL(ttl int, available []int) int {
+	for _, candidate := range available {
+		if candidate >= ttl {
+			return candidate
+		}
+	}
+	if len(available) > 0 {
+		return available[len(available)-1]
+	}
+
+	return ttl
+}
+
+func recordName(host, zoneName string) string {
+	if host == "" || host == "@" {
+		return zoneName
+	}
+
+	return normalizeName(host + "." + zoneName)
+}
+
+func isWithin(name, domain string) bool {
+	name, domain = normalizeName(name), normalizeName(domain)
+	return name == domain || strings.HasSuffix(name, "."+domain)
+}
+
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(name), "."))
+}
+
+func unquoteTXT(value string) string {
+	if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+		return value[1 : len(value)-1]
+	}
+
+	return value
+}
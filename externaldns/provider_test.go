@@ -0,0 +1,265 @@
+package externaldns
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ppmathis/cloudns-go"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (fn roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
+
+// fakeAccount simulates a ClouDNS account with a master zone `example.com` and a slave zone `example.net`
+type fakeAccount struct {
+	records map[int]cloudns.Record
+	nextID  int
+}
+
+func newFakeAccount(records ...cloudns.Record) *fakeAccount {
+	account := &fakeAccount{records: make(map[int]cloudns.Record)}
+	for _, record := range records {
+		account.nextID++
+		record.ID = account.nextID
+		account.records[record.ID] = record
+	}
+
+	return account
+}
+
+func (account *fakeAccount) handle(req *http.Request) string {
+	var params map[string]interface{}
+	_ = json.NewDecoder(req.Body).Decode(&params)
+
+	record := func() cloudns.Record {
+		ttl, _ := strconv.Atoi(fmt.Sprint(params["ttl"]))
+		record := cloudns.NewRecord(cloudns.RecordType(params["record-type"].(string)), params["host"].(string),
+			params["record"].(string), ttl)
+		if priority, err := strconv.Atoi(fmt.Sprint(params["priority"])); err == nil {
+			record.Priority = uint16(priority)
+		}
+		return record
+	}
+
+	switch req.URL.Path {
+	case "/dns/get-pages-count.json":
+		return `1`
+	case "/dns/list-zones.json":
+		return `[{"name":"example.com","type":"master","zone":"domain","status":"1"},` +
+			`{"name":"example.net","type":"slave","zone":"domain","status":"1"}]`
+	case "/dns/records.json":
+		records, _ := json.Marshal(account.records)
+		return string(records)
+	case "/dns/get-available-ttl.json":
+		return `[60,300,900,1800,3600,21600,86400]`
+	case "/dns/add-record.json":
+		account.nextID++
+		created := record()
+		created.ID = account.nextID
+		account.records[created.ID] = created
+		return `{"status":"Success"}`
+	case "/dns/mod-record.json":
+		id, _ := strconv.Atoi(fmt.Sprint(params["record-id"]))
+		updated := record()
+		updated.ID = id
+		account.records[id] = updated
+		return `{"status":"Success"}`
+	case "/dns/delete-record.json":
+		id, _ := strconv.Atoi(fmt.Sprint(params["record-id"]))
+		delete(account.records, id)
+		return `{"status":"Success"}`
+	}
+
+	return `{"status":"Failed","statusDescription":"unexpected request"}`
+}
+
+func newTestProvider(t *testing.T, account *fakeAccount, filter DomainFilter) *Provider {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(account.handle(req))),
+		}, nil
+	})
+
+	client, err := cloudns.New(cloudns.HTTPClient(&http.Client{Transport: transport}))
+	if err != nil {
+		t.Fatalf("could not create client: %v", err)
+	}
+
+	return NewProvider(client, filter)
+}
+
+func TestProvider_Records(t *testing.T) {
+	// given
+	inactive := cloudns.NewRecordA("old", "192.0.2.9", 3600)
+	inactive.IsActive = false
+	provider := newTestProvider(t, newFakeAccount(
+		cloudns.NewRecordNS("", "ns1.cloudns.net", 3600),
+		cloudns.NewRecordA("www", "192.0.2.1", 300),
+		cloudns.NewRecordA("www", "192.0.2.2", 300),
+		cloudns.NewRecordTXT("www", "heritage=external-dns,external-dns/owner=default", 300),
+		cloudns.NewRecordMX("", 10, "mail.example.com", 3600),
+		cloudns.NewRecordWebRedirect("go", "https://example.org", cloudns.WebRedirect{}, 3600),
+		inactive,
+	), DomainFilter{})
+
+	// when
+	endpoints, err := provider.Records(context.Background())
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []*Endpoint{
+		{DNSName: "example.com", RecordType: "MX", RecordTTL: 3600, Targets: []string{"10 mail.example.com"}},
+		{DNSName: "www.example.com", RecordType: "A", RecordTTL: 300, Targets: []string{"192.0.2.1", "192.0.2.2"}},
+		{DNSName: "www.example.com", RecordType: "TXT", RecordTTL: 300,
+			Targets: []string{`"heritage=external-dns,external-dns/owner=default"`}},
+	}, endpoints)
+}
+
+func TestProvider_Records_DomainFilter(t *testing.T) {
+	// given
+	provider := newTestProvider(t, newFakeAccount(
+		cloudns.NewRecordA("www", "192.0.2.1", 300),
+		cloudns.NewRecordA("app", "192.0.2.2", 300),
+		cloudns.NewRecordA("a.app", "192.0.2.3", 300),
+	), DomainFilter{Include: []string{"app.example.com"}})
+
+	// when
+	endpoints, err := provider.Records(context.Background())
+
+	// then
+	assert.NoError(t, err)
+	assert.Len(t, endpoints, 2)
+	assert.Equal(t, "a.app.example.com", endpoints[0].DNSName)
+	assert.Equal(t, "app.example.com", endpoints[1].DNSName)
+}
+
+func TestProvider_ApplyChanges(t *testing.T) {
+	// given
+	account := newFakeAccount(
+		cloudns.NewRecordA("www", "192.0.2.1", 300),
+		cloudns.NewRecordTXT("www", "heritage=external-dns,external-dns/owner=default", 300),
+		cloudns.NewRecordCNAME("old", "www.example.com", 300),
+	)
+	provider := newTestProvider(t, account, DomainFilter{})
+
+	// when
+	err := provider.ApplyChanges(context.Background(), &Changes{
+		Create: []*Endpoint{
+			{DNSName: "api.example.com", RecordType: "A", RecordTTL: 300, Targets: []string{"192.0.2.10"}},
+			{DNSName: "api.example.com", RecordType: "TXT", RecordTTL: 300,
+				Targets: []string{`"heritage=external-dns,external-dns/owner=default"`}},
+		},
+		UpdateOld: []*Endpoint{{DNSName: "www.example.com", RecordType: "A", Targets: []string{"192.0.2.1"}}},
+		UpdateNew: []*Endpoint{{DNSName: "www.example.com", RecordType: "A", RecordTTL: 300, Targets: []string{"192.0.2.5"}}},
+		Delete: []*Endpoint{
+			{DNSName: "old.example.com", RecordType: "CNAME", Targets: []string{"www.example.com"}},
+		},
+	})
+
+	// then
+	assert.NoError(t, err)
+	endpoints, err := provider.Records(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []*Endpoint{
+		{DNSName: "api.example.com", RecordType: "A", RecordTTL: 300, Targets: []string{"192.0.2.10"}},
+		{DNSName: "api.example.com", RecordType: "TXT", RecordTTL: 300,
+			Targets: []string{`"heritage=external-dns,external-dns/owner=default"`}},
+		{DNSName: "www.example.com", RecordType: "A", RecordTTL: 300, Targets: []string{"192.0.2.5"}},
+		{DNSName: "www.example.com", RecordType: "TXT", RecordTTL: 300,
+			Targets: []string{`"heritage=external-dns,external-dns/owner=default"`}},
+	}, endpoints)
+	assert.Equal(t, "heritage=external-dns,external-dns/owner=default", account.records[5].Record,
+		"ownership records should be stored unquoted")
+}
+
+func TestProvider_ApplyChanges_OutsideOfZones(t *testing.T) {
+	// given
+	provider := newTestProvider(t, newFakeAccount(), DomainFilter{})
+
+	// when
+	err := provider.ApplyChanges(context.Background(), &Changes{
+		Create: []*Endpoint{{DNSName: "www.example.net", RecordType: "A", Targets: []string{"192.0.2.1"}}},
+	})
+
+	// then
+	assert.ErrorIs(t, err, cloudns.ErrIllegalArgument, "slave zones should not be managed")
+}
+
+func TestProvider_AdjustEndpoints(t *testing.T) {
+	// given
+	provider := newTestProvider(t, newFakeAccount(), DomainFilter{})
+
+	// when
+	endpoints, err := provider.AdjustEndpoints(context.Background(), []*Endpoint{
+		{DNSName: "a.example.com", RecordType: "A", RecordTTL: 120},
+		{DNSName: "b.example.com", RecordType: "A"},
+		{DNSName: "c.example.com", RecordType: "A", RecordTTL: 604800},
+		{DNSName: "d.example.com", RecordType: "NAPTR", RecordTTL: 300},
+	})
+
+	// then
+	assert.NoError(t, err)
+	assert.Len(t, endpoints, 3, "unsupported record types should be dropped")
+	assert.Equal(t, int64(300), endpoints[0].RecordTTL)
+	assert.Equal(t, int64(3600), endpoints[1].RecordTTL)
+	assert.Equal(t, int64(86400), endpoints[2].RecordTTL)
+}
+
+func TestDomainFilter_Match(t *testing.T) {
+	filter := DomainFilter{Include: []string{"example.com"}, Exclude: []string{"internal.example.com"}}
+
+	assert.True(t, filter.Match("example.com"))
+	assert.True(t, filter.Match("WWW.example.com."))
+	assert.False(t, filter.Match("a.internal.example.com"))
+	assert.False(t, filter.Match("badexample.com"))
+	assert.True(t, DomainFilter{}.Match("anything.local"))
+}
+
+func TestHandler(t *testing.T) {
+	// given
+	account := newFakeAccount(cloudns.NewRecordA("www", "192.0.2.1", 300))
+	handler := NewHandler(newTestProvider(t, account, DomainFilter{Include: []string{"example.com"}}))
+	request := func(method, path, body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Accept", MediaType)
+		handler.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	// when
+	negotiation := request("GET", "/", "")
+	records := request("GET", "/records", "")
+	applied := request("POST", "/records", `{"Create":[{"dnsName":"api.example.com","recordType":"A","recordTTL":300,"targets":["192.0.2.2"]}]}`)
+	invalid := request("POST", "/records", `{"Create":[{"dnsName":"api.example.org","recordType":"A","targets":["192.0.2.2"]}]}`)
+	adjusted := request("POST", "/adjustendpoints", `[{"dnsName":"api.example.com","recordType":"A","recordTTL":100}]`)
+
+	// then
+	assert.Equal(t, http.StatusOK, negotiation.Code)
+	assert.Equal(t, MediaType, negotiation.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"include":["example.com"]}`, negotiation.Body.String())
+
+	assert.Equal(t, http.StatusOK, records.Code)
+	assert.JSONEq(t, `[{"dnsName":"www.example.com","recordType":"A","recordTTL":300,"targets":["192.0.2.1"]}]`,
+		records.Body.String())
+
+	assert.Equal(t, http.StatusNoContent, applied.Code)
+	assert.Len(t, account.records, 2)
+	assert.Equal(t, http.StatusBadRequest, invalid.Code)
+
+	assert.Equal(t, http.StatusOK, adjusted.Code)
+	assert.JSONEq(t, `[{"dnsName":"api.example.com","recordType":"A","recordTTL":300}]`, adjusted.Body.String())
+}
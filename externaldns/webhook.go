@@ -0,0 +1,91 @@
+package externaldns
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/ppmathis/cloudns-go"
+)
+
+// MediaType is the content type of all requests and responses of the external-dns webhook provider contract
+const MediaType = "application/external.dns.webhook+json;version=1"
+
+// NewHandler returns an HTTP handler implementing the external-dns webhook provider contract for the given provider:
+//   - GET / negotiates the contract and returns the domain filter
+//   - GET /records returns all managed records as endpoints
+//   - POST /records applies the posted changes
+//   - POST /adjustendpoints adjusts the posted endpoints
+//   - GET /healthz reports that the webhook is ready
+//
+// The handler should only be reachable by external-dns, e.g. by listening on localhost within the same pod.
+func NewHandler(provider *Provider) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, provider.DomainFilter())
+	})
+
+	mux.HandleFunc("GET /records", func(w http.ResponseWriter, r *http.Request) {
+		endpoints, err := provider.Records(r.Context())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if endpoints == nil {
+			endpoints = []*Endpoint{}
+		}
+		writeJSON(w, http.StatusOK, endpoints)
+	})
+
+	mux.HandleFunc("POST /records", func(w http.ResponseWriter, r *http.Request) {
+		var changes Changes
+		if err := json.NewDecoder(r.Body).Decode(&changes); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := provider.ApplyChanges(r.Context(), &changes); err != nil {
+			writeError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	mux.HandleFunc("POST /adjustendpoints", func(w http.ResponseWriter, r *http.Request) {
+		var endpoints []*Endpoint
+		if err := json.NewDecoder(r.Body).Decode(&endpoints); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		adjusted, err := provider.AdjustEndpoints(r.Context(), endpoints)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, adjusted)
+	})
+
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", MediaType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+// writeError reports invalid changes as bad request and all other failures as internal server error, which causes
+// external-dns to retry them during the next reconciliation
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, cloudns.ErrIllegalArgument) {
+		status = http.StatusBadRequest
+	}
+
+	http.Error(w, err.Error(), status)
+}
@@ -0,0 +1,67 @@
+package cloudns
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// zoneFreezer keeps track of all zones which have been frozen locally. It is shared by all copies of a client.
+type zoneFreezer struct {
+	mutex sync.RWMutex
+	zones map[string]bool
+}
+
+func newZoneFreezer() *zoneFreezer {
+	return &zoneFreezer{zones: make(map[string]bool)}
+}
+
+// FreezeZone marks the given zone as frozen, causing all mutating API calls against it to fail with ErrZoneFrozen
+// without contacting the API until the zone gets unfrozen. This is a purely local policy of the client, which protects
+// critical zones from accidental changes e.g. during incident response.
+func (c *Client) FreezeZone(zoneName string) {
+	c.freezer.mutex.Lock()
+	defer c.freezer.mutex.Unlock()
+
+	c.freezer.zones[normalizeHostname(zoneName)] = true
+}
+
+// UnfreezeZone allows mutating API calls against the given zone again
+func (c *Client) UnfreezeZone(zoneName string) {
+	c.freezer.mutex.Lock()
+	defer c.freezer.mutex.Unlock()
+
+	delete(c.freezer.zones, normalizeHostname(zoneName))
+}
+
+// IsZoneFrozen returns true if the given zone is currently frozen
+func (c *Client) IsZoneFrozen(zoneName string) bool {
+	c.freezer.mutex.RLock()
+	defer c.freezer.mutex.RUnlock()
+
+	return c.freezer.zones[normalizeHostname(zoneName)]
+}
+
+// FrozenZones returns the names of all currently frozen zones in alphabetical order
+func (c *Client) FrozenZones() []string {
+	c.freezer.mutex.RLock()
+	defer c.freezer.mutex.RUnlock()
+
+	zones := make([]string, 0, len(c.freezer.zones))
+	for zone := range c.freezer.zones {
+		zones = append(zones, zone)
+	}
+	sort.Strings(zones)
+
+	return zones
+}
+
+// checkFrozen returns ErrZoneFrozen if the request against the given endpoint would modify a frozen zone
+func (c *Client) checkFrozen(endpoint string, params HTTPParams) error {
+	zoneName, ok := params["domain-name"].(string)
+	if !ok || !c.isMutating(endpoint) || !c.IsZoneFrozen(zoneName) {
+		return nil
+	}
+
+	return ErrZoneFrozen.wrap(fmt.Errorf("zone %s is frozen", normalizeHostname(zoneName)))
+}
@@ -0,0 +1,45 @@
+package cloudns
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestClient_FreezeZone(t *testing.T) {
+	// given
+	var requests []string
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		requests = append(requests, req.URL.Path)
+		if req.URL.Path == recordListURL {
+			return `[]`
+		}
+		return `{"status":"Success"}`
+	}, FreezeZones("API-Example.com."))
+
+	// when
+	_, listErr := stubClient.Records.List(context.Background(), testDomain)
+	_, createErr := stubClient.Records.Create(context.Background(), testDomain, NewRecordA("www", "192.0.2.1", testTTL))
+	_, otherErr := stubClient.Records.Create(context.Background(), "other.example", NewRecordA("www", "192.0.2.1", testTTL))
+	stubClient.UnfreezeZone(testDomain)
+	_, unfrozenErr := stubClient.Records.Delete(context.Background(), testDomain, 1)
+
+	// then
+	assert.NoError(t, listErr, "read-only calls should not be affected")
+	assert.ErrorIs(t, createErr, ErrZoneFrozen)
+	assert.NoError(t, otherErr, "other zones should not be affected")
+	assert.NoError(t, unfrozenErr)
+	assert.Equal(t, []string{recordListURL, recordCreateURL, recordDeleteURL}, requests, "frozen call should not reach api")
+}
+
+func TestClient_FrozenZones(t *testing.T) {
+	client, err := New()
+	assert.NoError(t, err)
+
+	client.FreezeZone("b.example")
+	client.FreezeZone("A.example.")
+
+	assert.True(t, client.IsZoneFrozen("a.example"))
+	assert.Equal(t, []string{"a.example", "b.example"}, client.FrozenZones())
+}
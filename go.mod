@@ -1,8 +1,20 @@
 module github.com/ppmathis/cloudns-go
 
-go 1.16
+go 1.23
 
 require (
+	github.com/miekg/dns v1.1.62
 	github.com/stretchr/testify v1.7.0
 	gopkg.in/dnaeon/go-vcr.v3 v3.1.2
 )
+
+require (
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/mod v0.18.0 // indirect
+	golang.org/x/net v0.27.0 // indirect
+	golang.org/x/sync v0.7.0 // indirect
+	golang.org/x/sys v0.22.0 // indirect
+	golang.org/x/tools v0.22.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
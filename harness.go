@@ -0,0 +1,536 @@
+package cloudns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+)
+
+// harnessRecordHost is the host of all records temporarily created by RunEndpointChecks
+const harnessRecordHost = "_cloudns-go-harness"
+
+// harnessTransferIP is temporarily allowed to transfer the zone by RunEndpointChecks
+var harnessTransferIP = net.ParseIP("192.0.2.53")
+
+// EndpointCheckOptions specifies the environment used by RunEndpointChecks
+type EndpointCheckOptions struct {
+	// Zone is a sacrificial master zone, which gets modified during the checks. All temporarily created records are
+	// removed afterwards, but the SOA serial changes and the zone gets re-activated.
+	Zone string
+	// SourceZone is copied into Zone for checking CopyFromZone, which is skipped if empty
+	SourceZone string
+	// TemporaryZone is created and deleted again for checking the creation and deletion of zones, which is skipped if
+	// empty. It must not exist yet.
+	TemporaryZone string
+	// TransferServer is used for checking ImportTransfer, which is skipped if empty. Beware that a zone transfer
+	// overwrites all records of the zone.
+	TransferServer string
+	// GeoDNSZone is an existing GeoDNS zone used for checking GeoDNS locations, which is skipped if empty
+	GeoDNSZone string
+	// Groups enables checking zone groups by temporarily creating a group containing Zone, which is skipped otherwise.
+	// Zone no longer belongs to any group afterwards.
+	Groups bool
+	// CloudDomain is temporarily added to the DNS cloud of Zone for checking cloud domains, which is skipped if empty. It
+	// must not exist yet. Zone is promoted to cloud master again before the cloud domain gets deleted.
+	CloudDomain string
+	// DNSSEC enables checking the activation and deactivation of DNSSEC for Zone, which is skipped otherwise. DNSSEC
+	// must not be active for the zone yet.
+	DNSSEC bool
+	// Failover enables checking the failover endpoints using a temporarily created record, which is skipped otherwise, as
+	// failover requires a suitable ClouDNS plan
+	Failover bool
+}
+
+// EndpointCheck represents the result of exercising a single wrapped API endpoint
+type EndpointCheck struct {
+	Endpoint Endpoint
+	// Skipped is true if the endpoint could not be checked, e.g. due to missing options or a failed prerequisite
+	Skipped bool
+	Error   error
+}
+
+// endpointCheckStep exercises one or more endpoints of a service method
+type endpointCheckStep struct {
+	service string
+	name    string
+	run     func(ctx context.Context) error
+}
+
+// RunEndpointChecks exercises every wrapped API endpoint end-to-end against the live API using the given client and
+// reports which ones fail. This is useful for validating whether a ClouDNS plan supports all features and for
+// verifying cloudns-go before releases. Only use a sacrificial zone, as the checks modify it.
+func RunEndpointChecks(ctx context.Context, client *Client, options EndpointCheckOptions) ([]EndpointCheck, error) {
+	if options.Zone == "" {
+		return nil, ErrIllegalArgument.wrap(errors.New("sacrificial zone is required"))
+	}
+
+	zone := options.Zone
+	var soa SOA
+	var recordID, mailForwardID, transferServerID, groupID, certificateID int
+	var registeredDomain string
+	errSkipped := errors.New("skipped")
+	requireRecord := func() error {
+		if recordID == 0 {
+			return errSkipped
+		}
+		return nil
+	}
+	requireFailover := func() error {
+		if !options.Failover {
+			return errSkipped
+		}
+		return requireRecord()
+	}
+	failoverSettings := FailoverSettings{
+		CheckType:  1,
+		DownAction: FailoverDownDeactivateRecord,
+		UpAction:   FailoverUpActivateRecord,
+		MainIP:     "192.0.2.2",
+	}
+	requireGroup := func() error {
+		if !options.Groups || groupID == 0 {
+			return errSkipped
+		}
+		return nil
+	}
+	requireDNSSEC := func() error {
+		if !options.DNSSEC {
+			return errSkipped
+		}
+		return nil
+	}
+
+	steps := []endpointCheckStep{
+		{"Account", "Login", func(ctx context.Context) error {
+			_, err := client.Account.Login(ctx)
+			return err
+		}},
+		{"Account", "GetCurrentIP", func(ctx context.Context) error {
+			_, err := client.Account.GetCurrentIP(ctx)
+			return err
+		}},
+		{"Account", "GetBalance", func(ctx context.Context) error {
+			_, err := client.Account.GetBalance(ctx)
+			return err
+		}},
+		{"Zones", "Search", func(ctx context.Context) error {
+			_, err := client.Zones.Search(ctx, zone, 0)
+			return err
+		}},
+		{"Zones", "Create", func(ctx context.Context) error {
+			if options.TemporaryZone == "" {
+				return errSkipped
+			}
+			_, err := client.Zones.Create(ctx, options.TemporaryZone, ZoneTypeMaster, ZoneCreateOptions{})
+			return err
+		}},
+		{"Zones", "Delete", func(ctx context.Context) error {
+			if options.TemporaryZone == "" {
+				return errSkipped
+			}
+			_, err := client.Zones.Delete(ctx, options.TemporaryZone)
+			return err
+		}},
+		{"Zones", "Get", func(ctx context.Context) error {
+			_, err := client.Zones.Get(ctx, zone)
+			return err
+		}},
+		{"Zones", "GetRecordCount", func(ctx context.Context) error {
+			_, err := client.Zones.GetRecordCount(ctx, zone)
+			return err
+		}},
+		{"Zones", "MasterServers", func(ctx context.Context) error {
+			_, err := client.Zones.MasterServers(ctx, zone)
+			return err
+		}},
+		{"Zones", "SetActive", func(ctx context.Context) error {
+			_, err := client.Zones.SetActive(ctx, zone, true)
+			return err
+		}},
+		{"Zones", "TriggerUpdate", func(ctx context.Context) error {
+			_, err := client.Zones.TriggerUpdate(ctx, zone)
+			return err
+		}},
+		{"Zones", "IsUpdated", func(ctx context.Context) error {
+			_, err := client.Zones.IsUpdated(ctx, zone)
+			return err
+		}},
+		{"Zones", "GetUpdateStatus", func(ctx context.Context) error {
+			_, err := client.Zones.GetUpdateStatus(ctx, zone)
+			return err
+		}},
+		{"Zones", "AvailableNameservers", func(ctx context.Context) error {
+			_, err := client.Zones.AvailableNameservers(ctx)
+			return err
+		}},
+		{"Zones", "GetUsage", func(ctx context.Context) error {
+			_, err := client.Zones.GetUsage(ctx)
+			return err
+		}},
+		{"Zones", "Statistics", func(ctx context.Context) error {
+			for _, period := range []StatisticsPeriod{StatisticsHourly, StatisticsDaily, StatisticsMonthly, StatisticsYearly, StatisticsLast30Days} {
+				if _, err := client.Zones.Statistics(ctx, zone, period, client.clock.Now()); err != nil {
+					return err
+				}
+			}
+			return nil
+		}},
+		{"Zones", "CreateGroup", func(ctx context.Context) error {
+			if !options.Groups {
+				return errSkipped
+			}
+			_, err := client.Zones.CreateGroup(ctx, harnessRecordHost, zone)
+			return err
+		}},
+		{"Zones", "Groups", func(ctx context.Context) error {
+			group, _, err := client.Zones.GroupByName(ctx, harnessRecordHost)
+			groupID = group.ID
+			return err
+		}},
+		{"Zones", "RenameGroup", func(ctx context.Context) error {
+			if err := requireGroup(); err != nil {
+				return err
+			}
+			_, err := client.Zones.RenameGroup(ctx, groupID, harnessRecordHost)
+			return err
+		}},
+		{"Zones", "SetGroup", func(ctx context.Context) error {
+			if err := requireGroup(); err != nil {
+				return err
+			}
+			_, err := client.Zones.SetGroup(ctx, zone, groupID)
+			return err
+		}},
+		{"Zones", "DeleteGroup", func(ctx context.Context) error {
+			if err := requireGroup(); err != nil {
+				return err
+			}
+			_, err := client.Zones.DeleteGroup(ctx, groupID)
+			return err
+		}},
+		{"Zones", "AddTransferServer", func(ctx context.Context) error {
+			_, err := client.Zones.AddTransferServer(ctx, zone, harnessTransferIP)
+			return err
+		}},
+		{"Zones", "TransferServers", func(ctx context.Context) error {
+			servers, err := client.Zones.TransferServers(ctx, zone)
+			for _, server := range servers {
+				if server.Server.Equal(harnessTransferIP) {
+					transferServerID = server.ID
+				}
+			}
+			return err
+		}},
+		{"Zones", "RemoveTransferServer", func(ctx context.Context) error {
+			if transferServerID == 0 {
+				return errSkipped
+			}
+			_, err := client.Zones.RemoveTransferServer(ctx, zone, transferServerID)
+			return err
+		}},
+		{"Zones", "ListCloudDomains", func(ctx context.Context) error {
+			_, err := client.Zones.ListCloudDomains(ctx, zone)
+			return err
+		}},
+		{"Zones", "AddCloudDomain", func(ctx context.Context) error {
+			if options.CloudDomain == "" {
+				return errSkipped
+			}
+			_, err := client.Zones.AddCloudDomain(ctx, zone, options.CloudDomain)
+			return err
+		}},
+		{"Zones", "SetCloudMaster", func(ctx context.Context) error {
+			if options.CloudDomain == "" {
+				return errSkipped
+			}
+			_, err := client.Zones.SetCloudMaster(ctx, zone)
+			return err
+		}},
+		{"Zones", "DeleteCloudDomain", func(ctx context.Context) error {
+			if options.CloudDomain == "" {
+				return errSkipped
+			}
+			_, err := client.Zones.DeleteCloudDomain(ctx, options.CloudDomain)
+			return err
+		}},
+		{"Records", "AvailableTTLs", func(ctx context.Context) error {
+			_, err := client.Records.AvailableTTLs(ctx, zone)
+			return err
+		}},
+		{"Records", "AvailableRecordTypes", func(ctx context.Context) error {
+			_, err := client.Records.AvailableRecordTypes(ctx, ZoneTypeMaster, ZoneKindDomain)
+			return err
+		}},
+		{"Records", "GeoDNSLocations", func(ctx context.Context) error {
+			if options.GeoDNSZone == "" {
+				return errSkipped
+			}
+			_, err := client.Records.GeoDNSLocations(ctx, options.GeoDNSZone)
+			return err
+		}},
+		{"Records", "GetSOA", func(ctx context.Context) (err error) {
+			soa, err = client.Records.GetSOA(ctx, zone)
+			return
+		}},
+		{"Records", "UpdateSOA", func(ctx context.Context) error {
+			if soa.PrimaryNS == "" {
+				return errSkipped
+			}
+			_, err := client.Records.UpdateSOA(ctx, zone, soa)
+			return err
+		}},
+		{"Records", "Create", func(ctx context.Context) error {
+			_, err := client.Records.Create(ctx, zone, NewRecordA(harnessRecordHost, "192.0.2.1", 3600))
+			return err
+		}},
+		{"Records", "Search", func(ctx context.Context) error {
+			records, err := client.Records.Search(ctx, zone, harnessRecordHost, RecordTypeA)
+			for _, record := range records.SortedSlice() {
+				recordID = record.ID
+			}
+			return err
+		}},
+		{"Records", "Update", func(ctx context.Context) error {
+			if err := requireRecord(); err != nil {
+				return err
+			}
+			_, err := client.Records.Update(ctx, zone, recordID, NewRecordA(harnessRecordHost, "192.0.2.2", 3600))
+			return err
+		}},
+		{"Records", "SetActive", func(ctx context.Context) error {
+			if err := requireRecord(); err != nil {
+				return err
+			}
+			_, err := client.Records.SetActive(ctx, zone, recordID, false)
+			return err
+		}},
+		{"Records", "GetDynamicURL", func(ctx context.Context) error {
+			if err := requireRecord(); err != nil {
+				return err
+			}
+			_, err := client.Records.GetDynamicURL(ctx, zone, recordID)
+			return err
+		}},
+		{"Records", "ChangeDynamicURL", func(ctx context.Context) error {
+			if err := requireRecord(); err != nil {
+				return err
+			}
+			_, err := client.Records.ChangeDynamicURL(ctx, zone, recordID)
+			return err
+		}},
+		{"Records", "DisableDynamicURL", func(ctx context.Context) error {
+			if err := requireRecord(); err != nil {
+				return err
+			}
+			_, err := client.Records.DisableDynamicURL(ctx, zone, recordID)
+			return err
+		}},
+		{"Failover", "Activate", func(ctx context.Context) error {
+			if err := requireFailover(); err != nil {
+				return err
+			}
+			_, err := client.Failover.Activate(ctx, zone, recordID, failoverSettings)
+			return err
+		}},
+		{"Failover", "Get", func(ctx context.Context) error {
+			if err := requireFailover(); err != nil {
+				return err
+			}
+			_, err := client.Failover.Get(ctx, zone, recordID)
+			return err
+		}},
+		{"Failover", "Update", func(ctx context.Context) error {
+			if err := requireFailover(); err != nil {
+				return err
+			}
+			_, err := client.Failover.Update(ctx, zone, recordID, failoverSettings)
+			return err
+		}},
+		{"Failover", "GetState", func(ctx context.Context) error {
+			if err := requireFailover(); err != nil {
+				return err
+			}
+			_, err := client.Failover.GetState(ctx, zone, recordID)
+			return err
+		}},
+		{"Failover", "Deactivate", func(ctx context.Context) error {
+			if err := requireFailover(); err != nil {
+				return err
+			}
+			_, err := client.Failover.Deactivate(ctx, zone, recordID)
+			return err
+		}},
+		{"Records", "Delete", func(ctx context.Context) error {
+			if err := requireRecord(); err != nil {
+				return err
+			}
+			_, err := client.Records.Delete(ctx, zone, recordID)
+			return err
+		}},
+		{"Records", "Export", func(ctx context.Context) error {
+			_, err := client.Records.Export(ctx, zone)
+			return err
+		}},
+		{"Records", "Import", func(ctx context.Context) error {
+			content := fmt.Sprintf("%s.%s. 3600 IN TXT \"cloudns-go\"\n", harnessRecordHost, normalizeHostname(zone))
+			if _, err := client.Records.Import(ctx, zone, RecordFormatBIND, content, false); err != nil {
+				return err
+			}
+
+			records, err := client.Records.Search(ctx, zone, harnessRecordHost, RecordTypeTXT)
+			for _, record := range records.SortedSlice() {
+				if _, err := client.Records.Delete(ctx, zone, record.ID); err != nil {
+					return err
+				}
+			}
+			return err
+		}},
+		{"Records", "CopyFromZone", func(ctx context.Context) error {
+			if options.SourceZone == "" {
+				return errSkipped
+			}
+			_, err := client.Records.CopyFromZone(ctx, zone, options.SourceZone, false)
+			return err
+		}},
+		{"Records", "ImportTransfer", func(ctx context.Context) error {
+			if options.TransferServer == "" {
+				return errSkipped
+			}
+			_, err := client.Records.ImportTransfer(ctx, zone, options.TransferServer)
+			return err
+		}},
+		{"MailForwards", "Create", func(ctx context.Context) error {
+			forward := MailForward{Box: harnessRecordHost, Destination: "cloudns-go@example.com"}
+			_, err := client.MailForwards.Create(ctx, zone, forward)
+			return err
+		}},
+		{"MailForwards", "List", func(ctx context.Context) error {
+			forwards, err := client.MailForwards.List(ctx, zone)
+			for _, forward := range forwards.SortedSlice() {
+				if forward.Box == harnessRecordHost {
+					mailForwardID = forward.ID
+				}
+			}
+			return err
+		}},
+		{"MailForwards", "Delete", func(ctx context.Context) error {
+			if mailForwardID == 0 {
+				return errSkipped
+			}
+			_, err := client.MailForwards.Delete(ctx, zone, mailForwardID)
+			return err
+		}},
+		{"DNSSEC", "IsAvailable", func(ctx context.Context) error {
+			_, err := client.DNSSEC.IsAvailable(ctx, zone)
+			return err
+		}},
+		{"DNSSEC", "Activate", func(ctx context.Context) error {
+			if err := requireDNSSEC(); err != nil {
+				return err
+			}
+			_, err := client.DNSSEC.Activate(ctx, zone)
+			return err
+		}},
+		{"DNSSEC", "GetDSRecords", func(ctx context.Context) error {
+			if err := requireDNSSEC(); err != nil {
+				return err
+			}
+			_, err := client.DNSSEC.GetDSRecords(ctx, zone)
+			return err
+		}},
+		{"DNSSEC", "SetOptOut", func(ctx context.Context) error {
+			if err := requireDNSSEC(); err != nil {
+				return err
+			}
+			_, err := client.DNSSEC.SetOptOut(ctx, zone, false)
+			return err
+		}},
+		{"DNSSEC", "Deactivate", func(ctx context.Context) error {
+			if err := requireDNSSEC(); err != nil {
+				return err
+			}
+			_, err := client.DNSSEC.Deactivate(ctx, zone)
+			return err
+		}},
+		{"Domains", "CheckAvailability", func(ctx context.Context) error {
+			_, err := client.Domains.CheckAvailability(ctx, "cloudns-go-harness", "com")
+			return err
+		}},
+		{"Domains", "List", func(ctx context.Context) error {
+			domains, err := client.Domains.List(ctx)
+			if len(domains) > 0 {
+				registeredDomain = domains[0].Name
+			}
+			return err
+		}},
+		{"Domains", "Get", func(ctx context.Context) error {
+			if registeredDomain == "" {
+				return errSkipped
+			}
+			_, err := client.Domains.Get(ctx, registeredDomain)
+			return err
+		}},
+		{"Domains", "GetTransferCode", func(ctx context.Context) error {
+			if registeredDomain == "" {
+				return errSkipped
+			}
+			_, err := client.Domains.GetTransferCode(ctx, registeredDomain)
+			return err
+		}},
+		{"Domains", "GetContacts", func(ctx context.Context) error {
+			if registeredDomain == "" {
+				return errSkipped
+			}
+			_, err := client.Domains.GetContacts(ctx, registeredDomain)
+			return err
+		}},
+		// Contacts of registered domains are outside the sacrificial zone and therefore never modified
+		{"Domains", "UpdateContact", func(ctx context.Context) error { return errSkipped }},
+		// Toggling privacy protection is not reverted reliably by all registries and therefore never checked
+		{"Domains", "SetWhoisPrivacy", func(ctx context.Context) error { return errSkipped }},
+		// Domain orders are charged to the account balance and therefore never checked
+		{"Domains", "Register", func(ctx context.Context) error { return errSkipped }},
+		{"Domains", "Transfer", func(ctx context.Context) error { return errSkipped }},
+		{"Domains", "Renew", func(ctx context.Context) error { return errSkipped }},
+		{"SSL", "List", func(ctx context.Context) error {
+			certificates, err := client.SSL.List(ctx)
+			if len(certificates) > 0 {
+				certificateID = certificates[0].ID
+			}
+			return err
+		}},
+		{"SSL", "GetInfo", func(ctx context.Context) error {
+			if certificateID == 0 {
+				return errSkipped
+			}
+			_, err := client.SSL.GetInfo(ctx, certificateID)
+			return err
+		}},
+		// Certificate orders are charged and reissuing replaces the current certificate, so both are never checked
+		{"SSL", "Order", func(ctx context.Context) error { return errSkipped }},
+		{"SSL", "Reissue", func(ctx context.Context) error { return errSkipped }},
+	}
+
+	var results []EndpointCheck
+	for _, step := range steps {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+
+		err := step.run(ctx)
+		for _, endpoint := range endpoints {
+			if endpoint.Service != step.service || endpoint.Name != step.name {
+				continue
+			}
+
+			check := EndpointCheck{Endpoint: endpoint, Error: err}
+			if errors.Is(err, errSkipped) {
+				check.Skipped, check.Error = true, nil
+			}
+			results = append(results, check)
+		}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,75 @@
+package cloudns
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestRunEndpointChecks(t *testing.T) {
+	// given
+	responses := map[string]string{
+		zonePageCountURL:              `1`,
+		zoneListURL:                   `[]`,
+		zoneRecordCountURL:            `1`,
+		zoneMasterServersURL:          `[]`,
+		zoneIsUpdatedURL:              `true`,
+		zoneUpdateStatusURL:           `[]`,
+		zoneAvailableNameserversURL:   `[]`,
+		recordAvailableTTLsURL:        `[60,3600]`,
+		recordAvailableRecordTypesURL: `["A","TXT"]`,
+		recordSOAGetURL:               `{"serialNumber":"1","primaryNS":"ns1.example.com","adminMail":"admin@example.com","refresh":"1","retry":"1","expire":"1","defaultTTL":"1"}`,
+		recordListURL:                 `{"5":{"id":"5","host":"_cloudns-go-harness","record":"192.0.2.1","type":"A","ttl":"3600","status":1}}`,
+		recordDisableDynamicURL:       `{"status":"Failed","statusDescription":"Not supported"}`,
+		dnssecAvailableURL:            `{"status":1}`,
+		cloudDomainListURL:            `[]`,
+		groupListURL:                  `[]`,
+		transferListURL:               `{"9":{"id":"9","server":"192.0.2.53"}}`,
+		mailForwardListURL:            `{"3":{"id":"3","box":"_cloudns-go-harness","host":"","destination":"cloudns-go@example.com","status":"1"}}`,
+		domainCheckAvailableURL:       `{"cloudns-go-harness.com":{"status":"1"}}`,
+		domainListURL:                 `{"example.com":{"name":"example.com","status":"active"}}`,
+		sslListURL:                    `{"4":{"id":"4","domain":"example.com","type":"PositiveSSL","status":"active"}}`,
+		sslInfoURL:                    `{"id":"4","domain":"example.com","type":"PositiveSSL","status":"active"}`,
+	}
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		if response, ok := responses[req.URL.Path]; ok {
+			return response
+		}
+		return `{"status":"Success","statusDescription":"OK"}`
+	})
+
+	// when
+	results, err := RunEndpointChecks(context.Background(), stubClient, EndpointCheckOptions{Zone: testDomain})
+
+	// then
+	assert.NoError(t, err)
+	assert.Len(t, results, len(Endpoints()), "every wrapped endpoint should be checked exactly once")
+
+	checked := make(map[string]EndpointCheck)
+	for _, result := range results {
+		checked[result.Endpoint.Path] = result
+	}
+	for _, endpoint := range Endpoints() {
+		assert.Contains(t, checked, endpoint.Path, "endpoint %s.%s should be checked", endpoint.Service, endpoint.Name)
+	}
+
+	assert.ErrorIs(t, checked[recordDisableDynamicURL].Error, ErrAPIInvocation, "failures should be reported")
+	assert.True(t, checked[recordCopyFromZoneURL].Skipped, "checks without required options should be skipped")
+	assert.True(t, checked[recordImportTransferURL].Skipped, "checks without required options should be skipped")
+	assert.True(t, checked[dnssecActivateURL].Skipped, "dnssec should only be checked if enabled")
+	assert.NoError(t, checked[recordDeleteURL].Error)
+	assert.False(t, checked[mailForwardDeleteURL].Skipped, "created mail forward should be deleted")
+	assert.False(t, checked[transferRemoveURL].Skipped, "added transfer server should be removed")
+	assert.True(t, checked[domainRegisterURL].Skipped, "domain orders should never be checked")
+	assert.False(t, checked[domainInfoURL].Skipped, "listed domain should be checked")
+	assert.False(t, checked[sslInfoURL].Skipped, "listed certificate should be checked")
+}
+
+func TestRunEndpointChecks_MissingZone(t *testing.T) {
+	client, err := New()
+	assert.NoError(t, err)
+
+	_, err = RunEndpointChecks(context.Background(), client, EndpointCheckOptions{})
+	assert.ErrorIs(t, err, ErrIllegalArgument)
+}
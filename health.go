@@ -0,0 +1,74 @@
+package cloudns
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// HealthStatus is an enumeration of the possible outcomes of Client.Ping
+type HealthStatus int
+
+// Enumeration values for HealthStatus
+const (
+	// HealthStatusHealthy indicates that the API is reachable and accepted the credentials
+	HealthStatusHealthy HealthStatus = iota
+	// HealthStatusUnauthorized indicates that the API is reachable, but rejected the credentials
+	HealthStatusUnauthorized
+	// HealthStatusUnreachable indicates that the API could not be reached or returned an invalid response
+	HealthStatusUnreachable
+	// HealthStatusThrottled indicates that the API is reachable, but rejected the call due to its rate limit
+	HealthStatusThrottled
+	// HealthStatusDegraded indicates that the API is reachable, but failed the call for any other reason
+	HealthStatusDegraded
+)
+
+// Health represents the result of a health check against the ClouDNS API
+type Health struct {
+	Status  HealthStatus
+	Latency time.Duration
+}
+
+// String returns a human-readable name of the health status
+func (status HealthStatus) String() string {
+	switch status {
+	case HealthStatusHealthy:
+		return "healthy"
+	case HealthStatusUnauthorized:
+		return "unauthorized"
+	case HealthStatusUnreachable:
+		return "unreachable"
+	case HealthStatusThrottled:
+		return "throttled"
+	case HealthStatusDegraded:
+		return "degraded"
+	}
+
+	return "unknown"
+}
+
+// Ping performs a lightweight authenticated call against the ClouDNS API and returns the health status together with
+// the latency of the call, which is intended for readiness probes of services embedding the client. The latency includes
+// time spent waiting for the rate limiter of the client, if any. The error of the call is returned for all statuses
+// except HealthStatusHealthy.
+func (c *Client) Ping(ctx context.Context) (Health, error) {
+	startedAt := c.clock.Now()
+	_, err := c.Account.Login(ctx)
+	health := Health{Latency: c.clock.Now().Sub(startedAt)}
+
+	var apiErr *APIError
+	switch {
+	case err == nil:
+		health.Status = HealthStatusHealthy
+	case errors.Is(err, ErrAuthFailed):
+		health.Status = HealthStatusUnauthorized
+	case errors.Is(err, ErrRateLimited):
+		health.Status = HealthStatusThrottled
+	case errors.As(err, &apiErr):
+		health.Status = HealthStatusDegraded
+	default:
+		health.Status = HealthStatusUnreachable
+	}
+
+	return health, err
+}
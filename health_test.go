@@ -0,0 +1,72 @@
+package cloudns
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestClient_Ping(t *testing.T) {
+	// given
+	clock := NewManualClock(time.Date(2022, 12, 24, 0, 0, 0, 0, time.UTC))
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		clock.Advance(42 * time.Millisecond)
+		return `{"status":"Success","statusDescription":"Success login."}`
+	}, CustomClock(clock))
+
+	// when
+	health, err := stubClient.Ping(context.Background())
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, Health{Status: HealthStatusHealthy, Latency: 42 * time.Millisecond}, health)
+}
+
+func TestClient_Ping_Unauthorized(t *testing.T) {
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `{"status":"Failed","statusDescription":"Invalid authentication, incorrect auth-id or auth-password."}`
+	})
+
+	health, err := stubClient.Ping(context.Background())
+	assert.ErrorIs(t, err, ErrAPIInvocation)
+	assert.Equal(t, HealthStatusUnauthorized, health.Status)
+}
+
+func TestClient_Ping_Throttled(t *testing.T) {
+	transport := stubTransport(func(req *http.Request) (*http.Response, error) {
+		return newStatusResponse(http.StatusTooManyRequests, ""), nil
+	})
+	throttledClient, err := New(HTTPClient(&http.Client{Transport: transport}))
+	assert.NoError(t, err)
+
+	health, err := throttledClient.Ping(context.Background())
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.Equal(t, HealthStatusThrottled, health.Status, "throttled api should not be reported as unauthorized")
+	assert.Equal(t, "throttled", health.Status.String())
+}
+
+func TestClient_Ping_Degraded(t *testing.T) {
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `{"status":"Failed","statusDescription":"Internal error, please try again later."}`
+	})
+
+	health, err := stubClient.Ping(context.Background())
+	assert.ErrorIs(t, err, ErrAPIInvocation)
+	assert.Equal(t, HealthStatusDegraded, health.Status)
+}
+
+func TestClient_Ping_Unreachable(t *testing.T) {
+	transport := stubTransport(func(req *http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	})
+	unreachableClient, err := New(HTTPClient(&http.Client{Transport: transport}))
+	assert.NoError(t, err)
+
+	health, err := unreachableClient.Ping(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, HealthStatusUnreachable, health.Status)
+	assert.Equal(t, "unreachable", health.Status.String())
+}
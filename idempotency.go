@@ -0,0 +1,155 @@
+package cloudns
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// idempotentEndpoints contains all creating API endpoints whose successful requests are tracked by the Idempotency
+// option, as sending them twice creates duplicate objects
+var idempotentEndpoints = []string{
+	recordCreateURL,
+	zoneRegisterURL,
+	mailForwardCreateURL,
+	cloudDomainAddURL,
+	transferAddURL,
+}
+
+// idempotencyTracker remembers the fingerprints of successful creating requests for a limited duration, as well as of
+// creating requests which failed ambiguously and might have been processed by ClouDNS anyway
+type idempotencyTracker struct {
+	mutex   sync.Mutex
+	window  time.Duration
+	entries map[string]idempotencyEntry
+}
+
+type idempotencyEntry struct {
+	zone      string
+	expiresAt time.Time
+	respBody  []byte
+	// pending is true if the request failed ambiguously, in which case no response is available
+	pending bool
+}
+
+func newIdempotencyTracker(window time.Duration) *idempotencyTracker {
+	return &idempotencyTracker{window: window, entries: make(map[string]idempotencyEntry)}
+}
+
+// idempotencyKey returns the fingerprint of a request against the given endpoint, or an empty string if the endpoint is
+// not tracked or tracking is disabled
+func (c *Client) idempotencyKey(endpoint string, params map[string]interface{}) string {
+	if c.idempotency == nil || !containsString(endpoint, idempotentEndpoints) {
+		return ""
+	}
+
+	jsonParams, err := json.Marshal(params)
+	if err != nil {
+		return ""
+	}
+
+	hash := sha256.Sum256(append([]byte(c.baseURL+endpoint+"\n"), jsonParams...))
+	return hex.EncodeToString(hash[:])
+}
+
+// lookup returns the response of an identical request which succeeded within the window. If the identical request
+// failed ambiguously instead, pending is true and no response is returned.
+func (tracker *idempotencyTracker) lookup(key string, now time.Time) (respBody []byte, pending bool, ok bool) {
+	if key == "" {
+		return nil, false, false
+	}
+
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	entry, ok := tracker.entries[key]
+	if !ok || !now.Before(entry.expiresAt) {
+		delete(tracker.entries, key)
+		return nil, false, false
+	}
+
+	return entry.respBody, entry.pending, true
+}
+
+// record stores the response of a successful request. Any other successful mutating request against the same zone
+// forgets all tracked requests of that zone, as recreating a deleted record must not be skipped.
+func (tracker *idempotencyTracker) record(key string, params map[string]interface{}, now time.Time, respBody []byte) {
+	zone := fmt.Sprint(params["domain-name"])
+
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	for existingKey, entry := range tracker.entries {
+		if entry.zone == zone || !now.Before(entry.expiresAt) {
+			delete(tracker.entries, existingKey)
+		}
+	}
+
+	if key != "" {
+		tracker.entries[key] = idempotencyEntry{zone: zone, expiresAt: now.Add(tracker.window), respBody: respBody}
+	}
+}
+
+// recordPending stores the fingerprint of a creating request which failed ambiguously, e.g. due to a timeout, so that an
+// identical request within the window checks whether the object has been created before sending it again
+func (tracker *idempotencyTracker) recordPending(key string, params map[string]interface{}, now time.Time) {
+	if key == "" {
+		return
+	}
+
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	zone := fmt.Sprint(params["domain-name"])
+	tracker.entries[key] = idempotencyEntry{zone: zone, expiresAt: now.Add(tracker.window), pending: true}
+}
+
+// isAmbiguousError returns true if a request might have been processed by ClouDNS despite failing, which is the case
+// for all failures except for errors reported by the API and client errors with a definite HTTP status
+func isAmbiguousError(err error) bool {
+	var statusErr httpStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.statusCode >= 500
+	}
+
+	return !errors.Is(err, ErrAPIInvocation)
+}
+
+// findCreatedRecord checks whether a record creation which failed ambiguously has been processed by ClouDNS anyway by
+// searching for an equivalent record, returning a successful response if it exists. Pending requests against other
+// endpoints can not be verified and are always sent again.
+func (c *Client) findCreatedRecord(ctx context.Context, endpoint string, params map[string]interface{}) ([]byte, bool, error) {
+	if endpoint != recordCreateURL {
+		return nil, false, nil
+	}
+
+	recordType := RecordType(fmt.Sprint(params["record-type"]))
+	record := Record{Host: fmt.Sprint(params["host"]), RecordType: recordType, Record: fmt.Sprint(params["record"])}
+
+	searchParams := HTTPParams{"domain-name": params["domain-name"], "type": recordType}
+	if record.Host != "" {
+		searchParams["host"] = record.Host
+	}
+
+	var existing RecordMap
+	if err := c.request(WithForceRefresh(ctx), "POST", recordListURL, searchParams, nil, &existing); err != nil {
+		return nil, false, err
+	}
+
+	for _, candidate := range existing {
+		isEquivalent := normalizeRecordHost(candidate.Host) == normalizeRecordHost(record.Host) &&
+			candidate.RecordType == record.RecordType &&
+			normalizeRecordValue(candidate) == normalizeRecordValue(record) &&
+			fmt.Sprint(candidate.TTL) == fmt.Sprint(params["ttl"])
+		if isEquivalent {
+			return []byte(`{"status":"Success","statusDescription":"The record was added successfully."}`), true, nil
+		}
+	}
+
+	return nil, false, nil
+}
@@ -0,0 +1,118 @@
+package cloudns
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestIdempotency(t *testing.T) {
+	// given
+	var requests []string
+	clock := NewManualClock(time.Date(2022, 12, 24, 0, 0, 0, 0, time.UTC))
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		requests = append(requests, req.URL.Path)
+		return `{"status":"Success","statusDescription":"The record was added successfully."}`
+	}, Idempotency(time.Minute), CustomClock(clock))
+	record := NewRecordA("www", "192.0.2.1", testTTL)
+
+	// when
+	result1, err1 := stubClient.Records.Create(context.Background(), testDomain, record)
+	result2, err2 := stubClient.Records.Create(context.Background(), testDomain, record)
+	_, _ = stubClient.Records.Create(context.Background(), "other-"+testDomain, record)
+
+	// then
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+	assert.Equal(t, result1, result2, "previous response should be returned")
+	assert.Equal(t, []string{recordCreateURL, recordCreateURL}, requests, "identical create should be skipped")
+
+	// when
+	clock.Advance(time.Minute)
+	_, _ = stubClient.Records.Create(context.Background(), testDomain, record)
+
+	// then
+	assert.Len(t, requests, 3, "create should be sent again after the window")
+}
+
+func TestIdempotency_OtherMutation(t *testing.T) {
+	// given
+	var requests []string
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		requests = append(requests, req.URL.Path)
+		return `{"status":"Success","statusDescription":"OK"}`
+	}, Idempotency(time.Hour))
+	record := NewRecordA("www", "192.0.2.1", testTTL)
+
+	// when
+	_, _ = stubClient.Records.Create(context.Background(), testDomain, record)
+	_, _ = stubClient.Records.Delete(context.Background(), testDomain, 1)
+	_, _ = stubClient.Records.Create(context.Background(), testDomain, record)
+
+	// then
+	assert.Equal(t, []string{recordCreateURL, recordDeleteURL, recordCreateURL}, requests,
+		"create should be sent again after other mutations of the zone")
+}
+
+func TestIdempotency_Failed(t *testing.T) {
+	var requests int
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		requests++
+		return `{"status":"Failed","statusDescription":"Invalid record"}`
+	}, Idempotency(time.Hour))
+	record := NewRecordA("www", "192.0.2.1", testTTL)
+
+	_, _ = stubClient.Records.Create(context.Background(), testDomain, record)
+	_, _ = stubClient.Records.Create(context.Background(), testDomain, record)
+	assert.Equal(t, 2, requests, "failed requests should not be tracked")
+}
+
+func newTimeoutClient(t *testing.T, requests *[]string, createdRecords string) *Client {
+	transport := stubTransport(func(req *http.Request) (*http.Response, error) {
+		*requests = append(*requests, req.URL.Path)
+		if req.URL.Path == recordListURL {
+			return newStubResponse(createdRecords), nil
+		}
+		return nil, errors.New("i/o timeout")
+	})
+
+	timeoutClient, err := New(HTTPClient(&http.Client{Transport: transport}), Idempotency(time.Hour))
+	if err != nil {
+		t.Fatalf("could not create timeout client: %v", err)
+	}
+
+	return timeoutClient
+}
+
+func TestIdempotency_AmbiguousFailure(t *testing.T) {
+	// given
+	var requests []string
+	timeoutClient := newTimeoutClient(t, &requests,
+		`{"1":{"id":"1","host":"www","record":"192.0.2.1","type":"A","ttl":"3600","status":1}}`)
+	record := NewRecordA("www", "192.0.2.1", testTTL)
+
+	// when
+	_, err1 := timeoutClient.Records.Create(context.Background(), testDomain, record)
+	_, err2 := timeoutClient.Records.Create(context.Background(), testDomain, record)
+	_, err3 := timeoutClient.Records.Create(context.Background(), testDomain, record)
+
+	// then
+	assert.Error(t, err1)
+	assert.NoError(t, err2, "record created by the ambiguous request should be detected")
+	assert.NoError(t, err3)
+	assert.Equal(t, []string{recordCreateURL, recordListURL}, requests, "create should not be sent again")
+}
+
+func TestIdempotency_AmbiguousFailure_NotCreated(t *testing.T) {
+	var requests []string
+	timeoutClient := newTimeoutClient(t, &requests, `[]`)
+	record := NewRecordA("www", "192.0.2.1", testTTL)
+
+	_, _ = timeoutClient.Records.Create(context.Background(), testDomain, record)
+	_, _ = timeoutClient.Records.Create(context.Background(), testDomain, record)
+	assert.Equal(t, []string{recordCreateURL, recordListURL, recordCreateURL}, requests,
+		"create should be sent again if the record does not exist")
+}
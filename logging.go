@@ -0,0 +1,52 @@
+package cloudns
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"time"
+)
+
+// logRequest logs an outbound request at debug level, with sensitive parameters like credentials being redacted
+func (c *Client) logRequest(ctx context.Context, method, endpoint string, params map[string]interface{}) {
+	if c.logger == nil || !c.logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+
+	sanitized := sanitizeParams(params)
+	keys := make([]string, 0, len(sanitized))
+	for key := range sanitized {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]any, 0, len(keys))
+	for _, key := range keys {
+		attrs = append(attrs, slog.String(key, sanitized[key]))
+	}
+
+	c.logger.DebugContext(ctx, "cloudns api request",
+		slog.String("method", method),
+		slog.String("endpoint", endpoint),
+		slog.Group("params", attrs...),
+	)
+}
+
+// logResponse logs an inbound response at debug level. The response body is not logged, as it might contain secrets
+// like transfer codes.
+func (c *Client) logResponse(ctx context.Context, endpoint string, duration time.Duration, respBody []byte, err error) {
+	if c.logger == nil || !c.logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+
+	attrs := []any{
+		slog.String("endpoint", endpoint),
+		slog.Duration("duration", duration),
+		slog.Int("size", len(respBody)),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+
+	c.logger.DebugContext(ctx, "cloudns api response", attrs...)
+}
@@ -0,0 +1,45 @@
+package cloudns
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"net/http"
+	"testing"
+)
+
+func TestLogger(t *testing.T) {
+	// given
+	var output bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&output, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `{"status":"Failed","statusDescription":"Invalid record-id param."}`
+	}, Logger(logger), AuthSubUserName("operator", "s3cr3t"))
+
+	// when
+	_, _ = stubClient.Records.Delete(context.Background(), testDomain, 42)
+
+	// then
+	logs := output.String()
+	assert.Contains(t, logs, `msg="cloudns api request" method=POST endpoint=/dns/delete-record.json`)
+	assert.Contains(t, logs, "params.domain-name=api-example.com params.record-id=42")
+	assert.Contains(t, logs, "params.sub-auth-user=[redacted]")
+	assert.Contains(t, logs, "params.auth-password=[redacted]")
+	assert.NotContains(t, logs, "s3cr3t")
+	assert.NotContains(t, logs, "operator")
+	assert.Contains(t, logs, `msg="cloudns api response" endpoint=/dns/delete-record.json`)
+	assert.Contains(t, logs, `error="api invocation failed: Invalid record-id param."`)
+}
+
+func TestLogger_InfoLevel(t *testing.T) {
+	var output bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&output, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `[]`
+	}, Logger(logger))
+
+	_, err := stubClient.Records.List(context.Background(), testDomain)
+	assert.NoError(t, err)
+	assert.Empty(t, output.String(), "requests should only be logged at debug level")
+}
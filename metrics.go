@@ -0,0 +1,183 @@
+package cloudns
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Enumeration values for the status of requests passed to MetricsRecorder
+const (
+	RequestStatusSuccess   = "success"
+	RequestStatusThrottled = "throttled"
+	RequestStatusAPIError  = "api_error"
+	RequestStatusHTTPError = "http_error"
+)
+
+// MetricsRecorder receives measurements of all requests sent to the ClouDNS API, see the Metrics option. Implementations
+// must be safe for concurrent use, e.g. by forwarding the measurements to Prometheus collectors.
+type MetricsRecorder interface {
+	// ObserveRequest is called after every attempt of sending a request to the given endpoint, with status being one of
+	// the RequestStatus constants
+	ObserveRequest(endpoint, status string, duration time.Duration)
+	// ObserveRetry is called whenever a failed request to the given endpoint is about to be retried
+	ObserveRetry(endpoint string)
+}
+
+// DefaultMetricsBuckets are the upper bounds in seconds of the latency histogram buckets used by RequestMetrics
+var DefaultMetricsBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// RequestMetrics is a MetricsRecorder which aggregates all measurements in-memory and exposes them in the text-based
+// exposition format of Prometheus, without depending on its client library. It serves as http.Handler for scraping,
+// exposing the following metrics:
+//   - cloudns_api_request_duration_seconds: histogram of request latencies by endpoint and status
+//   - cloudns_api_retries_total: counter of retried requests by endpoint
+type RequestMetrics struct {
+	buckets []float64
+
+	mutex    sync.Mutex
+	requests map[requestMetricsKey]*requestHistogram
+	retries  map[string]uint64
+}
+
+type requestMetricsKey struct {
+	endpoint string
+	status   string
+}
+
+type requestHistogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewRequestMetrics instantiates a new RequestMetrics using the given latency histogram buckets in seconds, defaulting to
+// DefaultMetricsBuckets
+func NewRequestMetrics(buckets ...float64) *RequestMetrics {
+	if len(buckets) == 0 {
+		buckets = DefaultMetricsBuckets
+	}
+
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+
+	return &RequestMetrics{
+		buckets:  sorted,
+		requests: make(map[requestMetricsKey]*requestHistogram),
+		retries:  make(map[string]uint64),
+	}
+}
+
+// ObserveRequest records the latency of a request within the histogram of its endpoint and status
+func (metrics *RequestMetrics) ObserveRequest(endpoint, status string, duration time.Duration) {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+
+	key := requestMetricsKey{endpoint: endpoint, status: status}
+	histogram, ok := metrics.requests[key]
+	if !ok {
+		histogram = &requestHistogram{counts: make([]uint64, len(metrics.buckets))}
+		metrics.requests[key] = histogram
+	}
+
+	seconds := duration.Seconds()
+	for i, bound := range metrics.buckets {
+		if seconds <= bound {
+			histogram.counts[i]++
+		}
+	}
+	histogram.count++
+	histogram.sum += seconds
+}
+
+// ObserveRetry increments the retry counter of the endpoint
+func (metrics *RequestMetrics) ObserveRetry(endpoint string) {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+
+	metrics.retries[endpoint]++
+}
+
+// WriteTo writes all metrics in the text-based exposition format of Prometheus to the given writer
+func (metrics *RequestMetrics) WriteTo(w io.Writer) (int64, error) {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+
+	var builder strings.Builder
+	builder.WriteString("# HELP cloudns_api_request_duration_seconds Latency of requests sent to the ClouDNS API.\n")
+	builder.WriteString("# TYPE cloudns_api_request_duration_seconds histogram\n")
+
+	keys := make([]requestMetricsKey, 0, len(metrics.requests))
+	for key := range metrics.requests {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].endpoint != keys[j].endpoint {
+			return keys[i].endpoint < keys[j].endpoint
+		}
+		return keys[i].status < keys[j].status
+	})
+
+	for _, key := range keys {
+		histogram := metrics.requests[key]
+		labels := fmt.Sprintf(`endpoint="%s",status="%s"`, escapeLabelValue(key.endpoint), escapeLabelValue(key.status))
+		for i, bound := range metrics.buckets {
+			fmt.Fprintf(&builder, "cloudns_api_request_duration_seconds_bucket{%s,le=\"%s\"} %d\n", labels,
+				strconv.FormatFloat(bound, 'g', -1, 64), histogram.counts[i])
+		}
+		fmt.Fprintf(&builder, "cloudns_api_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels, histogram.count)
+		fmt.Fprintf(&builder, "cloudns_api_request_duration_seconds_sum{%s} %s\n", labels,
+			strconv.FormatFloat(histogram.sum, 'g', -1, 64))
+		fmt.Fprintf(&builder, "cloudns_api_request_duration_seconds_count{%s} %d\n", labels, histogram.count)
+	}
+
+	builder.WriteString("# HELP cloudns_api_retries_total Amount of retried requests sent to the ClouDNS API.\n")
+	builder.WriteString("# TYPE cloudns_api_retries_total counter\n")
+	for _, endpoint := range sortedKeys(metrics.retries) {
+		fmt.Fprintf(&builder, "cloudns_api_retries_total{endpoint=\"%s\"} %d\n", escapeLabelValue(endpoint),
+			metrics.retries[endpoint])
+	}
+
+	n, err := io.WriteString(w, builder.String())
+	return int64(n), err
+}
+
+// ServeHTTP exposes all metrics for scraping by Prometheus
+func (metrics *RequestMetrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = metrics.WriteTo(w)
+}
+
+// requestStatus classifies the outcome of a request for metrics
+func requestStatus(err error) string {
+	switch {
+	case err == nil:
+		return RequestStatusSuccess
+	case errors.Is(err, ErrRateLimited):
+		return RequestStatusThrottled
+	case errors.Is(err, ErrAPIInvocation):
+		return RequestStatusAPIError
+	}
+
+	return RequestStatusHTTPError
+}
+
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
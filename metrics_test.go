@@ -0,0 +1,90 @@
+package cloudns
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetrics(t *testing.T) {
+	// given
+	attempts := 0
+	metrics := NewRequestMetrics(0.5, 0.1)
+	sequenceClient := newSequenceClient(t, []*http.Response{
+		newStatusResponse(http.StatusTooManyRequests, ``),
+		newStatusResponse(http.StatusBadGateway, ``),
+	}, &attempts, Retries(RetryPolicy{MaxAttempts: 3}), CustomClock(NewManualClock(time.Now())), Metrics(metrics))
+
+	// when
+	_, err := sequenceClient.Account.GetCurrentIP(context.Background())
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+
+	recorder := httptest.NewRecorder()
+	metrics.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, strings.Join([]string{
+		"# HELP cloudns_api_request_duration_seconds Latency of requests sent to the ClouDNS API.",
+		"# TYPE cloudns_api_request_duration_seconds histogram",
+		`cloudns_api_request_duration_seconds_bucket{endpoint="/ip/get-my-ip.json",status="http_error",le="0.1"} 1`,
+		`cloudns_api_request_duration_seconds_bucket{endpoint="/ip/get-my-ip.json",status="http_error",le="0.5"} 1`,
+		`cloudns_api_request_duration_seconds_bucket{endpoint="/ip/get-my-ip.json",status="http_error",le="+Inf"} 1`,
+		`cloudns_api_request_duration_seconds_sum{endpoint="/ip/get-my-ip.json",status="http_error"} 0`,
+		`cloudns_api_request_duration_seconds_count{endpoint="/ip/get-my-ip.json",status="http_error"} 1`,
+		`cloudns_api_request_duration_seconds_bucket{endpoint="/ip/get-my-ip.json",status="success",le="0.1"} 1`,
+		`cloudns_api_request_duration_seconds_bucket{endpoint="/ip/get-my-ip.json",status="success",le="0.5"} 1`,
+		`cloudns_api_request_duration_seconds_bucket{endpoint="/ip/get-my-ip.json",status="success",le="+Inf"} 1`,
+		`cloudns_api_request_duration_seconds_sum{endpoint="/ip/get-my-ip.json",status="success"} 0`,
+		`cloudns_api_request_duration_seconds_count{endpoint="/ip/get-my-ip.json",status="success"} 1`,
+		`cloudns_api_request_duration_seconds_bucket{endpoint="/ip/get-my-ip.json",status="throttled",le="0.1"} 1`,
+		`cloudns_api_request_duration_seconds_bucket{endpoint="/ip/get-my-ip.json",status="throttled",le="0.5"} 1`,
+		`cloudns_api_request_duration_seconds_bucket{endpoint="/ip/get-my-ip.json",status="throttled",le="+Inf"} 1`,
+		`cloudns_api_request_duration_seconds_sum{endpoint="/ip/get-my-ip.json",status="throttled"} 0`,
+		`cloudns_api_request_duration_seconds_count{endpoint="/ip/get-my-ip.json",status="throttled"} 1`,
+		"# HELP cloudns_api_retries_total Amount of retried requests sent to the ClouDNS API.",
+		"# TYPE cloudns_api_retries_total counter",
+		`cloudns_api_retries_total{endpoint="/ip/get-my-ip.json"} 2`,
+	}, "\n")+"\n", recorder.Body.String())
+}
+
+func TestMetrics_APIError(t *testing.T) {
+	// given
+	metrics := NewRequestMetrics()
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `{"status":"Failed","statusDescription":"Missing domain-name"}`
+	}, Metrics(metrics))
+
+	// when
+	_, err := stubClient.Zones.Get(context.Background(), testDomain)
+
+	// then
+	assert.Error(t, err)
+	assert.Equal(t, uint64(1), metrics.requests[requestMetricsKey{endpoint: zoneGetURL, status: RequestStatusAPIError}].count)
+}
+
+func TestRequestMetrics_Buckets(t *testing.T) {
+	// given
+	metrics := NewRequestMetrics(1, 0.1)
+
+	// when
+	metrics.ObserveRequest("/test.json", RequestStatusSuccess, 50*time.Millisecond)
+	metrics.ObserveRequest("/test.json", RequestStatusSuccess, 500*time.Millisecond)
+	metrics.ObserveRequest("/test.json", RequestStatusSuccess, 5*time.Second)
+
+	// then
+	histogram := metrics.requests[requestMetricsKey{endpoint: "/test.json", status: RequestStatusSuccess}]
+	assert.Equal(t, []uint64{1, 2}, histogram.counts, "buckets should be sorted and cumulative")
+	assert.Equal(t, uint64(3), histogram.count)
+	assert.InDelta(t, 5.55, histogram.sum, 0.001)
+}
+
+func TestMetrics_Nil(t *testing.T) {
+	_, err := New(Metrics(nil))
+	assert.ErrorIs(t, err, ErrInvalidOptions)
+}
@@ -1,8 +1,14 @@
 package cloudns
 
 import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
 	"net/http"
+	"reflect"
 	"strings"
+	"time"
 )
 
 // Option represents functional options which can be specified when instantiating a new API client
@@ -49,6 +55,261 @@ func UserAgent(userAgent string) Option {
 	}
 }
 
+// CustomClock overrides the clock used for all time-dependent behavior of the API client, e.g. when waiting between
+// requests. Using a ManualClock allows testing such behavior deterministically without sleeping.
+func CustomClock(clock Clock) Option {
+	return func(api *Client) error {
+		api.clock = clock
+		return nil
+	}
+}
+
+// RandomSource overrides the source of randomness used by the API client, e.g. for jitter when waiting between
+// requests. Using a fixed seed allows testing such behavior deterministically.
+func RandomSource(source rand.Source) Option {
+	return func(api *Client) error {
+		api.random = newLockedRand(source)
+		return nil
+	}
+}
+
+// ResponseCache enables caching of rarely changing metadata like available TTLs, record types and nameservers using
+// the given cache for the specified duration. Use NewFileCache to share cached metadata across multiple processes.
+func ResponseCache(cache Cache, ttl time.Duration) Option {
+	return func(api *Client) error {
+		api.cache = cache
+		api.cacheTTL = ttl
+		return nil
+	}
+}
+
+// RateLimit limits the rate of requests sent to the ClouDNS API using the given rate limiter. Requests wait until the
+// rate limiter grants a token, which allows multiple processes sharing one account to coordinate through a distributed
+// RateLimiter implementation.
+func RateLimit(limiter RateLimiter) Option {
+	return func(api *Client) error {
+		api.rateLimiter = limiter
+		return nil
+	}
+}
+
+// NameserverCacheTTL memoizes the nameservers returned by Zones.AvailableNameservers for the given duration, as they
+// rarely change but are required by various higher-level operations. Concurrent callers share a single API request.
+func NameserverCacheTTL(ttl time.Duration) Option {
+	return func(api *Client) error {
+		api.nameservers = &memoizedValue[[]Nameserver]{ttl: ttl}
+		return nil
+	}
+}
+
+// Idempotency skips re-sending creating requests, e.g. Records.Create, which are identical to a request that succeeded
+// within the given window, returning the previous response instead. This protects against duplicate objects caused by
+// retrying a request after an ambiguous timeout. If a creating request fails ambiguously, e.g. due to a timeout or a
+// server error, identical record creations within the window first check whether an equivalent record exists and only
+// send the request again otherwise. Any other successful mutating request against the same zone ends the window for all
+// of its tracked requests, so that e.g. deleted records can be recreated.
+func Idempotency(window time.Duration) Option {
+	return func(api *Client) error {
+		api.idempotency = newIdempotencyTracker(window)
+		return nil
+	}
+}
+
+// Retries enables retrying requests which failed due to transient errors, using exponential backoff with optional
+// jitter as specified by the given policy, see RetryPolicy for which failures are retried. A RetryBudget can be used to
+// limit the total amount of retries.
+func Retries(policy RetryPolicy) Option {
+	return func(api *Client) error {
+		if policy.MaxAttempts < 1 {
+			return ErrIllegalArgument.wrap(errors.New("retry policy requires at least one attempt"))
+		}
+		if policy.Jitter < 0 || policy.Jitter > 1 {
+			return ErrIllegalArgument.wrap(errors.New("retry jitter must be between 0 and 1"))
+		}
+
+		api.retryPolicy = &policy
+		return nil
+	}
+}
+
+// TranslateStatus translates the status descriptions of failed API calls using the given function before they are
+// classified, as ClouDNS might return localized descriptions depending on the language of the account, which breaks the
+// extraction of structured information into APIError. Returning an empty string keeps the original description.
+func TranslateStatus(translate func(description string) string) Option {
+	return func(api *Client) error {
+		api.statusTranslator = translate
+		return nil
+	}
+}
+
+// StatusTranslations translates known localized status descriptions of failed API calls into their English
+// counterparts, see TranslateStatus. Descriptions are matched exactly after trimming surrounding whitespace.
+func StatusTranslations(translations map[string]string) Option {
+	return TranslateStatus(func(description string) string {
+		return translations[strings.TrimSpace(description)]
+	})
+}
+
+// SchemaDriftDetection enables comparing all API responses with the types they are decoded into using the given
+// detector, which records and logs JSON fields unknown to cloudns-go as well as expected fields missing in responses.
+// This is intended as a diagnostics mode, as every response gets decoded twice.
+func SchemaDriftDetection(detector *SchemaDriftDetector) Option {
+	return func(api *Client) error {
+		api.schemaDrift = detector
+		return nil
+	}
+}
+
+// FreezeZones freezes the given zones right from the start, see Client.FreezeZone
+func FreezeZones(zoneNames ...string) Option {
+	return func(api *Client) error {
+		for _, zoneName := range zoneNames {
+			api.freezer.zones[normalizeHostname(zoneName)] = true
+		}
+		return nil
+	}
+}
+
+// ClassifyEndpoints registers the classification of API endpoints for this client, overriding the classification of
+// wrapped endpoints with the same path. This allows requests against endpoints invoked with Client.Call to be retried
+// or cached, which are otherwise treated as mutating.
+func ClassifyEndpoints(endpoints ...Endpoint) Option {
+	return func(api *Client) error {
+		if api.classifiedEndpoints == nil {
+			api.classifiedEndpoints = make(map[string]Endpoint)
+		}
+
+		for _, endpoint := range endpoints {
+			if endpoint.Path == "" {
+				return ErrIllegalArgument.wrap(errors.New("endpoint path must not be empty"))
+			}
+			if endpoint.Mutating && endpoint.Cacheable {
+				return ErrIllegalArgument.wrap(fmt.Errorf("mutating endpoint %s can not be cacheable", endpoint.Path))
+			}
+
+			api.classifiedEndpoints[endpoint.Path] = endpoint
+		}
+		return nil
+	}
+}
+
+// EnforceTTL enforces the given TTL policy for all records written with Records.Create and Records.Update, including
+// all higher-level methods based on them. Depending on the policy, out-of-range TTLs are either rejected with
+// ErrTTLPolicy or clamped. Records written by server-side imports or zone transfers are not affected.
+func EnforceTTL(policy TTLPolicy) Option {
+	return func(api *Client) error {
+		if err := policy.validate(); err != nil {
+			return ErrIllegalArgument.wrap(err)
+		}
+
+		api.ttlPolicy = &policy
+		return nil
+	}
+}
+
+// NormalizeRecords registers normalizers which are applied in order to all records read with Records.Search and
+// Records.ListPaged and to all records written with Records.Create and Records.Update, including all higher-level
+// methods based on them. Desired records passed to Records.Sync and Records.Upsert are normalized before comparing them,
+// which avoids perpetual spurious changes caused by cosmetic differences in how ClouDNS echoes values back. See
+// NormalizeHostCase, NormalizeWhitespace and NormalizeIPv6 for built-in normalizers.
+func NormalizeRecords(normalizers ...RecordNormalizer) Option {
+	return func(api *Client) error {
+		for _, normalizer := range normalizers {
+			if normalizer == nil {
+				return ErrIllegalArgument.wrap(errors.New("record normalizer must not be nil"))
+			}
+		}
+
+		api.recordNormalizers = append(api.recordNormalizers, normalizers...)
+		return nil
+	}
+}
+
+// ParamEncoder registers a function which encodes all parameter values of the concrete type T into strings, including
+// elements of slices and IndexedParam, e.g. for sending time.Time values as Unix timestamps. The encoder is applied to
+// all parameters of a request, regardless of whether they are sent as JSON body or query parameters. Values without a
+// registered encoder are sent using their JSON or textual representation.
+func ParamEncoder[T any](encode func(value T) string) Option {
+	return func(api *Client) error {
+		if encode == nil {
+			return ErrIllegalArgument.wrap(errors.New("parameter encoder must not be nil"))
+		}
+
+		if api.paramEncoders == nil {
+			api.paramEncoders = make(map[reflect.Type]func(value interface{}) string)
+		}
+		api.paramEncoders[reflect.TypeOf((*T)(nil)).Elem()] = func(value interface{}) string {
+			return encode(value.(T))
+		}
+		return nil
+	}
+}
+
+// Logger logs all outbound API requests and inbound responses at debug level using the given logger. Authentication
+// parameters are always redacted, see RequestError.
+func Logger(logger *slog.Logger) Option {
+	return func(api *Client) error {
+		api.logger = logger
+		return nil
+	}
+}
+
+// Metrics reports the latency and outcome of every request attempt as well as all retries to the given recorder, e.g.
+// for monitoring the API consumption and error rates of large automations. See RequestMetrics for a recorder which can
+// be scraped by Prometheus.
+func Metrics(recorder MetricsRecorder) Option {
+	return func(api *Client) error {
+		if recorder == nil {
+			return ErrIllegalArgument.wrap(errors.New("metrics recorder must not be nil"))
+		}
+
+		api.metrics = recorder
+		return nil
+	}
+}
+
+// DNSPort changes the port of all DNS queries which are sent directly to nameservers, e.g. by ZoneService.WaitForSerial
+// or RecordService.InspectAlias, which defaults to 53
+func DNSPort(port int) Option {
+	return func(api *Client) error {
+		if port <= 0 || port > 65535 {
+			return ErrIllegalArgument.wrap(fmt.Errorf("invalid dns port: %d", port))
+		}
+
+		api.dnsPort = port
+		return nil
+	}
+}
+
+// ReadOnly causes all mutating methods to fail with ErrReadOnlyClient without invoking the API, which guarantees that
+// e.g. reporting and monitoring deployments never modify any zone or place any order, even if misconfigured. Methods
+// combining multiple API calls might still perform their read-only calls before failing. It can not be combined with
+// DryRun.
+func ReadOnly() Option {
+	return func(api *Client) error {
+		api.readOnly = true
+		return nil
+	}
+}
+
+// DryRun causes all requests against mutating endpoints to succeed without being sent, like using WithDryRun for every
+// request of the client. It can not be combined with ReadOnly.
+func DryRun() Option {
+	return func(api *Client) error {
+		api.dryRun = true
+		return nil
+	}
+}
+
+// RequireAuth causes the instantiation of the API client to fail with ErrMissingCredentials if none of the
+// authentication options has been specified, as requests without credentials are rejected by the ClouDNS API anyway.
+func RequireAuth() Option {
+	return func(api *Client) error {
+		api.requireAuth = true
+		return nil
+	}
+}
+
 // AuthUserID setups user-id based authentication against the ClouDNS API
 func AuthUserID(id int, password string) Option {
 	return func(api *Client) error {
@@ -93,3 +354,24 @@ func AuthSubUserName(user string, password string) Option {
 		return nil
 	}
 }
+
+// AuthFromEnv setups authentication against the ClouDNS API using the credentials of the environment variables
+// CLOUDNS_AUTH_PASSWORD and exactly one of CLOUDNS_AUTH_ID, CLOUDNS_SUB_AUTH_ID or CLOUDNS_SUB_AUTH_USER. Missing or
+// conflicting variables fail with ErrMissingCredentials or ErrMultipleCredentials respectively.
+func AuthFromEnv() Option {
+	return func(api *Client) error {
+		auth, err := authFromEnv()
+		if err != nil {
+			return err
+		}
+
+		switch auth.Type {
+		case AuthTypeUserID:
+			return AuthUserID(auth.UserID, auth.Password)(api)
+		case AuthTypeSubUserID:
+			return AuthSubUserID(auth.SubUserID, auth.Password)(api)
+		default:
+			return AuthSubUserName(auth.SubUserName, auth.Password)(api)
+		}
+	}
+}
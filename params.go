@@ -0,0 +1,110 @@
+package cloudns
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// IndexedParam is a parameter with multiple values, which gets encoded with explicit indices as GET parameter, e.g.
+// `ns[0]=a&ns[1]=b`. Plain slices are encoded as repeated parameters instead, e.g. `ns[]=a&ns[]=b`. Both are sent as
+// JSON array when using POST.
+type IndexedParam []interface{}
+
+// Indexed returns an IndexedParam containing the given values
+func Indexed[T any](values ...T) IndexedParam {
+	param := make(IndexedParam, 0, len(values))
+	for _, value := range values {
+		param = append(param, value)
+	}
+
+	return param
+}
+
+// encodeQueryParams encodes the given parameters as GET parameters, expanding slices into repeated or indexed keys
+func encodeQueryParams(params map[string]interface{}) url.Values {
+	values := make(url.Values)
+	for key, value := range params {
+		if indexed, ok := value.(IndexedParam); ok {
+			key = strings.TrimSuffix(key, "[]")
+			for index, item := range indexed {
+				values.Set(key+"["+strconv.Itoa(index)+"]", formatParam(item))
+			}
+			continue
+		}
+
+		if items := reflect.ValueOf(value); items.Kind() == reflect.Slice && items.Type().Elem().Kind() != reflect.Uint8 {
+			if !strings.HasSuffix(key, "[]") {
+				key += "[]"
+			}
+			for index := 0; index < items.Len(); index++ {
+				values.Add(key, formatParam(items.Index(index).Interface()))
+			}
+			continue
+		}
+
+		values.Set(key, formatParam(value))
+	}
+
+	return values
+}
+
+// encodeParams applies the parameter encoders registered with the ParamEncoder option to the given parameters in-place
+func (c *Client) encodeParams(params map[string]interface{}) {
+	if len(c.paramEncoders) == 0 {
+		return
+	}
+
+	for key, value := range params {
+		params[key] = c.encodeParam(value)
+	}
+}
+
+// encodeParam applies the parameter encoder registered for the type of the given value, including all elements of
+// slices and IndexedParam, or returns the value as-is if there is none
+func (c *Client) encodeParam(value interface{}) interface{} {
+	if encode, ok := c.paramEncoders[reflect.TypeOf(value)]; ok {
+		return encode(value)
+	}
+
+	if indexed, ok := value.(IndexedParam); ok {
+		encoded := make(IndexedParam, 0, len(indexed))
+		for _, item := range indexed {
+			encoded = append(encoded, c.encodeParam(item))
+		}
+		return encoded
+	}
+
+	if items := reflect.ValueOf(value); items.Kind() == reflect.Slice {
+		if encode, ok := c.paramEncoders[items.Type().Elem()]; ok {
+			encoded := make([]string, 0, items.Len())
+			for index := 0; index < items.Len(); index++ {
+				encoded = append(encoded, encode(items.Index(index).Interface()))
+			}
+			return encoded
+		}
+	}
+
+	return value
+}
+
+// formatParam formats a single parameter value, using the textual representation of types like net.IP or time.Time,
+// which matches their representation within JSON bodies
+func formatParam(value interface{}) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case encoding.TextMarshaler:
+		if text, err := value.MarshalText(); err == nil {
+			return string(text)
+		}
+		return fmt.Sprint(value)
+	case fmt.Stringer:
+		return value.String()
+	default:
+		return fmt.Sprint(value)
+	}
+}
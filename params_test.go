@@ -0,0 +1,100 @@
+package cloudns
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func captureRequest(t *testing.T, method string, params HTTPParams, options ...Option) (*http.Request, string) {
+	var captured *http.Request
+	var body []byte
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		captured = req
+		if req.Body != nil {
+			body, _ = io.ReadAll(req.Body)
+		}
+		return `{"status":"Success"}`
+	}, options...)
+
+	var result StatusResult
+	assert.NoError(t, stubClient.request(context.Background(), method, "/test.json", params, nil, &result))
+	return captured, string(body)
+}
+
+func TestRequest_GET_RepeatedParams(t *testing.T) {
+	req, _ := captureRequest(t, "GET", HTTPParams{
+		"domain-name": testDomain,
+		"ns":          []string{"ns1.example.com", "ns2.example.com"},
+		"tld[]":       []string{"com"},
+		"period":      2,
+	})
+
+	assert.Equal(t, "domain-name=api-example.com&ns%5B%5D=ns1.example.com&ns%5B%5D=ns2.example.com&period=2&tld%5B%5D=com",
+		req.URL.RawQuery)
+}
+
+func TestRequest_GET_IndexedParams(t *testing.T) {
+	req, _ := captureRequest(t, "GET", HTTPParams{
+		"ip": Indexed(net.ParseIP("192.0.2.1"), net.ParseIP("2001:db8::1")),
+	})
+
+	assert.Equal(t, "ip%5B0%5D=192.0.2.1&ip%5B1%5D=2001%3Adb8%3A%3A1", req.URL.RawQuery)
+}
+
+func TestRequest_POST_MultipleValues(t *testing.T) {
+	req, body := captureRequest(t, "POST", HTTPParams{
+		"ns": []string{"ns1.example.com", "ns2.example.com"},
+		"ip": Indexed(net.ParseIP("192.0.2.1")),
+	})
+
+	assert.Empty(t, req.URL.RawQuery)
+	assert.JSONEq(t, `{"ns":["ns1.example.com","ns2.example.com"],"ip":["192.0.2.1"]}`, body)
+}
+
+func TestIndexed(t *testing.T) {
+	assert.Equal(t, IndexedParam{1, 2}, Indexed(1, 2))
+	assert.Empty(t, Indexed[string]())
+}
+
+type testParamEnum int
+
+func TestParamEncoder(t *testing.T) {
+	// given
+	options := []Option{
+		ParamEncoder(func(value time.Time) string { return strconv.FormatInt(value.Unix(), 10) }),
+		ParamEncoder(func(value testParamEnum) string { return []string{"off", "on"}[value] }),
+	}
+	params := HTTPParams{
+		"since":  time.Unix(1671840000, 0),
+		"mode":   testParamEnum(1),
+		"modes":  []testParamEnum{0, 1},
+		"modes2": Indexed(testParamEnum(1)),
+		"count":  3,
+	}
+
+	// when
+	getReq, _ := captureRequest(t, "GET", params, options...)
+	_, postBody := captureRequest(t, "POST", params, options...)
+
+	// then
+	assert.Equal(t, "count=3&mode=on&modes2%5B0%5D=on&modes%5B%5D=off&modes%5B%5D=on&since=1671840000",
+		getReq.URL.RawQuery)
+	assert.JSONEq(t, `{"since":"1671840000","mode":"on","modes":["off","on"],"modes2":["on"],"count":3}`, postBody)
+}
+
+func TestParamEncoder_Nil(t *testing.T) {
+	_, err := New(ParamEncoder[time.Time](nil))
+	assert.ErrorIs(t, err, ErrInvalidOptions)
+}
+
+func TestFormatParam_TextMarshaler(t *testing.T) {
+	assert.Equal(t, "2001:db8::1", formatParam(netip.MustParseAddr("2001:db8::1")))
+	assert.Equal(t, "2022-12-24T00:00:00Z", formatParam(time.Date(2022, 12, 24, 0, 0, 0, 0, time.UTC)))
+}
@@ -0,0 +1,114 @@
+package cloudns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimiter limits the rate of requests sent to the ClouDNS API. Implementations may share their state across
+// multiple processes, e.g. by storing token buckets in Redis, so that all replicas using the same account collectively
+// stay within the limits of the ClouDNS API.
+type RateLimiter interface {
+	// Take attempts to take a single token from the bucket identified by the given key, which is derived from the
+	// credentials of the client. A zero duration indicates that the request may be sent, otherwise the client waits
+	// for the returned duration before trying again.
+	Take(ctx context.Context, key string) (time.Duration, error)
+}
+
+// TokenBucket is an in-process RateLimiter based on the token bucket algorithm, keeping a separate bucket per key. It
+// can be used directly for single-process setups or serves as a reference for distributed implementations.
+type TokenBucket struct {
+	mutex   sync.Mutex
+	rate    float64
+	burst   float64
+	clock   Clock
+	buckets map[string]*tokenBucketState
+}
+
+type tokenBucketState struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+// NewTokenBucket instantiates a new TokenBucket which refills the given amount of tokens per second up to the given
+// burst size. A nil clock defaults to the system clock.
+func NewTokenBucket(rate float64, burst int, clock Clock) (*TokenBucket, error) {
+	if rate <= 0 || burst <= 0 {
+		return nil, ErrIllegalArgument.wrap(errors.New("rate and burst of token bucket must be positive"))
+	}
+	if clock == nil {
+		clock = systemClock{}
+	}
+
+	return &TokenBucket{
+		rate:    rate,
+		burst:   float64(burst),
+		clock:   clock,
+		buckets: make(map[string]*tokenBucketState),
+	}, nil
+}
+
+// Take takes a single token from the bucket of the given key or returns the duration until the next token is available
+func (bucket *TokenBucket) Take(_ context.Context, key string) (time.Duration, error) {
+	bucket.mutex.Lock()
+	defer bucket.mutex.Unlock()
+
+	now := bucket.clock.Now()
+	state, ok := bucket.buckets[key]
+	if !ok {
+		state = &tokenBucketState{tokens: bucket.burst, updatedAt: now}
+		bucket.buckets[key] = state
+	}
+
+	elapsed := now.Sub(state.updatedAt).Seconds()
+	state.tokens = math.Min(bucket.burst, state.tokens+math.Max(0, elapsed)*bucket.rate)
+	state.updatedAt = now
+
+	if state.tokens >= 1 {
+		state.tokens--
+		return 0, nil
+	}
+
+	missing := (1 - state.tokens) / bucket.rate
+	return time.Duration(math.Ceil(missing * float64(time.Second))), nil
+}
+
+// waitForRateLimit blocks until the rate limiter of the client allows sending another request
+func (c *Client) waitForRateLimit(ctx context.Context) error {
+	if c.rateLimiter == nil {
+		return nil
+	}
+
+	key := c.auth.rateLimitKey()
+	for {
+		wait, err := c.rateLimiter.Take(ctx, key)
+		if err != nil {
+			return ErrRateLimiterFailed.wrap(err)
+		}
+		if wait <= 0 {
+			return nil
+		}
+
+		if err := c.clock.Sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// rateLimitKey returns the key identifying the rate limit bucket of the credentials
+func (auth *Auth) rateLimitKey() string {
+	switch auth.Type {
+	case AuthTypeUserID:
+		return fmt.Sprintf("auth-id:%d", auth.UserID)
+	case AuthTypeSubUserID:
+		return fmt.Sprintf("sub-auth-id:%d", auth.SubUserID)
+	case AuthTypeSubUserName:
+		return fmt.Sprintf("sub-auth-user:%s", auth.SubUserName)
+	}
+
+	return "anonymous"
+}
@@ -0,0 +1,84 @@
+package cloudns
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type failingRateLimiter struct{}
+
+func (failingRateLimiter) Take(context.Context, string) (time.Duration, error) {
+	return 0, errors.New("backend unavailable")
+}
+
+func TestTokenBucket_Take(t *testing.T) {
+	// given
+	clock := NewManualClock(time.Date(2022, 12, 24, 0, 0, 0, 0, time.UTC))
+	bucket, err := NewTokenBucket(2, 2, clock)
+	assert.NoError(t, err)
+
+	// when
+	first, _ := bucket.Take(context.Background(), "a")
+	second, _ := bucket.Take(context.Background(), "a")
+	third, _ := bucket.Take(context.Background(), "a")
+	other, _ := bucket.Take(context.Background(), "b")
+	clock.Advance(500 * time.Millisecond)
+	refilled, _ := bucket.Take(context.Background(), "a")
+
+	// then
+	assert.Zero(t, first)
+	assert.Zero(t, second)
+	assert.Equal(t, 500*time.Millisecond, third, "should wait until next token is available")
+	assert.Zero(t, other, "buckets should be separated by key")
+	assert.Zero(t, refilled, "bucket should refill over time")
+}
+
+func TestNewTokenBucket_Invalid(t *testing.T) {
+	_, err := NewTokenBucket(0, 1, nil)
+	assert.ErrorIs(t, err, ErrIllegalArgument)
+}
+
+func TestRateLimit(t *testing.T) {
+	// given
+	start := time.Date(2022, 12, 24, 0, 0, 0, 0, time.UTC)
+	clock := NewManualClock(start)
+	bucket, _ := NewTokenBucket(1, 1, clock)
+	var requests int
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		requests++
+		return `{"status":"Success"}`
+	}, AuthUserID(123, "secret"), CustomClock(clock), RateLimit(bucket))
+
+	// when
+	_, err1 := stubClient.Account.Login(context.Background())
+	_, err2 := stubClient.Account.Login(context.Background())
+	_, err3 := stubClient.Account.Login(context.Background())
+
+	// then
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+	assert.NoError(t, err3)
+	assert.Equal(t, 3, requests)
+	assert.Equal(t, start.Add(2*time.Second), clock.Now(), "client should have waited for rate limiter")
+}
+
+func TestRateLimit_Failure(t *testing.T) {
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		t.Fatal("request should not have been sent")
+		return ""
+	}, RateLimit(failingRateLimiter{}))
+
+	_, err := stubClient.Account.Login(context.Background())
+	assert.ErrorIs(t, err, ErrRateLimiterFailed)
+}
+
+func TestAuth_RateLimitKey(t *testing.T) {
+	assert.Equal(t, "anonymous", NewAuth().rateLimitKey())
+	assert.Equal(t, "auth-id:1", (&Auth{Type: AuthTypeUserID, UserID: 1}).rateLimitKey())
+	assert.Equal(t, "sub-auth-id:2", (&Auth{Type: AuthTypeSubUserID, SubUserID: 2}).rateLimitKey())
+	assert.Equal(t, "sub-auth-user:x", (&Auth{Type: AuthTypeSubUserName, SubUserName: "x"}).rateLimitKey())
+}
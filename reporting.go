@@ -0,0 +1,80 @@
+package cloudns
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+)
+
+// ZoneUsageSample represents the zone usage of an account observed at a given point in time
+type ZoneUsageSample struct {
+	Time  time.Time
+	Usage ZoneUsage
+}
+
+// ZoneUsageForecast represents the estimated development of the zone usage based on previously observed samples
+type ZoneUsageForecast struct {
+	// Current and Limit are taken from the most recent sample
+	Current int
+	Limit   int
+	// GrowthPerDay is the average amount of zones added per day, determined by linear regression over all samples
+	GrowthPerDay float64
+	// LimitReachedAt is the estimated point in time when the zone limit will be reached. It is zero if the usage is not
+	// growing or the plan has no limit, and equals the time of the most recent sample if the limit has already been
+	// reached. Estimates beyond the maximum representable duration are clamped to it.
+	LimitReachedAt time.Time
+}
+
+// ForecastZoneUsage computes the trend of the zone usage based on periodically sampled values of ZoneService.GetUsage
+// and estimates when the limit of the current plan will be reached. At least two samples taken at different points in
+// time are required. Samples do not have to be sorted.
+func ForecastZoneUsage(samples []ZoneUsageSample) (result ZoneUsageForecast, err error) {
+	if len(samples) < 2 {
+		return result, ErrIllegalArgument.wrap(errors.New("at least two samples are required for forecasting"))
+	}
+
+	sorted := make([]ZoneUsageSample, len(samples))
+	copy(sorted, samples)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Time.Before(sorted[j].Time)
+	})
+
+	first, latest := sorted[0], sorted[len(sorted)-1]
+	if !latest.Time.After(first.Time) {
+		return result, ErrIllegalArgument.wrap(errors.New("samples must span a period of time"))
+	}
+
+	// Determine the slope with a least squares fit, using days since the first sample as x and the usage as y
+	var sumX, sumY, sumXY, sumXX float64
+	for _, sample := range sorted {
+		x := sample.Time.Sub(first.Time).Hours() / 24
+		y := float64(sample.Usage.Current)
+
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+
+	n := float64(len(sorted))
+	result.Current = latest.Usage.Current
+	result.Limit = latest.Usage.Limit
+	result.GrowthPerDay = (n*sumXY - sumX*sumY) / (n*sumXX - sumX*sumX)
+
+	switch {
+	case result.Limit <= 0:
+		// A limit of zero means that the plan is unlimited
+	case result.Current >= result.Limit:
+		result.LimitReachedAt = latest.Time
+	case result.GrowthPerDay > 0:
+		days := float64(result.Limit-result.Current) / result.GrowthPerDay
+		duration := time.Duration(math.MaxInt64)
+		if nanoseconds := math.Round(days * 24 * float64(time.Hour)); nanoseconds < float64(math.MaxInt64) {
+			duration = time.Duration(nanoseconds)
+		}
+		result.LimitReachedAt = latest.Time.Add(duration)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,83 @@
+package cloudns
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestForecastZoneUsage(t *testing.T) {
+	// given
+	start := time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)
+	samples := []ZoneUsageSample{
+		{Time: start.Add(48 * time.Hour), Usage: ZoneUsage{Current: 14, Limit: 50}},
+		{Time: start, Usage: ZoneUsage{Current: 10, Limit: 50}},
+		{Time: start.Add(24 * time.Hour), Usage: ZoneUsage{Current: 12, Limit: 50}},
+	}
+
+	// when
+	forecast, err := ForecastZoneUsage(samples)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 14, forecast.Current)
+	assert.Equal(t, 50, forecast.Limit)
+	assert.InDelta(t, 2.0, forecast.GrowthPerDay, 0.0001)
+	assert.Equal(t, start.Add(20*24*time.Hour), forecast.LimitReachedAt, "limit should be reached after 18 more days")
+}
+
+func TestForecastZoneUsage_NoGrowth(t *testing.T) {
+	start := time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)
+	forecast, err := ForecastZoneUsage([]ZoneUsageSample{
+		{Time: start, Usage: ZoneUsage{Current: 20, Limit: 50}},
+		{Time: start.Add(24 * time.Hour), Usage: ZoneUsage{Current: 18, Limit: 50}},
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, forecast.LimitReachedAt.IsZero(), "shrinking usage should never reach limit")
+}
+
+func TestForecastZoneUsage_LimitReached(t *testing.T) {
+	start := time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)
+	forecast, err := ForecastZoneUsage([]ZoneUsageSample{
+		{Time: start, Usage: ZoneUsage{Current: 48, Limit: 50}},
+		{Time: start.Add(24 * time.Hour), Usage: ZoneUsage{Current: 50, Limit: 50}},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, start.Add(24*time.Hour), forecast.LimitReachedAt)
+}
+
+func TestForecastZoneUsage_Unlimited(t *testing.T) {
+	start := time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)
+	forecast, err := ForecastZoneUsage([]ZoneUsageSample{
+		{Time: start, Usage: ZoneUsage{Current: 10, Limit: 0}},
+		{Time: start.Add(24 * time.Hour), Usage: ZoneUsage{Current: 12, Limit: 0}},
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, forecast.LimitReachedAt.IsZero(), "unlimited plan should never reach limit")
+}
+
+func TestForecastZoneUsage_LongHorizon(t *testing.T) {
+	start := time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)
+	forecast, err := ForecastZoneUsage([]ZoneUsageSample{
+		{Time: start, Usage: ZoneUsage{Current: 10, Limit: 1000000000}},
+		{Time: start.Add(24 * time.Hour), Usage: ZoneUsage{Current: 11, Limit: 1000000000}},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, start.Add(24*time.Hour).Add(math.MaxInt64), forecast.LimitReachedAt, "estimate should be clamped")
+}
+
+func TestForecastZoneUsage_Invalid(t *testing.T) {
+	start := time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := ForecastZoneUsage([]ZoneUsageSample{{Time: start}})
+	assert.True(t, errors.Is(err, ErrIllegalArgument), "single sample should be rejected")
+
+	_, err = ForecastZoneUsage([]ZoneUsageSample{{Time: start}, {Time: start}})
+	assert.True(t, errors.Is(err, ErrIllegalArgument), "samples without time span should be rejected")
+}
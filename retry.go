@@ -0,0 +1,200 @@
+package cloudns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RetryPolicy specifies how requests failing due to transient errors are retried. Network errors and server errors
+// (HTTP 5xx) are only retried for endpoints which are known to be read-only, as a failed mutating request might have
+// been processed by ClouDNS anyway. Requests rejected by ClouDNS due to too many requests are retried for all endpoints,
+// as they have not been processed.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum amount of attempts per request, including the initial one
+	MaxAttempts int
+	// InitialBackoff is the time waited before the first retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the time waited between two attempts
+	MaxBackoff time.Duration
+	// Multiplier is the factor by which the backoff grows after every retry, defaulting to 2
+	Multiplier float64
+	// Jitter randomly shortens every backoff by up to the given fraction between 0 and 1, e.g. 0.2 waits between 80%
+	// and 100% of the backoff, so that clients failing at the same time do not retry in lockstep
+	Jitter float64
+	// Budget limits the amount of retries across all requests of the client, so that a burst of failures does not
+	// multiply the load on a struggling API. Retries are unlimited if no budget has been specified.
+	Budget *RetryBudget
+}
+
+// RetryBudget limits retries to a ratio of the requests sent by a client. Every request deposits the ratio into the
+// budget while every retry withdraws a whole token, so that e.g. a ratio of 0.1 allows retrying one out of ten requests
+// on average. The amount of tokens is capped to allow short bursts of retries after a longer period of success.
+type RetryBudget struct {
+	mutex     sync.Mutex
+	ratio     float64
+	maxTokens float64
+	tokens    float64
+}
+
+// NewRetryBudget instantiates a new retry budget with the given ratio and maximum amount of tokens, which starts full
+func NewRetryBudget(ratio float64, maxTokens int) (*RetryBudget, error) {
+	if ratio < 0 || maxTokens <= 0 {
+		return nil, ErrIllegalArgument.wrap(errors.New("ratio must not be negative and max tokens must be positive"))
+	}
+
+	return &RetryBudget{ratio: ratio, maxTokens: float64(maxTokens), tokens: float64(maxTokens)}, nil
+}
+
+// Remaining returns the amount of retries which are currently allowed by the budget
+func (budget *RetryBudget) Remaining() int {
+	budget.mutex.Lock()
+	defer budget.mutex.Unlock()
+
+	return int(budget.tokens)
+}
+
+func (budget *RetryBudget) deposit() {
+	budget.mutex.Lock()
+	defer budget.mutex.Unlock()
+
+	budget.tokens = math.Min(budget.maxTokens, budget.tokens+budget.ratio)
+}
+
+func (budget *RetryBudget) withdraw() bool {
+	budget.mutex.Lock()
+	defer budget.mutex.Unlock()
+
+	if budget.tokens < 1 {
+		return false
+	}
+
+	budget.tokens--
+	return true
+}
+
+// httpStatusError is returned for responses with a server error status code, which are considered transient
+type httpStatusError struct {
+	statusCode int
+}
+
+func (err httpStatusError) Error() string {
+	return fmt.Sprintf("unexpected http status: %d %s", err.statusCode, http.StatusText(err.statusCode))
+}
+
+// backoff returns the time to wait before the given retry, starting at 1 for the first retry
+func (policy *RetryPolicy) backoff(retry int) time.Duration {
+	multiplier := policy.Multiplier
+	if multiplier <= 0 {
+		multiplier = 2
+	}
+
+	backoff := float64(policy.InitialBackoff) * math.Pow(multiplier, float64(retry-1))
+	if policy.MaxBackoff > 0 && backoff > float64(policy.MaxBackoff) {
+		return policy.MaxBackoff
+	}
+
+	return time.Duration(backoff)
+}
+
+// sendWithRetries sends a request to the given endpoint, retrying it according to the retry policy of the client. No
+// retry is attempted if the backoff would exceed the deadline of the context or if the retry budget is exhausted, in
+// which case the error of the last attempt is returned.
+func (c *Client) sendWithRetries(ctx context.Context, method, endpoint string, params HTTPParams, headers http.Header) ([]byte, error) {
+	policy := c.retryPolicy
+	if policy != nil && policy.Budget != nil {
+		policy.Budget.deposit()
+	}
+
+	for attempt := 1; ; attempt++ {
+		respBody, err := c.send(ctx, method, endpoint, params, headers)
+		if err == nil || !c.shouldRetry(ctx, endpoint, attempt, err) {
+			return respBody, err
+		}
+
+		backoff := policy.backoff(attempt)
+		if policy.Jitter > 0 {
+			backoff -= time.Duration(policy.Jitter * c.random.Float64() * float64(backoff))
+		}
+		// Context deadlines are based on wall-clock time, so the remaining time is not derived from the client clock
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= backoff {
+			return nil, err
+		}
+		if policy.Budget != nil && !policy.Budget.withdraw() {
+			return nil, err
+		}
+
+		if c.metrics != nil {
+			c.metrics.ObserveRetry(endpoint)
+		}
+		if sleepErr := c.clock.Sleep(ctx, backoff); sleepErr != nil {
+			return nil, err
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, method, endpoint string, params HTTPParams, headers http.Header) ([]byte, error) {
+	req, err := c.makeRequest(ctx, method, endpoint, params, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.dispatch(ctx, method, endpoint, c.mergeParams(ctx, params), func() ([]byte, error) {
+		return c.doRequest(req)
+	})
+}
+
+// dispatch waits for the rate limiter before performing a single attempt of a request with the given function, which
+// gets logged and reported to the metrics recorder. It is shared by all requests sent to ClouDNS, including those which
+// do not target the API itself like dynamic URLs.
+func (c *Client) dispatch(ctx context.Context, method, endpoint string, params map[string]interface{}, do func() ([]byte, error)) ([]byte, error) {
+	if err := c.waitForRateLimit(ctx); err != nil {
+		return nil, err
+	}
+
+	c.logRequest(ctx, method, endpoint, params)
+	start := c.clock.Now()
+	respBody, err := do()
+	duration := c.clock.Now().Sub(start)
+	c.logResponse(ctx, endpoint, duration, respBody, err)
+	if c.metrics != nil {
+		c.metrics.ObserveRequest(endpoint, requestStatus(err), duration)
+	}
+
+	return respBody, err
+}
+
+// shouldRetry determines if a failed attempt should be retried according to the classification of isRetryableError.
+// Requests against unknown endpoints as well as cancelled contexts are never retried.
+func (c *Client) shouldRetry(ctx context.Context, path string, attempt int, err error) bool {
+	if c.retryPolicy == nil || attempt >= c.retryPolicy.MaxAttempts || ctx.Err() != nil {
+		return false
+	}
+
+	endpoint, ok := c.endpoint(path)
+	return ok && isRetryableError(err, endpoint.Mutating)
+}
+
+// isRetryableError classifies an error of a failed attempt. Throttled API errors are always retryable, while network
+// and server errors are only retryable for read-only endpoints. All other API errors, failures of the client-side rate
+// limiter as well as malformed requests or responses are fatal.
+func isRetryableError(err error, isMutating bool) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Throttled
+	}
+	if isMutating {
+		return false
+	}
+
+	var statusErr httpStatusError
+	if errors.As(err, &statusErr) {
+		return true
+	}
+
+	return !errors.Is(err, ErrAPIInvocation) && !errors.Is(err, ErrHTTPRequest) && !errors.Is(err, ErrRateLimiterFailed)
+}
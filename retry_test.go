@@ -0,0 +1,229 @@
+package cloudns
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func newSequenceClient(t *testing.T, responses []*http.Response, attempts *int, options ...Option) *Client {
+	transport := stubTransport(func(req *http.Request) (*http.Response, error) {
+		*attempts++
+		if *attempts <= len(responses) {
+			return responses[*attempts-1], nil
+		}
+		return newStubResponse(`{"status":"Success","ip":"192.0.2.1"}`), nil
+	})
+
+	sequenceClient, err := New(append([]Option{HTTPClient(&http.Client{Transport: transport})}, options...)...)
+	if err != nil {
+		t.Fatalf("could not create sequence client: %v", err)
+	}
+
+	return sequenceClient
+}
+
+func newStatusResponse(statusCode int, body string) *http.Response {
+	resp := newStubResponse(body)
+	resp.StatusCode = statusCode
+	return resp
+}
+
+func newFlakyClient(t *testing.T, failures int, attempts *int, options ...Option) *Client {
+	transport := stubTransport(func(req *http.Request) (*http.Response, error) {
+		*attempts++
+		if *attempts <= failures {
+			return nil, errors.New("connection reset by peer")
+		}
+		return newStubResponse(`{"ip":"192.0.2.1"}`), nil
+	})
+
+	flakyClient, err := New(append([]Option{HTTPClient(&http.Client{Transport: transport})}, options...)...)
+	if err != nil {
+		t.Fatalf("could not create flaky client: %v", err)
+	}
+
+	return flakyClient
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}
+
+	assert.Equal(t, time.Second, policy.backoff(1))
+	assert.Equal(t, 2*time.Second, policy.backoff(2))
+	assert.Equal(t, 4*time.Second, policy.backoff(3))
+	assert.Equal(t, 5*time.Second, policy.backoff(4), "backoff should be capped")
+}
+
+func TestRetries(t *testing.T) {
+	// given
+	var attempts int
+	clock := NewManualClock(time.Date(2022, 12, 24, 0, 0, 0, 0, time.UTC))
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Second}
+	flakyClient := newFlakyClient(t, 2, &attempts, CustomClock(clock), Retries(policy))
+
+	// when
+	ip, err := flakyClient.Account.GetCurrentIP(context.Background())
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "192.0.2.1", ip.String())
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, 3*time.Second, clock.Now().Sub(time.Date(2022, 12, 24, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestRetries_MutatingEndpoint(t *testing.T) {
+	var attempts int
+	flakyClient := newFlakyClient(t, 1, &attempts, Retries(RetryPolicy{MaxAttempts: 3}))
+
+	_, err := flakyClient.Records.Delete(context.Background(), testDomain, 1)
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts, "mutating requests should never be retried")
+}
+
+func TestRetries_Deadline(t *testing.T) {
+	// given
+	var attempts int
+	clock := NewManualClock(time.Date(2022, 12, 24, 0, 0, 0, 0, time.UTC))
+	policy := RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Minute}
+	flakyClient := newFlakyClient(t, 5, &attempts, CustomClock(clock), Retries(policy))
+	deadlineCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// when
+	_, err := flakyClient.Account.GetCurrentIP(deadlineCtx)
+
+	// then
+	assert.Contains(t, err.Error(), "connection reset by peer", "error of last attempt should be returned")
+	assert.Equal(t, 1, attempts, "backoff exceeding the deadline should not be scheduled, regardless of the client clock")
+}
+
+func TestRetries_Budget(t *testing.T) {
+	// given
+	var attempts int
+	budget, err := NewRetryBudget(0, 1)
+	assert.NoError(t, err)
+	clock := NewManualClock(time.Date(2022, 12, 24, 0, 0, 0, 0, time.UTC))
+	flakyClient := newFlakyClient(t, 10, &attempts, CustomClock(clock), Retries(RetryPolicy{MaxAttempts: 3, Budget: budget}))
+
+	// when
+	_, _ = flakyClient.Account.GetCurrentIP(context.Background())
+	_, _ = flakyClient.Account.GetCurrentIP(context.Background())
+
+	// then
+	assert.Equal(t, 3, attempts, "only a single retry should be allowed by the budget")
+	assert.Zero(t, budget.Remaining())
+}
+
+func TestRetries_Invalid(t *testing.T) {
+	_, err := New(Retries(RetryPolicy{}))
+	assert.ErrorIs(t, err, ErrInvalidOptions)
+
+	_, err = New(Retries(RetryPolicy{MaxAttempts: 3, Jitter: 1.5}))
+	assert.ErrorIs(t, err, ErrInvalidOptions)
+}
+
+func TestRetries_Jitter(t *testing.T) {
+	// given
+	var attempts int
+	start := time.Date(2022, 12, 24, 0, 0, 0, 0, time.UTC)
+	clock := NewManualClock(start)
+	policy := RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Second, Jitter: 0.5}
+	flakyClient := newFlakyClient(t, 1, &attempts, CustomClock(clock), RandomSource(rand.NewSource(42)), Retries(policy))
+
+	// when
+	_, err := flakyClient.Account.GetCurrentIP(context.Background())
+
+	// then
+	assert.NoError(t, err)
+	waited := clock.Now().Sub(start)
+	assert.True(t, waited >= 500*time.Millisecond && waited < time.Second, "backoff should be shortened by jitter: %v", waited)
+}
+
+func TestRetries_ServerError(t *testing.T) {
+	// given
+	var attempts int
+	responses := []*http.Response{
+		newStatusResponse(http.StatusBadGateway, "<html>Bad Gateway</html>"),
+		newStatusResponse(http.StatusServiceUnavailable, ""),
+	}
+	clock := NewManualClock(time.Now())
+	sequenceClient := newSequenceClient(t, responses, &attempts, CustomClock(clock), Retries(RetryPolicy{MaxAttempts: 3}))
+
+	// when
+	ip, err := sequenceClient.Account.GetCurrentIP(context.Background())
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "192.0.2.1", ip.String())
+	assert.Equal(t, 3, attempts)
+}
+
+func TestRetries_ServerError_MutatingEndpoint(t *testing.T) {
+	// given
+	var attempts int
+	responses := []*http.Response{newStatusResponse(http.StatusInternalServerError, "")}
+	sequenceClient := newSequenceClient(t, responses, &attempts, Retries(RetryPolicy{MaxAttempts: 3}))
+
+	// when
+	_, err := sequenceClient.Records.Delete(context.Background(), testDomain, 1)
+
+	// then
+	assert.ErrorIs(t, err, ErrHTTPRequest)
+	assert.Contains(t, err.Error(), "500 Internal Server Error")
+	assert.Equal(t, 1, attempts, "server errors of mutating requests should not be retried")
+}
+
+func TestRetries_Throttled(t *testing.T) {
+	// given
+	var attempts int
+	responses := []*http.Response{
+		newStubResponse(`{"status":"Failed","statusDescription":"Too many requests. Please try again later."}`),
+		newStatusResponse(http.StatusTooManyRequests, ""),
+	}
+	clock := NewManualClock(time.Now())
+	sequenceClient := newSequenceClient(t, responses, &attempts, CustomClock(clock), Retries(RetryPolicy{MaxAttempts: 3}))
+
+	// when
+	_, err := sequenceClient.Records.Delete(context.Background(), testDomain, 1)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts, "throttled requests should be retried even if mutating")
+}
+
+func TestRetries_FatalAPIError(t *testing.T) {
+	// given
+	var attempts int
+	responses := []*http.Response{newStubResponse(`{"status":"Failed","statusDescription":"Invalid domain name."}`)}
+	sequenceClient := newSequenceClient(t, responses, &attempts, Retries(RetryPolicy{MaxAttempts: 3}))
+
+	// when
+	_, err := sequenceClient.Account.GetCurrentIP(context.Background())
+
+	// then
+	assert.ErrorIs(t, err, ErrAPIInvocation)
+	assert.Equal(t, 1, attempts, "api errors should not be retried")
+}
+
+func TestIsRetryableError(t *testing.T) {
+	networkErr := errors.New("connection reset by peer")
+	serverErr := ErrHTTPRequest.wrap(httpStatusError{statusCode: http.StatusServiceUnavailable})
+	throttledErr := ErrAPIInvocation.wrap(newAPIError("Failed", "Too many requests"))
+	apiErr := ErrAPIInvocation.wrap(newAPIError("Failed", "Invalid record-id param."))
+	decodeErr := ErrHTTPRequest.wrap(errors.New("unexpected end of JSON input"))
+
+	assert.True(t, isRetryableError(networkErr, false))
+	assert.False(t, isRetryableError(networkErr, true))
+	assert.True(t, isRetryableError(serverErr, false))
+	assert.False(t, isRetryableError(serverErr, true))
+	assert.True(t, isRetryableError(throttledErr, false))
+	assert.True(t, isRetryableError(throttledErr, true))
+	assert.False(t, isRetryableError(apiErr, false))
+	assert.False(t, isRetryableError(decodeErr, false))
+	assert.False(t, isRetryableError(ErrRateLimiterFailed.wrap(context.Canceled), false))
+}
@@ -0,0 +1,166 @@
+package cloudns
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// ZoneSnapshot represents the records of a zone at a given point in time
+type ZoneSnapshot struct {
+	Zone    string    `json:"zone"`
+	TakenAt time.Time `json:"takenAt"`
+	Records []Record  `json:"records"`
+}
+
+// Snapshotter periodically stores snapshots of zones within a Store, which allows answering how records looked like
+// in the past even without access to the audit log of ClouDNS. Snapshots exceeding the retention limit are no longer
+// referenced, but their data remains within the store, as Store does not support deletion.
+type Snapshotter struct {
+	records   *RecordService
+	store     Store
+	clock     Clock
+	retention int
+}
+
+// NewSnapshotter instantiates a new Snapshotter which stores snapshots taken with the given client in the given store,
+// keeping at most the given amount of snapshots per zone. A retention of zero keeps all snapshots.
+func NewSnapshotter(client *Client, store Store, retention int) *Snapshotter {
+	return &Snapshotter{records: client.Records, store: store, clock: client.clock, retention: retention}
+}
+
+// Run takes snapshots of all given zones immediately and afterwards once per interval until the context is done.
+// Failing to snapshot a single zone does not stop snapshotting the other zones, the first error is returned at the end.
+func (snapshotter *Snapshotter) Run(ctx context.Context, interval time.Duration, zoneNames ...string) error {
+	var firstErr error
+	for {
+		for _, zoneName := range zoneNames {
+			if _, err := snapshotter.Snapshot(ctx, zoneName); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+
+		if err := snapshotter.clock.Sleep(ctx, interval); err != nil {
+			if firstErr != nil {
+				return firstErr
+			}
+			return err
+		}
+	}
+}
+
+// Snapshot takes and stores a snapshot of the zone
+func (snapshotter *Snapshotter) Snapshot(ctx context.Context, zoneName string) (snapshot ZoneSnapshot, err error) {
+	records, err := snapshotter.records.List(ctx, zoneName)
+	if err != nil {
+		return
+	}
+
+	snapshot = ZoneSnapshot{
+		Zone:    normalizeHostname(zoneName),
+		TakenAt: snapshotter.clock.Now().UTC(),
+		Records: records.SortedSlice(),
+	}
+
+	index, err := snapshotter.index(ctx, zoneName)
+	if err != nil {
+		return
+	}
+
+	value, err := json.Marshal(snapshot)
+	if err != nil {
+		return
+	}
+	if err = snapshotter.store.Put(ctx, snapshotKey(zoneName, snapshot.TakenAt), value); err != nil {
+		return
+	}
+
+	index = append(index, snapshot.TakenAt.UnixNano())
+	if snapshotter.retention > 0 && len(index) > snapshotter.retention {
+		index = index[len(index)-snapshotter.retention:]
+	}
+
+	err = snapshotter.putIndex(ctx, zoneName, index)
+	return
+}
+
+// At returns the latest snapshot of the zone taken at or before the given time. False is returned if no such snapshot
+// exists, e.g. because the time is before the first snapshot or outside of the retention.
+func (snapshotter *Snapshotter) At(ctx context.Context, zoneName string, at time.Time) (ZoneSnapshot, bool, error) {
+	index, err := snapshotter.index(ctx, zoneName)
+	if err != nil {
+		return ZoneSnapshot{}, false, err
+	}
+
+	position := sort.Search(len(index), func(i int) bool {
+		return index[i] > at.UnixNano()
+	})
+	if position == 0 {
+		return ZoneSnapshot{}, false, nil
+	}
+
+	value, ok, err := snapshotter.store.Get(ctx, snapshotKey(zoneName, time.Unix(0, index[position-1])))
+	if err != nil || !ok {
+		return ZoneSnapshot{}, false, err
+	}
+
+	var snapshot ZoneSnapshot
+	if err := json.Unmarshal(value, &snapshot); err != nil {
+		return ZoneSnapshot{}, false, err
+	}
+
+	return snapshot, true, nil
+}
+
+// RecordsAt returns all records with the given host and type as they existed within the latest snapshot taken at or
+// before the given time
+func (snapshotter *Snapshotter) RecordsAt(ctx context.Context, zoneName, host string, recordType RecordType, at time.Time) ([]Record, error) {
+	snapshot, ok, err := snapshotter.At(ctx, zoneName, at)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	key := recordSetKey(host, recordType)
+	var results []Record
+	for _, record := range snapshot.Records {
+		if recordSetKey(record.Host, record.RecordType) == key {
+			results = append(results, record)
+		}
+	}
+
+	return results, nil
+}
+
+// index returns the timestamps of all stored snapshots of the zone in ascending order
+func (snapshotter *Snapshotter) index(ctx context.Context, zoneName string) ([]int64, error) {
+	value, ok, err := snapshotter.store.Get(ctx, snapshotIndexKey(zoneName))
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	var index []int64
+	if err := json.Unmarshal(value, &index); err != nil {
+		return nil, err
+	}
+
+	return index, nil
+}
+
+func (snapshotter *Snapshotter) putIndex(ctx context.Context, zoneName string, index []int64) error {
+	value, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+
+	return snapshotter.store.Put(ctx, snapshotIndexKey(zoneName), value)
+}
+
+func snapshotIndexKey(zoneName string) string {
+	return "snapshots/" + normalizeHostname(zoneName) + "/index"
+}
+
+func snapshotKey(zoneName string, takenAt time.Time) string {
+	return "snapshots/" + normalizeHostname(zoneName) + "/" + strconv.FormatInt(takenAt.UnixNano(), 10)
+}
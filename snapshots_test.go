@@ -0,0 +1,74 @@
+package cloudns
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestSnapshotter(t *testing.T) {
+	// given
+	start := time.Date(2022, 12, 20, 0, 0, 0, 0, time.UTC)
+	clock := NewManualClock(start)
+	responses := []string{
+		`{"1":{"id":"1","host":"www","record":"192.0.2.1","type":"A","ttl":"3600","status":1}}`,
+		`{"1":{"id":"1","host":"www","record":"192.0.2.2","type":"A","ttl":"3600","status":1}}`,
+		`{"1":{"id":"1","host":"www","record":"192.0.2.3","type":"A","ttl":"3600","status":1}}`,
+	}
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		response := responses[0]
+		responses = responses[1:]
+		return response
+	}, CustomClock(clock))
+	snapshotter := NewSnapshotter(stubClient, NewMemoryStore(), 2)
+
+	// when
+	ctx := context.Background()
+	for range []int{1, 2, 3} {
+		_, err := snapshotter.Snapshot(ctx, testDomain)
+		assert.NoError(t, err)
+		clock.Advance(24 * time.Hour)
+	}
+
+	// then
+	_, ok, err := snapshotter.At(ctx, testDomain, start.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.False(t, ok, "first snapshot should have been dropped by retention")
+
+	records, err := snapshotter.RecordsAt(ctx, testDomain, "www", RecordTypeA, start.Add(36*time.Hour))
+	assert.NoError(t, err)
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, "192.0.2.2", records[0].Record)
+	}
+
+	snapshot, ok, err := snapshotter.At(ctx, testDomain, start.Add(100*time.Hour))
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, start.Add(48*time.Hour), snapshot.TakenAt)
+}
+
+func TestSnapshotter_Run(t *testing.T) {
+	// given
+	runCtx, cancel := context.WithCancel(context.Background())
+	var requests int
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		requests++
+		if requests == 3 {
+			cancel()
+		}
+		return `[]`
+	}, CustomClock(NewManualClock(time.Now())))
+	store := NewMemoryStore()
+
+	// when
+	err := NewSnapshotter(stubClient, store, 0).Run(runCtx, time.Hour, testDomain)
+
+	// then
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 3, requests)
+
+	index, _, _ := store.Get(context.Background(), snapshotIndexKey(testDomain))
+	assert.NotEmpty(t, index)
+}
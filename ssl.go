@@ -0,0 +1,106 @@
+package cloudns
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+)
+
+const sslOrderURL = "/ssl/order-new-certificate.json"
+const sslListURL = "/ssl/list-certificates.json"
+const sslInfoURL = "/ssl/certificate-info.json"
+const sslReissueURL = "/ssl/reissue-certificate.json"
+
+// SSLService is a service object which groups all operations related to SSL certificates sold by ClouDNS. Orders are
+// charged to the balance of the account.
+type SSLService struct {
+	api *Client
+}
+
+// SSLCertificate represents an SSL certificate ordered through ClouDNS
+type SSLCertificate struct {
+	ID     int    `json:"id,string"`
+	Domain string `json:"domain"`
+	// Product is the name of the certificate product, e.g. `PositiveSSL`
+	Product string `json:"type"`
+	Status  string `json:"status"`
+	// ExpirationDate is formatted as `YYYY-MM-DD` and empty for certificates which have not been issued yet
+	ExpirationDate string `json:"expiration_date"`
+	// Certificate contains the PEM-encoded certificate, only available for issued certificates returned by GetInfo
+	Certificate string `json:"certificate"`
+}
+
+// SSLOrder specifies a new SSL certificate which gets ordered
+type SSLOrder struct {
+	// Product is the name of the certificate product, e.g. `PositiveSSL`
+	Product string
+	Domain  string
+	// Period is the validity period in years
+	Period int
+	// CSR is the PEM-encoded certificate signing request
+	CSR string
+	// ApproverMail receives the mail for validating the control over the domain
+	ApproverMail string
+}
+
+// SSLOrderResult represents the result of ordering an SSL certificate
+type SSLOrderResult struct {
+	StatusResult
+	CertificateID int `json:"id,string"`
+}
+
+// Order orders a new SSL certificate, which gets issued after the control over the domain has been validated
+func (svc *SSLService) Order(ctx context.Context, order SSLOrder) (result SSLOrderResult, err error) {
+	if strings.TrimSpace(order.CSR) == "" {
+		return result, ErrIllegalArgument.wrap(errors.New("certificate signing request is required"))
+	}
+
+	params := HTTPParams{
+		"certificate-type": order.Product,
+		"domain":           order.Domain,
+		"period":           order.Period,
+		"csr":              order.CSR,
+		"mail":             order.ApproverMail,
+	}
+
+	err = svc.api.request(ctx, "POST", sslOrderURL, params, nil, &result)
+	return
+}
+
+// List returns all SSL certificates of the account sorted by their ID
+func (svc *SSLService) List(ctx context.Context) ([]SSLCertificate, error) {
+	var result map[string]SSLCertificate
+	if err := svc.api.request(ctx, "POST", sslListURL, nil, nil, &result); err != nil {
+		return nil, err
+	}
+
+	certificates := make([]SSLCertificate, 0, len(result))
+	for _, certificate := range result {
+		certificates = append(certificates, certificate)
+	}
+	sort.Slice(certificates, func(i, j int) bool {
+		return certificates[i].ID < certificates[j].ID
+	})
+
+	return certificates, nil
+}
+
+// GetInfo returns the details of the SSL certificate with the given ID, including the certificate itself once issued
+func (svc *SSLService) GetInfo(ctx context.Context, certificateID int) (result SSLCertificate, err error) {
+	params := HTTPParams{"id": certificateID}
+	err = svc.api.request(ctx, "POST", sslInfoURL, params, nil, &result)
+	return
+}
+
+// Reissue requests a new certificate for an existing SSL certificate using the given certificate signing request, e.g.
+// after its private key has been compromised
+func (svc *SSLService) Reissue(ctx context.Context, certificateID int, csr string) (result StatusResult, err error) {
+	if strings.TrimSpace(csr) == "" {
+		return result, ErrIllegalArgument.wrap(errors.New("certificate signing request is required"))
+	}
+
+	params := HTTPParams{"id": certificateID, "csr": csr}
+	err = svc.api.request(ctx, "POST", sslReissueURL, params, nil, &result)
+	return
+}
@@ -0,0 +1,118 @@
+package cloudns
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+const testCSR = "-----BEGIN CERTIFICATE REQUEST-----\nMIIB\n-----END CERTIFICATE REQUEST-----"
+
+func TestSSLService_Order(t *testing.T) {
+	// given
+	var params map[string]interface{}
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		_ = json.NewDecoder(req.Body).Decode(&params)
+		return `{"status":"Success","statusDescription":"The certificate was ordered successfully.","id":"42"}`
+	})
+
+	// when
+	result, err := stubClient.SSL.Order(context.Background(), SSLOrder{
+		Product:      "PositiveSSL",
+		Domain:       "www.example.com",
+		Period:       1,
+		CSR:          testCSR,
+		ApproverMail: "admin@example.com",
+	})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "Success", result.Status)
+	assert.Equal(t, 42, result.CertificateID)
+	assert.Equal(t, "PositiveSSL", params["certificate-type"])
+	assert.Equal(t, "www.example.com", params["domain"])
+	assert.EqualValues(t, 1, params["period"])
+	assert.Equal(t, testCSR, params["csr"])
+	assert.Equal(t, "admin@example.com", params["mail"])
+}
+
+func TestSSLService_Order_MissingCSR(t *testing.T) {
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		t.Error("request should not be sent")
+		return `{"status":"Success"}`
+	})
+
+	_, err := stubClient.SSL.Order(context.Background(), SSLOrder{Product: "PositiveSSL", Domain: "example.com"})
+	assert.ErrorIs(t, err, ErrIllegalArgument)
+}
+
+func TestSSLService_List(t *testing.T) {
+	// given
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `{
+			"12":{"id":"12","domain":"b.example.com","type":"PositiveSSL Wildcard","status":"pending"},
+			"4":{"id":"4","domain":"a.example.com","type":"PositiveSSL","status":"active","expiration_date":"2027-01-01"}
+		}`
+	})
+
+	// when
+	certificates, err := stubClient.SSL.List(context.Background())
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []SSLCertificate{
+		{ID: 4, Domain: "a.example.com", Product: "PositiveSSL", Status: "active", ExpirationDate: "2027-01-01"},
+		{ID: 12, Domain: "b.example.com", Product: "PositiveSSL Wildcard", Status: "pending"},
+	}, certificates)
+}
+
+func TestSSLService_List_Empty(t *testing.T) {
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `[]`
+	})
+
+	certificates, err := stubClient.SSL.List(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, certificates)
+}
+
+func TestSSLService_GetInfo(t *testing.T) {
+	// given
+	var params map[string]interface{}
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		_ = json.NewDecoder(req.Body).Decode(&params)
+		return `{"id":"4","domain":"a.example.com","type":"PositiveSSL","status":"active","certificate":"-----BEGIN CERTIFICATE-----"}`
+	})
+
+	// when
+	certificate, err := stubClient.SSL.GetInfo(context.Background(), 4)
+
+	// then
+	assert.NoError(t, err)
+	assert.EqualValues(t, 4, params["id"])
+	assert.Equal(t, 4, certificate.ID)
+	assert.Equal(t, "-----BEGIN CERTIFICATE-----", certificate.Certificate)
+}
+
+func TestSSLService_Reissue(t *testing.T) {
+	// given
+	var path string
+	var params map[string]interface{}
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		path = req.URL.Path
+		_ = json.NewDecoder(req.Body).Decode(&params)
+		return `{"status":"Success","statusDescription":"The certificate will be reissued."}`
+	})
+
+	// when
+	result, err := stubClient.SSL.Reissue(context.Background(), 4, testCSR)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "Success", result.Status)
+	assert.Equal(t, sslReissueURL, path)
+	assert.EqualValues(t, 4, params["id"])
+	assert.Equal(t, testCSR, params["csr"])
+}
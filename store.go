@@ -0,0 +1,90 @@
+package cloudns
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Store persists the state of long-running components, e.g. positions of a watcher or the progress of an import, so
+// that restarts neither lose progress nor cause duplicate work. Unlike Cache, failures are reported to the caller, as
+// losing state is not acceptable for these components.
+type Store interface {
+	// Get returns the value stored for the given key and true, or false if no value is present
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Put stores the value for the given key, replacing any previous value
+	Put(ctx context.Context, key string, value []byte) error
+}
+
+// MemoryStore is an in-memory Store, mainly intended for testing or for components which do not need to survive
+// restarts
+type MemoryStore struct {
+	mutex  sync.RWMutex
+	values map[string][]byte
+}
+
+// FileStore is a persistent Store which stores every value as a separate file within a directory
+type FileStore struct {
+	directory string
+}
+
+// NewMemoryStore instantiates a new empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{values: make(map[string][]byte)}
+}
+
+// NewFileStore instantiates a new persistent store within the given directory, which gets created if missing
+func NewFileStore(directory string) (*FileStore, error) {
+	if err := os.MkdirAll(directory, 0o700); err != nil {
+		return nil, err
+	}
+
+	return &FileStore{directory: directory}, nil
+}
+
+// Get returns the value stored for the given key
+func (store *MemoryStore) Get(_ context.Context, key string) ([]byte, bool, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	value, ok := store.values[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	return append([]byte(nil), value...), true, nil
+}
+
+// Put stores the value for the given key
+func (store *MemoryStore) Put(_ context.Context, key string, value []byte) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	store.values[key] = append([]byte(nil), value...)
+	return nil
+}
+
+// Get returns the value stored for the given key
+func (store *FileStore) Get(_ context.Context, key string) ([]byte, bool, error) {
+	value, err := os.ReadFile(store.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	return value, true, nil
+}
+
+// Put stores the value for the given key. The file is replaced atomically, so that a crash while writing never leaves
+// a partially written value behind.
+func (store *FileStore) Put(_ context.Context, key string, value []byte) error {
+	return writeFileAtomic(store.directory, store.path(key), value)
+}
+
+func (store *FileStore) path(key string) string {
+	return filepath.Join(store.directory, url.PathEscape(key))
+}
@@ -0,0 +1,40 @@
+package cloudns
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testStore(t *testing.T, store Store) {
+	ctx := context.Background()
+
+	_, ok, err := store.Get(ctx, "watcher/api-example.com")
+	assert.NoError(t, err)
+	assert.False(t, ok, "missing key should not be found")
+
+	assert.NoError(t, store.Put(ctx, "watcher/api-example.com", []byte("first")))
+	assert.NoError(t, store.Put(ctx, "watcher/api-example.com", []byte("second")))
+
+	value, ok, err := store.Get(ctx, "watcher/api-example.com")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("second"), value, "latest value should be returned")
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore())
+}
+
+func TestFileStore(t *testing.T) {
+	directory := filepath.Join(t.TempDir(), "state")
+	store, err := NewFileStore(directory)
+	assert.NoError(t, err)
+
+	testStore(t, store)
+
+	entries, _ := os.ReadDir(directory)
+	assert.Len(t, entries, 1, "key should be stored as single file without leftovers")
+}
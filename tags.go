@@ -0,0 +1,497 @@
+package cloudns
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// tagHeritage marks TXT records created by the TXTTagStore, which prevents interpreting unrelated TXT records as tags
+const tagHeritage = "cloudns-go"
+
+// DefaultTagPrefix is the host prefix used by TXTTagStore for companion TXT records if no other prefix was specified
+const DefaultTagPrefix = "_cloudns-go"
+
+// ownedTagKey is the reserved tag listing the values of all records created by RecordService.CreateOwned within a
+// record set, which allows telling them apart from records added by humans to the same record set
+const ownedTagKey = "owned"
+
+// RecordTags represents a set of labels attached to a set of records sharing the same host and type, e.g. to track
+// the owner, ticket or system which created them
+type RecordTags map[string]string
+
+// TaggedRecordSet represents the tags attached to all records of a given host and type
+type TaggedRecordSet struct {
+	Host       string
+	RecordType RecordType
+	Tags       RecordTags
+}
+
+// TagStore persists tags for sets of records sharing the same host and type within a zone. Tags are attached to record
+// sets instead of single records, as record IDs are not stable across deletion and recreation.
+type TagStore interface {
+	// GetTags returns the tags of the given record set or nil if it has no tags
+	GetTags(ctx context.Context, zoneName, host string, recordType RecordType) (RecordTags, error)
+	// SetTags replaces the tags of the given record set
+	SetTags(ctx context.Context, zoneName, host string, recordType RecordType, tags RecordTags) error
+	// DeleteTags removes all tags of the given record set
+	DeleteTags(ctx context.Context, zoneName, host string, recordType RecordType) error
+	// ListTags returns all tagged record sets within the given zone
+	ListTags(ctx context.Context, zoneName string) ([]TaggedRecordSet, error)
+}
+
+// TXTTagStore is a TagStore which encodes tags in companion TXT records within the zone itself, similar to the TXT
+// registry of external-dns. The tags of `www` with type `A` are stored in a TXT record at `<prefix>.www`.
+type TXTTagStore struct {
+	records *RecordService
+	prefix  string
+	ttl     int
+}
+
+// MemoryTagStore is a TagStore which keeps all tags in memory, mainly intended for testing or as a reference for
+// implementing TagStore on top of an external database
+type MemoryTagStore struct {
+	mutex sync.RWMutex
+	tags  map[string]map[string]TaggedRecordSet
+}
+
+// NewTXTTagStore instantiates a TagStore which stores tags as TXT records using the given record service. An empty
+// prefix defaults to DefaultTagPrefix.
+func NewTXTTagStore(records *RecordService, prefix string, ttl int) *TXTTagStore {
+	if prefix == "" {
+		prefix = DefaultTagPrefix
+	}
+
+	return &TXTTagStore{records: records, prefix: prefix, ttl: ttl}
+}
+
+// NewMemoryTagStore instantiates an empty in-memory TagStore
+func NewMemoryTagStore() *MemoryTagStore {
+	return &MemoryTagStore{tags: make(map[string]map[string]TaggedRecordSet)}
+}
+
+// FindByTags returns all records of the zone whose record set has all tags of the given selector. An empty selector
+// matches all tagged records.
+func (svc *RecordService) FindByTags(ctx context.Context, zoneName string, store TagStore, selector RecordTags) ([]Record, error) {
+	taggedSets, err := store.ListTags(ctx, zoneName)
+	if err != nil {
+		return nil, err
+	}
+
+	matchingSets := make(map[string]bool)
+	for _, taggedSet := range taggedSets {
+		if taggedSet.Tags.Matches(selector) {
+			matchingSets[recordSetKey(taggedSet.Host, taggedSet.RecordType)] = true
+		}
+	}
+	if len(matchingSets) == 0 {
+		return nil, nil
+	}
+
+	records, err := svc.List(ctx, zoneName)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []Record
+	for _, record := range records.SortedSlice() {
+		if matchingSets[recordSetKey(record.Host, record.RecordType)] {
+			results = append(results, record)
+		}
+	}
+
+	return results, nil
+}
+
+// Matches returns true if the tags contain all keys of the selector with the same values
+func (tags RecordTags) Matches(selector RecordTags) bool {
+	for key, value := range selector {
+		if actual, ok := tags[key]; !ok || actual != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GetTags returns the tags of the given record set
+func (store *TXTTagStore) GetTags(ctx context.Context, zoneName, host string, recordType RecordType) (RecordTags, error) {
+	record, tags, err := store.find(ctx, zoneName, host, recordType)
+	if err != nil || record == nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// SetTags creates or updates the companion TXT record of the given record set
+func (store *TXTTagStore) SetTags(ctx context.Context, zoneName, host string, recordType RecordType, tags RecordTags) error {
+	value, err := encodeTags(recordType, tags)
+	if err != nil {
+		return err
+	}
+
+	existing, _, err := store.find(ctx, zoneName, host, recordType)
+	if err != nil {
+		return err
+	}
+
+	record := NewRecordTXT(store.txtHost(host), value, store.ttl)
+	if existing != nil {
+		_, err = store.records.Update(ctx, zoneName, existing.ID, record)
+	} else {
+		_, err = store.records.Create(ctx, zoneName, record)
+	}
+
+	return err
+}
+
+// DeleteTags removes the companion TXT record of the given record set, if present
+func (store *TXTTagStore) DeleteTags(ctx context.Context, zoneName, host string, recordType RecordType) error {
+	existing, _, err := store.find(ctx, zoneName, host, recordType)
+	if err != nil || existing == nil {
+		return err
+	}
+
+	_, err = store.records.Delete(ctx, zoneName, existing.ID)
+	return err
+}
+
+// ListTags returns all record sets within the zone which have a companion TXT record
+func (store *TXTTagStore) ListTags(ctx context.Context, zoneName string) ([]TaggedRecordSet, error) {
+	records, err := store.records.Search(ctx, zoneName, "", RecordTypeTXT)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []TaggedRecordSet
+	for _, record := range records.SortedSlice() {
+		host, ok := store.ownerHost(record.Host)
+		if !ok {
+			continue
+		}
+
+		recordType, tags, ok := decodeTags(record.Record)
+		if !ok {
+			continue
+		}
+
+		results = append(results, TaggedRecordSet{Host: host, RecordType: recordType, Tags: tags})
+	}
+
+	return results, nil
+}
+
+func (store *TXTTagStore) find(ctx context.Context, zoneName, host string, recordType RecordType) (*Record, RecordTags, error) {
+	records, err := store.records.Search(ctx, zoneName, store.txtHost(host), RecordTypeTXT)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	txtHost := normalizeHostname(store.txtHost(host))
+	for _, record := range records.SortedSlice() {
+		if normalizeHostname(record.Host) != txtHost {
+			continue
+		}
+
+		if taggedType, tags, ok := decodeTags(record.Record); ok && taggedType == recordType {
+			return &record, tags, nil
+		}
+	}
+
+	return nil, nil, nil
+}
+
+func (store *TXTTagStore) txtHost(host string) string {
+	host = normalizeHostname(host)
+	if host == "" || host == "@" {
+		return store.prefix
+	}
+
+	return store.prefix + "." + host
+}
+
+func (store *TXTTagStore) ownerHost(txtHost string) (string, bool) {
+	txtHost = normalizeHostname(txtHost)
+	prefix := strings.ToLower(store.prefix)
+
+	switch {
+	case txtHost == prefix:
+		return "", true
+	case strings.HasPrefix(txtHost, prefix+"."):
+		return strings.TrimPrefix(txtHost, prefix+"."), true
+	}
+
+	return "", false
+}
+
+// GetTags returns the tags of the given record set
+func (store *MemoryTagStore) GetTags(_ context.Context, zoneName, host string, recordType RecordType) (RecordTags, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	taggedSet, ok := store.tags[normalizeHostname(zoneName)][recordSetKey(host, recordType)]
+	if !ok {
+		return nil, nil
+	}
+
+	return copyTags(taggedSet.Tags), nil
+}
+
+// SetTags replaces the tags of the given record set
+func (store *MemoryTagStore) SetTags(_ context.Context, zoneName, host string, recordType RecordType, tags RecordTags) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	zoneName = normalizeHostname(zoneName)
+	if store.tags[zoneName] == nil {
+		store.tags[zoneName] = make(map[string]TaggedRecordSet)
+	}
+
+	store.tags[zoneName][recordSetKey(host, recordType)] = TaggedRecordSet{
+		Host:       normalizeRecordHost(host),
+		RecordType: recordType,
+		Tags:       copyTags(tags),
+	}
+
+	return nil
+}
+
+// DeleteTags removes all tags of the given record set
+func (store *MemoryTagStore) DeleteTags(_ context.Context, zoneName, host string, recordType RecordType) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	delete(store.tags[normalizeHostname(zoneName)], recordSetKey(host, recordType))
+	return nil
+}
+
+// ListTags returns all tagged record sets within the given zone, sorted by host and type
+func (store *MemoryTagStore) ListTags(_ context.Context, zoneName string) ([]TaggedRecordSet, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	var results []TaggedRecordSet
+	for _, taggedSet := range store.tags[normalizeHostname(zoneName)] {
+		taggedSet.Tags = copyTags(taggedSet.Tags)
+		results = append(results, taggedSet)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return recordSetKey(results[i].Host, results[i].RecordType) < recordSetKey(results[j].Host, results[j].RecordType)
+	})
+
+	return results, nil
+}
+
+// encodeTags encodes the tags of a record set into the value of a companion TXT record
+func encodeTags(recordType RecordType, tags RecordTags) (string, error) {
+	values := make(url.Values)
+	for key, value := range tags {
+		if key == "" || key == "heritage" || key == "type" {
+			return "", ErrIllegalArgument.wrap(errors.New("tag keys must not be empty or reserved"))
+		}
+		values.Set(key, value)
+	}
+
+	values.Set("heritage", tagHeritage)
+	values.Set("type", string(recordType))
+	return values.Encode(), nil
+}
+
+// decodeTags decodes the value of a companion TXT record, returning false if it was not created by cloudns-go
+func decodeTags(value string) (RecordType, RecordTags, bool) {
+	values, err := url.ParseQuery(value)
+	if err != nil || values.Get("heritage") != tagHeritage || values.Get("type") == "" {
+		return RecordTypeUnknown, nil, false
+	}
+
+	recordType := RecordType(values.Get("type"))
+	tags := make(RecordTags)
+	for key := range values {
+		if key != "heritage" && key != "type" {
+			tags[key] = values.Get(key)
+		}
+	}
+
+	return recordType, tags, true
+}
+
+// recordSetKey returns a key identifying all records of a given host and type
+func recordSetKey(host string, recordType RecordType) string {
+	return string(recordType) + "|" + normalizeRecordHost(host)
+}
+
+// normalizeRecordHost normalizes a host relative to a zone, using an empty string for the zone apex
+func normalizeRecordHost(host string) string {
+	host = normalizeHostname(host)
+	if host == "@" {
+		return ""
+	}
+
+	return host
+}
+
+func copyTags(tags RecordTags) RecordTags {
+	result := make(RecordTags, len(tags))
+	for key, value := range tags {
+		result[key] = value
+	}
+
+	return result
+}
+
+// GarbageCollection represents the result of RecordService.CollectGarbage
+type GarbageCollection struct {
+	// Deleted contains all owned records which were no longer desired and have been deleted
+	Deleted []Record
+	// Released contains all owned record sets which were no longer desired at all and whose tags have been removed
+	Released []TaggedRecordSet
+}
+
+// CreateOwned creates a new record within the given zone and attaches the given tags to its record set, marking the
+// record as owned by automation. Existing tags of the record set are replaced, except for the reserved `owned` tag,
+// which lists the values of all records created by CreateOwned within the record set.
+func (svc *RecordService) CreateOwned(ctx context.Context, zoneName string, record Record, store TagStore, tags RecordTags) (result StatusResult, err error) {
+	if _, ok := tags[ownedTagKey]; ok {
+		return result, ErrIllegalArgument.wrap(errors.New("tag key owned is reserved"))
+	}
+
+	existing, err := store.GetTags(ctx, zoneName, record.Host, record.RecordType)
+	if err != nil {
+		return
+	}
+	if result, err = svc.Create(ctx, zoneName, record); err != nil {
+		return
+	}
+
+	tags = copyTags(tags)
+	setOwnedValues(tags, append(existing.ownedValues(), normalizeRecordValue(record)))
+	err = store.SetTags(ctx, zoneName, record.Host, record.RecordType, tags)
+	return
+}
+
+// ownedValues returns the values of all records listed by the reserved `owned` tag
+func (tags RecordTags) ownedValues() []string {
+	var values []string
+	for _, value := range strings.Split(tags[ownedTagKey], ",") {
+		if value, err := url.QueryUnescape(value); err == nil && value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+// setOwnedValues replaces the reserved `owned` tag with the given unique values, removing the tag if there are none
+func setOwnedValues(tags RecordTags, values []string) {
+	seen := make(map[string]bool)
+	var escaped []string
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			escaped = append(escaped, url.QueryEscape(value))
+		}
+	}
+
+	if len(escaped) == 0 {
+		delete(tags, ownedTagKey)
+		return
+	}
+	tags[ownedTagKey] = strings.Join(escaped, ",")
+}
+
+// CollectGarbage deletes all records within the zone which are owned by automation, identified by record sets having
+// all tags of the owner selector and the record value being listed as created by CreateOwned, but are not part of the
+// desired records anymore. Records of untagged record sets and records added by humans to owned record sets are never
+// touched. Tags of owned record sets without any desired records are removed as well, while the deleted values are
+// removed from the tags of all other record sets. If dryRun is true, the records which would be deleted are returned
+// without performing any changes.
+func (svc *RecordService) CollectGarbage(ctx context.Context, zoneName string, store TagStore, owner RecordTags, desired []Record, dryRun bool) (result GarbageCollection, err error) {
+	if len(owner) == 0 {
+		return result, ErrIllegalArgument.wrap(errors.New("owner selector must not be empty"))
+	}
+
+	taggedSets, err := store.ListTags(ctx, zoneName)
+	if err != nil {
+		return
+	}
+
+	ownedRecords, err := svc.FindByTags(ctx, zoneName, store, owner)
+	if err != nil {
+		return
+	}
+
+	desiredSets := make(map[string]bool)
+	for _, record := range desired {
+		desiredSets[recordSetKey(record.Host, record.RecordType)] = true
+	}
+
+	ownedValues := make(map[string]map[string]bool)
+	for _, taggedSet := range taggedSets {
+		if taggedSet.Tags.Matches(owner) {
+			key := recordSetKey(taggedSet.Host, taggedSet.RecordType)
+			ownedValues[key] = make(map[string]bool)
+			for _, value := range taggedSet.Tags.ownedValues() {
+				ownedValues[key][value] = true
+			}
+		}
+	}
+
+	deletedValues := make(map[string]map[string]bool)
+	for _, record := range ownedRecords {
+		key := recordSetKey(record.Host, record.RecordType)
+		value := normalizeRecordValue(record)
+		if ownedValues[key][value] && indexOfEquivalentRecord(desired, record) < 0 {
+			result.Deleted = append(result.Deleted, record)
+			if deletedValues[key] == nil {
+				deletedValues[key] = make(map[string]bool)
+			}
+			deletedValues[key][value] = true
+		}
+	}
+
+	var pruned []TaggedRecordSet
+	for _, taggedSet := range taggedSets {
+		key := recordSetKey(taggedSet.Host, taggedSet.RecordType)
+		switch {
+		case !taggedSet.Tags.Matches(owner):
+		case !desiredSets[key]:
+			result.Released = append(result.Released, taggedSet)
+		case len(deletedValues[key]) > 0:
+			var remaining []string
+			for _, value := range taggedSet.Tags.ownedValues() {
+				if !deletedValues[key][value] {
+					remaining = append(remaining, value)
+				}
+			}
+			taggedSet.Tags = copyTags(taggedSet.Tags)
+			setOwnedValues(taggedSet.Tags, remaining)
+			pruned = append(pruned, taggedSet)
+		}
+	}
+
+	if dryRun {
+		return
+	}
+
+	for _, record := range result.Deleted {
+		if _, err = svc.Delete(ctx, zoneName, record.ID); err != nil {
+			return
+		}
+	}
+	for _, taggedSet := range pruned {
+		if err = store.SetTags(ctx, zoneName, taggedSet.Host, taggedSet.RecordType, taggedSet.Tags); err != nil {
+			return
+		}
+	}
+	for _, taggedSet := range result.Released {
+		if err = store.DeleteTags(ctx, zoneName, taggedSet.Host, taggedSet.RecordType); err != nil {
+			return
+		}
+	}
+
+	return
+}
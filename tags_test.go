@@ -0,0 +1,191 @@
+package cloudns
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestEncodeDecodeTags(t *testing.T) {
+	value, err := encodeTags(RecordTypeA, RecordTags{"owner": "team a", "ticket": "OPS-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "heritage=cloudns-go&owner=team+a&ticket=OPS-1&type=A", value)
+
+	recordType, tags, ok := decodeTags(value)
+	assert.True(t, ok)
+	assert.Equal(t, RecordTypeA, recordType)
+	assert.Equal(t, RecordTags{"owner": "team a", "ticket": "OPS-1"}, tags)
+
+	_, _, ok = decodeTags("v=spf1 -all")
+	assert.False(t, ok, "unrelated TXT records should not be decoded")
+
+	_, err = encodeTags(RecordTypeA, RecordTags{"type": "AAAA"})
+	assert.True(t, errors.Is(err, ErrIllegalArgument), "reserved keys should be rejected")
+}
+
+func TestRecordTags_Matches(t *testing.T) {
+	tags := RecordTags{"owner": "a", "system": "ci"}
+
+	assert.True(t, tags.Matches(nil))
+	assert.True(t, tags.Matches(RecordTags{"owner": "a"}))
+	assert.False(t, tags.Matches(RecordTags{"owner": "b"}))
+	assert.False(t, tags.Matches(RecordTags{"ticket": "1"}))
+}
+
+func TestMemoryTagStore(t *testing.T) {
+	store := NewMemoryTagStore()
+	ctx := context.Background()
+
+	assert.NoError(t, store.SetTags(ctx, testDomain, "www", RecordTypeA, RecordTags{"owner": "a"}))
+	assert.NoError(t, store.SetTags(ctx, testDomain, "@", RecordTypeMX, RecordTags{"owner": "b"}))
+
+	tags, err := store.GetTags(ctx, testDomain+".", "WWW.", RecordTypeA)
+	assert.NoError(t, err)
+	assert.Equal(t, RecordTags{"owner": "a"}, tags)
+
+	sets, err := store.ListTags(ctx, testDomain)
+	assert.NoError(t, err)
+	assert.Equal(t, []TaggedRecordSet{
+		{Host: "www", RecordType: RecordTypeA, Tags: RecordTags{"owner": "a"}},
+		{Host: "", RecordType: RecordTypeMX, Tags: RecordTags{"owner": "b"}},
+	}, sets)
+
+	assert.NoError(t, store.DeleteTags(ctx, testDomain, "www", RecordTypeA))
+	tags, err = store.GetTags(ctx, testDomain, "www", RecordTypeA)
+	assert.NoError(t, err)
+	assert.Nil(t, tags)
+}
+
+func TestTXTTagStore(t *testing.T) {
+	// given
+	var created map[string]interface{}
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		switch req.URL.Path {
+		case recordListURL:
+			return `{
+				"1":{"id":"1","host":"_cloudns-go.www","record":"heritage=cloudns-go&owner=a&type=A","type":"TXT","ttl":"3600","status":1},
+				"2":{"id":"2","host":"_cloudns-go","record":"heritage=cloudns-go&owner=b&type=MX","type":"TXT","ttl":"3600","status":1},
+				"3":{"id":"3","host":"","record":"v=spf1 -all","type":"TXT","ttl":"3600","status":1},
+				"4":{"id":"4","host":"www","record":"192.0.2.1","type":"A","ttl":"3600","status":1},
+				"5":{"id":"5","host":"","record":"mx1.local","type":"MX","ttl":"3600","status":1}
+			}`
+		case recordCreateURL:
+			_ = json.NewDecoder(req.Body).Decode(&created)
+		}
+		return `{"status":"Success","statusDescription":"OK"}`
+	})
+	store := NewTXTTagStore(stubClient.Records, "", testTTL)
+	ctx := context.Background()
+
+	// when
+	sets, listErr := store.ListTags(ctx, testDomain)
+	tags, getErr := store.GetTags(ctx, testDomain, "www", RecordTypeA)
+	setErr := store.SetTags(ctx, testDomain, "blog", RecordTypeCNAME, RecordTags{"owner": "c"})
+	records, findErr := stubClient.Records.FindByTags(ctx, testDomain, store, RecordTags{"owner": "a"})
+
+	// then
+	assert.NoError(t, listErr)
+	assert.Equal(t, []TaggedRecordSet{
+		{Host: "www", RecordType: RecordTypeA, Tags: RecordTags{"owner": "a"}},
+		{Host: "", RecordType: RecordTypeMX, Tags: RecordTags{"owner": "b"}},
+	}, sets)
+
+	assert.NoError(t, getErr)
+	assert.Equal(t, RecordTags{"owner": "a"}, tags)
+
+	assert.NoError(t, setErr)
+	assert.Equal(t, "_cloudns-go.blog", created["host"])
+	assert.Equal(t, "heritage=cloudns-go&owner=c&type=CNAME", created["record"])
+
+	assert.NoError(t, findErr)
+	assert.Len(t, records, 1)
+	assert.Equal(t, 4, records[0].ID)
+}
+
+func TestRecordService_CollectGarbage(t *testing.T) {
+	// given
+	var deleted []string
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		switch req.URL.Path {
+		case recordListURL:
+			return `{
+				"1":{"id":"1","host":"www","record":"192.0.2.1","type":"A","ttl":"3600","status":1},
+				"2":{"id":"2","host":"www","record":"192.0.2.2","type":"A","ttl":"3600","status":1},
+				"3":{"id":"3","host":"old","record":"192.0.2.3","type":"A","ttl":"3600","status":1},
+				"4":{"id":"4","host":"manual","record":"192.0.2.4","type":"A","ttl":"3600","status":1},
+				"5":{"id":"5","host":"other","record":"192.0.2.5","type":"A","ttl":"3600","status":1},
+				"6":{"id":"6","host":"www","record":"192.0.2.9","type":"A","ttl":"3600","status":1}
+			}`
+		case recordDeleteURL:
+			var params map[string]interface{}
+			_ = json.NewDecoder(req.Body).Decode(&params)
+			deleted = append(deleted, fmt.Sprint(params["record-id"]))
+		}
+		return `{"status":"Success","statusDescription":"OK"}`
+	})
+
+	ctx := context.Background()
+	store := NewMemoryTagStore()
+	_ = store.SetTags(ctx, testDomain, "www", RecordTypeA, RecordTags{"owner": "sync", "owned": "192.0.2.1,192.0.2.2"})
+	_ = store.SetTags(ctx, testDomain, "old", RecordTypeA, RecordTags{"owner": "sync", "owned": "192.0.2.3"})
+	_ = store.SetTags(ctx, testDomain, "other", RecordTypeA, RecordTags{"owner": "someone-else"})
+	desired := []Record{NewRecordA("www", "192.0.2.1", testTTL)}
+
+	// when
+	dryResult, dryErr := stubClient.Records.CollectGarbage(ctx, testDomain, store, RecordTags{"owner": "sync"}, desired, true)
+	dryDeleted := len(deleted)
+	result, err := stubClient.Records.CollectGarbage(ctx, testDomain, store, RecordTags{"owner": "sync"}, desired, false)
+
+	// then
+	assert.NoError(t, dryErr)
+	assert.Equal(t, 0, dryDeleted, "dry run should not delete anything")
+	assert.Equal(t, dryResult, result, "dry run should report same changes")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2", "3"}, deleted, "only undesired owned records should be deleted, not human ones")
+	assert.Equal(t, []TaggedRecordSet{{Host: "old", RecordType: RecordTypeA,
+		Tags: RecordTags{"owner": "sync", "owned": "192.0.2.3"}}}, result.Released)
+
+	remainingSets, _ := store.ListTags(ctx, testDomain)
+	assert.Len(t, remainingSets, 2, "tags of released record set should be removed")
+	tags, _ := store.GetTags(ctx, testDomain, "www", RecordTypeA)
+	assert.Equal(t, RecordTags{"owner": "sync", "owned": "192.0.2.1"}, tags, "deleted values should no longer be owned")
+}
+
+func TestRecordService_CreateOwned(t *testing.T) {
+	// given
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		return `{"status":"Success","statusDescription":"OK"}`
+	})
+	ctx := context.Background()
+	store := NewMemoryTagStore()
+
+	// when
+	_, firstErr := stubClient.Records.CreateOwned(ctx, testDomain, NewRecordA("www", "192.0.2.1", testTTL), store,
+		RecordTags{"owner": "sync"})
+	_, secondErr := stubClient.Records.CreateOwned(ctx, testDomain, NewRecordA("www", "192.0.2.2", testTTL), store,
+		RecordTags{"owner": "sync", "ticket": "OPS-1"})
+	_, reservedErr := stubClient.Records.CreateOwned(ctx, testDomain, NewRecordA("www", "192.0.2.3", testTTL), store,
+		RecordTags{"owned": "192.0.2.4"})
+
+	// then
+	assert.NoError(t, firstErr)
+	assert.NoError(t, secondErr)
+	assert.ErrorIs(t, reservedErr, ErrIllegalArgument)
+
+	tags, _ := store.GetTags(ctx, testDomain, "www", RecordTypeA)
+	assert.Equal(t, RecordTags{"owner": "sync", "ticket": "OPS-1", "owned": "192.0.2.1,192.0.2.2"}, tags,
+		"values of all owned records should be retained")
+}
+
+func TestRecordService_CollectGarbage_EmptyOwner(t *testing.T) {
+	client, err := New()
+	assert.NoError(t, err)
+
+	_, err = client.Records.CollectGarbage(context.Background(), testDomain, NewMemoryTagStore(), nil, nil, true)
+	assert.True(t, errors.Is(err, ErrIllegalArgument), "empty owner selector should be rejected")
+}
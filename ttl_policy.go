@@ -0,0 +1,74 @@
+package cloudns
+
+import (
+	"errors"
+	"fmt"
+)
+
+// TTLPolicy specifies the range of TTLs allowed for records written by a client, see the EnforceTTL option
+type TTLPolicy struct {
+	// Min is the minimum TTL in seconds, which is not enforced if zero
+	Min int
+	// Max is the maximum TTL in seconds, which is not enforced if zero
+	Max int
+	// Clamp adjusts TTLs outside the range to the nearest bound instead of rejecting the write with ErrTTLPolicy
+	Clamp bool
+	// OnClamp is called for every clamped record with its original TTL, e.g. for logging a warning
+	OnClamp func(record Record, originalTTL int)
+}
+
+// validate checks whether the bounds of the policy are consistent
+func (policy TTLPolicy) validate() error {
+	if policy.Min < 0 || policy.Max < 0 {
+		return errors.New("ttl bounds must not be negative")
+	}
+	if policy.Max > 0 && policy.Min > policy.Max {
+		return fmt.Errorf("minimum ttl %d exceeds maximum ttl %d", policy.Min, policy.Max)
+	}
+
+	return nil
+}
+
+// apply returns the record with its TTL clamped to the bounds of the policy, or fails with ErrTTLPolicy if the TTL is
+// out of bounds and clamping is disabled
+func (policy TTLPolicy) apply(record Record) (Record, error) {
+	ttl := record.TTL
+	if policy.Min > 0 && ttl < policy.Min {
+		ttl = policy.Min
+	}
+	if policy.Max > 0 && ttl > policy.Max {
+		ttl = policy.Max
+	}
+	if ttl == record.TTL {
+		return record, nil
+	}
+
+	if !policy.Clamp {
+		return record, ErrTTLPolicy.wrap(fmt.Errorf("ttl %d of %s record [%s] is outside of %s",
+			record.TTL, record.RecordType, record.Host, policy.bounds()))
+	}
+
+	originalTTL := record.TTL
+	record.TTL = ttl
+	if policy.OnClamp != nil {
+		policy.OnClamp(record, originalTTL)
+	}
+
+	return record, nil
+}
+
+func (policy TTLPolicy) bounds() string {
+	if policy.Max == 0 {
+		return fmt.Sprintf("[%d, unlimited]", policy.Min)
+	}
+	return fmt.Sprintf("[%d, %d]", policy.Min, policy.Max)
+}
+
+// applyTTLPolicy applies the TTL policy of the client to a record which is about to be written, if any
+func (c *Client) applyTTLPolicy(record Record) (Record, error) {
+	if c.ttlPolicy == nil {
+		return record, nil
+	}
+
+	return c.ttlPolicy.apply(record)
+}
@@ -0,0 +1,63 @@
+package cloudns
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestEnforceTTL_Reject(t *testing.T) {
+	// given
+	var requests int
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		requests++
+		return `{"status":"Success"}`
+	}, EnforceTTL(TTLPolicy{Min: 300, Max: 86400}))
+
+	// when
+	_, createErr := stubClient.Records.Create(context.Background(), testDomain, NewRecordA("www", "192.0.2.1", 5))
+	_, updateErr := stubClient.Records.Update(context.Background(), testDomain, 1, NewRecordA("www", "192.0.2.1", 604800))
+	_, validErr := stubClient.Records.Create(context.Background(), testDomain, NewRecordA("www", "192.0.2.1", 3600))
+
+	// then
+	assert.ErrorIs(t, createErr, ErrTTLPolicy)
+	assert.Contains(t, createErr.Error(), "ttl 5 of A record [www] is outside of [300, 86400]")
+	assert.ErrorIs(t, updateErr, ErrTTLPolicy)
+	assert.NoError(t, validErr)
+	assert.Equal(t, 1, requests, "rejected writes should not reach api")
+}
+
+func TestEnforceTTL_Clamp(t *testing.T) {
+	// given
+	var ttls []interface{}
+	var clamped []int
+	stubClient := newStubClient(t, func(req *http.Request) string {
+		var params map[string]interface{}
+		_ = json.NewDecoder(req.Body).Decode(&params)
+		ttls = append(ttls, params["ttl"])
+		return `{"status":"Success"}`
+	}, EnforceTTL(TTLPolicy{Min: 300, Clamp: true, OnClamp: func(record Record, originalTTL int) {
+		assert.Equal(t, 300, record.TTL)
+		clamped = append(clamped, originalTTL)
+	}}))
+
+	// when
+	_, lowErr := stubClient.Records.Create(context.Background(), testDomain, NewRecordA("www", "192.0.2.1", 5))
+	_, highErr := stubClient.Records.Create(context.Background(), testDomain, NewRecordA("www", "192.0.2.1", 604800))
+
+	// then
+	assert.NoError(t, lowErr)
+	assert.NoError(t, highErr)
+	assert.Equal(t, []interface{}{300.0, 604800.0}, ttls, "ttl should only be clamped to configured bounds")
+	assert.Equal(t, []int{5}, clamped)
+}
+
+func TestEnforceTTL_Invalid(t *testing.T) {
+	_, err := New(EnforceTTL(TTLPolicy{Min: 3600, Max: 60}))
+	assert.ErrorIs(t, err, ErrInvalidOptions)
+
+	_, err = New(EnforceTTL(TTLPolicy{Min: -1}))
+	assert.ErrorIs(t, err, ErrInvalidOptions)
+}
@@ -0,0 +1,63 @@
+package cloudns
+
+import (
+	"runtime/debug"
+	"sync"
+)
+
+// modulePath is the Go module path of cloudns-go, used for looking up the version within the build information
+const modulePath = "github.com/ppmathis/cloudns-go"
+
+// develVersion is reported if the version of cloudns-go could not be determined, e.g. when running its own tests
+const develVersion = "(devel)"
+
+var (
+	versionOnce sync.Once
+	version     string
+)
+
+// Version returns the version of cloudns-go compiled into the current binary, e.g. `v1.2.3`, based on the build
+// information embedded by the Go toolchain. It returns `(devel)` if the version is unknown.
+func Version() string {
+	versionOnce.Do(func() {
+		version = versionFromBuildInfo(debug.ReadBuildInfo())
+	})
+
+	return version
+}
+
+func versionFromBuildInfo(info *debug.BuildInfo, ok bool) string {
+	if !ok || info == nil {
+		return develVersion
+	}
+
+	var module *debug.Module
+	if info.Main.Path == modulePath {
+		module = &info.Main
+	}
+	for _, dep := range info.Deps {
+		if dep.Path != modulePath {
+			continue
+		}
+
+		module = dep
+		if dep.Replace != nil && dep.Replace.Version != "" {
+			module = dep.Replace
+		}
+	}
+
+	if module == nil || module.Version == "" {
+		return develVersion
+	}
+
+	return module.Version
+}
+
+// defaultUserAgent returns the user agent sent by cloudns-go, including the version if known
+func defaultUserAgent() string {
+	if v := Version(); v != develVersion {
+		return "cloudns-go/" + v
+	}
+
+	return "cloudns-go"
+}
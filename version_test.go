@@ -0,0 +1,51 @@
+package cloudns
+
+import (
+	"github.com/stretchr/testify/assert"
+	"runtime/debug"
+	"testing"
+)
+
+func TestVersionFromBuildInfo(t *testing.T) {
+	tests := []struct {
+		name     string
+		info     *debug.BuildInfo
+		expected string
+	}{
+		{
+			name:     "missing build info",
+			expected: develVersion,
+		},
+		{
+			name:     "dependency",
+			info:     &debug.BuildInfo{Deps: []*debug.Module{{Path: "example.com/other", Version: "v0.1.0"}, {Path: modulePath, Version: "v1.2.3"}}},
+			expected: "v1.2.3",
+		},
+		{
+			name:     "replaced dependency",
+			info:     &debug.BuildInfo{Deps: []*debug.Module{{Path: modulePath, Version: "v1.2.3", Replace: &debug.Module{Path: "example.com/fork", Version: "v1.2.4"}}}},
+			expected: "v1.2.4",
+		},
+		{
+			name:     "main module",
+			info:     &debug.BuildInfo{Main: debug.Module{Path: modulePath, Version: develVersion}},
+			expected: develVersion,
+		},
+		{
+			name:     "unrelated binary",
+			info:     &debug.BuildInfo{Main: debug.Module{Path: "example.com/other", Version: "v0.1.0"}},
+			expected: develVersion,
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, versionFromBuildInfo(test.info, test.info != nil), test.name)
+	}
+}
+
+func TestDefaultUserAgent(t *testing.T) {
+	client, err := New()
+	assert.NoError(t, err)
+	assert.Equal(t, defaultUserAgent(), client.userAgent)
+	assert.NotContains(t, client.userAgent, develVersion)
+}